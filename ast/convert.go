@@ -0,0 +1,182 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnumName is the Go representation of an enum value, so that it isn't mistaken for a string
+// when converted back to a Value.
+type EnumName string
+
+// ValueToGo returns v as a Go value. Unlike Value.Value it keeps every detail of the literal:
+//
+//   - Ints are int64, or *big.Int when they don't fit in an int64
+//   - Floats are float64, Strings string and Booleans bool
+//   - enum values are EnumName
+//   - null is nil
+//   - lists are []interface{} and objects map[string]interface{}, even when empty
+//
+// Variables are looked up in vars and returned as they are, falling back to their default value.
+// Floats too large for a float64 are an error.
+func ValueToGo(v *Value, vars map[string]interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch v.Kind {
+	case Variable:
+		if value, ok := vars[v.Raw]; ok {
+			return value, nil
+		}
+		if v.VariableDefinition != nil && v.VariableDefinition.DefaultValue != nil {
+			return ValueToGo(v.VariableDefinition.DefaultValue, vars)
+		}
+		return nil, nil
+	case IntValue:
+		if i, err := strconv.ParseInt(v.Raw, 10, 64); err == nil {
+			return i, nil
+		}
+		i, ok := new(big.Int).SetString(v.Raw, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid Int %s", v.Raw)
+		}
+		return i, nil
+	case FloatValue:
+		f, err := strconv.ParseFloat(v.Raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Float %s: out of range", v.Raw)
+		}
+		return f, nil
+	case StringValue, BlockValue:
+		return v.Raw, nil
+	case BooleanValue:
+		return v.Raw == "true", nil
+	case NullValue:
+		return nil, nil
+	case EnumValue:
+		return EnumName(v.Raw), nil
+	case ListValue:
+		list := make([]interface{}, 0, len(v.Children))
+		for _, child := range v.Children {
+			value, err := ValueToGo(child.Value, vars)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+	case ObjectValue:
+		obj := make(map[string]interface{}, len(v.Children))
+		for _, child := range v.Children {
+			value, err := ValueToGo(child.Value, vars)
+			if err != nil {
+				return nil, err
+			}
+			obj[child.Name] = value
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("unknown value kind %d", v.Kind)
+	}
+}
+
+// GoToValue returns the literal of a Go value, the reverse of ValueToGo.
+//
+// Integers of any size, *big.Int and integral json.Numbers become Ints, floats and other
+// json.Numbers Floats, EnumName enum values, and nil and nil pointers null. Slices and arrays
+// become lists, and maps with string keys objects with their fields sorted by name. Pointers and
+// interfaces are followed. Other types, and floats that are infinite or NaN, are an error.
+func GoToValue(value interface{}) (*Value, error) {
+	switch value := value.(type) {
+	case nil:
+		return &Value{Kind: NullValue, Raw: "null"}, nil
+	case EnumName:
+		return &Value{Kind: EnumValue, Raw: string(value)}, nil
+	case *big.Int:
+		if value == nil {
+			return &Value{Kind: NullValue, Raw: "null"}, nil
+		}
+		return &Value{Kind: IntValue, Raw: value.String()}, nil
+	case json.Number:
+		if _, ok := new(big.Int).SetString(string(value), 10); ok {
+			return &Value{Kind: IntValue, Raw: string(value)}, nil
+		}
+		f, err := value.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", value)
+		}
+		return floatValue(f)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return &Value{Kind: BooleanValue, Raw: strconv.FormatBool(rv.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Value{Kind: IntValue, Raw: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Value{Kind: IntValue, Raw: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return floatValue(rv.Float())
+	case reflect.String:
+		return &Value{Kind: StringValue, Raw: rv.String()}, nil
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return &Value{Kind: NullValue, Raw: "null"}, nil
+		}
+		return GoToValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return &Value{Kind: NullValue, Raw: "null"}, nil
+		}
+		list := &Value{Kind: ListValue}
+		for i := 0; i < rv.Len(); i++ {
+			child, err := GoToValue(rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list.Children = append(list.Children, &ChildValue{Value: child})
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot convert %T to a value, object keys must be strings", value)
+		}
+		if rv.IsNil() {
+			return &Value{Kind: NullValue, Raw: "null"}, nil
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		obj := &Value{Kind: ObjectValue}
+		for _, key := range keys {
+			child, err := GoToValue(rv.MapIndex(key).Interface())
+			if err != nil {
+				return nil, err
+			}
+			obj.Children = append(obj.Children, &ChildValue{Name: key.String(), Value: child})
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to a value", value)
+	}
+}
+
+func floatValue(f float64) (*Value, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("cannot convert %v to a value", f)
+	}
+	raw := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(raw, ".e") {
+		// without a fraction or an exponent the literal would be an Int
+		raw += ".0"
+	}
+	return &Value{Kind: FloatValue, Raw: raw}, nil
+}
@@ -0,0 +1,103 @@
+package ast
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueToGo(t *testing.T) {
+	huge, _ := new(big.Int).SetString("92233720368547758070", 10)
+
+	value := &Value{Kind: ObjectValue, Children: ChildValueList{
+		{Name: "int", Value: &Value{Kind: IntValue, Raw: "-12"}},
+		{Name: "huge", Value: &Value{Kind: IntValue, Raw: "92233720368547758070"}},
+		{Name: "float", Value: &Value{Kind: FloatValue, Raw: "1.5e3"}},
+		{Name: "string", Value: &Value{Kind: StringValue, Raw: "ADMIN"}},
+		{Name: "block", Value: &Value{Kind: BlockValue, Raw: "text"}},
+		{Name: "bool", Value: &Value{Kind: BooleanValue, Raw: "true"}},
+		{Name: "null", Value: &Value{Kind: NullValue, Raw: "null"}},
+		{Name: "enum", Value: &Value{Kind: EnumValue, Raw: "ADMIN"}},
+		{Name: "empty", Value: &Value{Kind: ListValue}},
+		{Name: "nested", Value: &Value{Kind: ListValue, Children: ChildValueList{
+			{Value: &Value{Kind: ObjectValue}},
+			{Value: &Value{Kind: Variable, Raw: "a"}},
+			{Value: &Value{Kind: Variable, Raw: "b", VariableDefinition: &VariableDefinition{
+				DefaultValue: &Value{Kind: EnumValue, Raw: "B"},
+			}}},
+			{Value: &Value{Kind: Variable, Raw: "c"}},
+		}}},
+	}}
+
+	got, err := ValueToGo(value, map[string]interface{}{"a": "from vars"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"int":    int64(-12),
+		"huge":   huge,
+		"float":  1500.0,
+		"string": "ADMIN",
+		"block":  "text",
+		"bool":   true,
+		"null":   nil,
+		"enum":   EnumName("ADMIN"),
+		"empty":  []interface{}{},
+		"nested": []interface{}{map[string]interface{}{}, "from vars", EnumName("B"), nil},
+	}, got)
+
+	t.Run("round trips", func(t *testing.T) {
+		back, err := GoToValue(got)
+		require.NoError(t, err)
+		require.Equal(t, `{block:"text",bool:true,empty:[],enum:ADMIN,float:1500.0,huge:92233720368547758070,int:-12,nested:[{},"from vars",B,null],null:null,string:"ADMIN"}`, back.String())
+	})
+
+	t.Run("float out of range", func(t *testing.T) {
+		_, err := ValueToGo(&Value{Kind: FloatValue, Raw: "1e400"}, nil)
+		require.EqualError(t, err, "invalid Float 1e400: out of range")
+	})
+}
+
+func TestGoToValue(t *testing.T) {
+	str := "s"
+	var nilPtr *int
+	type Role string
+
+	for _, tc := range []struct {
+		in       interface{}
+		expected string
+		kind     ValueKind
+	}{
+		{nil, "null", NullValue},
+		{nilPtr, "null", NullValue},
+		{[]int(nil), "null", NullValue},
+		{true, "true", BooleanValue},
+		{int8(-3), "-3", IntValue},
+		{uint64(math.MaxUint64), "18446744073709551615", IntValue},
+		{json.Number("12345678901234567890123"), "12345678901234567890123", IntValue},
+		{json.Number("1.25"), "1.25", FloatValue},
+		{2.0, "2.0", FloatValue},
+		{float32(0.5), "0.5", FloatValue},
+		{1e21, "1e+21", FloatValue},
+		{&str, `"s"`, StringValue},
+		{Role("ADMIN"), `"ADMIN"`, StringValue},
+		{EnumName("ADMIN"), "ADMIN", EnumValue},
+		{[2]string{"a", "b"}, `["a","b"]`, ListValue},
+		{map[string]int{"b": 2, "a": 1}, "{a:1,b:2}", ObjectValue},
+	} {
+		v, err := GoToValue(tc.in)
+		require.NoError(t, err, "%#v", tc.in)
+		require.Equal(t, tc.expected, v.String(), "%#v", tc.in)
+		require.Equal(t, tc.kind, v.Kind, "%#v", tc.in)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := GoToValue(math.Inf(1))
+		require.EqualError(t, err, "cannot convert +Inf to a value")
+		_, err = GoToValue(map[int]string{})
+		require.EqualError(t, err, "cannot convert map[int]string to a value, object keys must be strings")
+		_, err = GoToValue([]interface{}{struct{}{}})
+		require.EqualError(t, err, "cannot convert struct {} to a value")
+	})
+}
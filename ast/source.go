@@ -8,6 +8,17 @@ type Source struct {
 	Input string
 	// BuiltIn indicate whether the source is a part of the specification
 	BuiltIn bool
+	// LocationOffset is the position of Input within the file called Name, for documents that
+	// are extracted from a larger file such as a query embedded in Go code. The zero value means
+	// Input starts at the beginning of the file.
+	LocationOffset LocationOffset
+}
+
+// LocationOffset is a 1 based line and column. The column offset only applies to the first line
+// of the source, as every following line starts at the beginning of a line in the file too.
+type LocationOffset struct {
+	Line   int
+	Column int
 }
 
 type Position struct {
@@ -0,0 +1,150 @@
+package astbin_test
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/astbin"
+	"github.com/vektah/gqlparser/v2/astpb"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const query = `
+query Q($id: ID! = "1", $tags: [String!] @deprecated) @live {
+	user(id: $id) {
+		id
+		name: fullName(format: {case: UPPER, parts: [FIRST, LAST]}, limit: 1.5)
+		... on Admin @include(if: true) { level }
+		...F @skip(if: false)
+	}
+}
+
+subscription { events { id } }
+
+fragment F on User {
+	friends(first: 10, after: null) { id name: fullName }
+}
+`
+
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+func parse(t *testing.T) *ast.QueryDocument {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+	require.NoError(t, err)
+	return doc
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc := parse(t)
+	b := astbin.MarshalQueryDocument(doc)
+
+	decoded, err := astbin.UnmarshalQueryDocument(b)
+	require.NoError(t, err)
+	require.Equal(t, format(doc), format(decoded))
+	require.Equal(t, ast.Subscription, decoded.Operations[1].Operation)
+
+	field := decoded.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet[1].(*ast.Field)
+	require.Equal(t, "name", field.Alias)
+	pos := *doc.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet[1].GetPosition()
+	pos.Src = field.Position.Src
+	require.Equal(t, pos, *field.Position)
+	require.Equal(t, "query.graphql", field.Position.Src.Name)
+	require.Empty(t, field.Position.Src.Input)
+	require.Same(t, field.Position.Src, decoded.Fragments[0].Position.Src)
+
+	value := field.Arguments.ForName("format").Value
+	require.Equal(t, "{case:UPPER,parts:[FIRST,LAST]}", value.String())
+	require.Equal(t, ast.FloatValue, field.Arguments.ForName("limit").Value.Kind)
+	require.Equal(t, ast.NullValue, decoded.Fragments[0].SelectionSet[0].(*ast.Field).Arguments[1].Value.Kind)
+
+	require.Less(t, len(b), len(astpb.MarshalQueryDocument(doc)), "names are only written once")
+
+	t.Run("empty documents", func(t *testing.T) {
+		decoded, err := astbin.UnmarshalQueryDocument(astbin.MarshalQueryDocument(nil))
+		require.NoError(t, err)
+		require.Equal(t, &ast.QueryDocument{}, decoded)
+	})
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	b := astbin.MarshalQueryDocument(parse(t))
+
+	t.Run("other versions", func(t *testing.T) {
+		_, err := astbin.UnmarshalQueryDocument([]byte("query { a }"))
+		require.ErrorIs(t, err, astbin.ErrUnsupportedVersion)
+
+		next := append([]byte("GQLB\x02"), b[5:]...)
+		_, err = astbin.UnmarshalQueryDocument(next)
+		require.ErrorIs(t, err, astbin.ErrUnsupportedVersion)
+		require.EqualError(t, err, "astbin: unsupported format version 2")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		for i := 0; i < len(b); i++ {
+			_, err := astbin.UnmarshalQueryDocument(b[:i])
+			require.Error(t, err, "truncated to %d bytes", i)
+		}
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		_, err := astbin.UnmarshalQueryDocument(append(b[:len(b):len(b)], 0))
+		require.EqualError(t, err, "astbin: unexpected data after the document")
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			corrupt := append([]byte(nil), b...)
+			for j := 0; j < 1+rnd.Intn(4); j++ {
+				corrupt[len("GQLB")+1+rnd.Intn(len(corrupt)-len("GQLB")-1)] = byte(rnd.Intn(256))
+			}
+			require.NotPanics(t, func() {
+				doc, err := astbin.UnmarshalQueryDocument(corrupt)
+				if err == nil {
+					// whatever decodes must be safe to use
+					format(doc)
+				}
+			})
+		}
+	})
+
+	t.Run("deeply nested", func(t *testing.T) {
+		typ := ast.NamedType("Int", nil)
+		for i := 0; i < 2000; i++ {
+			typ = ast.ListType(typ, nil)
+		}
+		doc := &ast.QueryDocument{Operations: ast.OperationList{{
+			Operation:           ast.Query,
+			VariableDefinitions: ast.VariableDefinitionList{{Variable: "v", Type: typ}},
+		}}}
+
+		_, err := astbin.UnmarshalQueryDocument(astbin.MarshalQueryDocument(doc))
+		require.EqualError(t, err, "astbin: nodes nested more than 1000 deep")
+	})
+
+	t.Run("missing parts", func(t *testing.T) {
+		doc := &ast.QueryDocument{Operations: ast.OperationList{{
+			SelectionSet: ast.SelectionSet{&ast.Field{Name: "a", Arguments: ast.ArgumentList{{Name: "x"}}}},
+		}}}
+		_, err := astbin.UnmarshalQueryDocument(astbin.MarshalQueryDocument(doc))
+		require.EqualError(t, err, "astbin: argument x has no value")
+
+		doc = &ast.QueryDocument{Operations: ast.OperationList{{
+			VariableDefinitions: ast.VariableDefinitionList{{Variable: "v"}},
+		}}}
+		_, err = astbin.UnmarshalQueryDocument(astbin.MarshalQueryDocument(doc))
+		require.EqualError(t, err, "astbin: type has no name")
+		require.False(t, errors.Is(err, astbin.ErrUnsupportedVersion))
+	})
+}
@@ -0,0 +1,359 @@
+package astbin
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ErrUnsupportedVersion is returned when decoding a document written by another version of the
+// format, or data that isn't an encoded document at all.
+var ErrUnsupportedVersion = errors.New("astbin: unsupported format version")
+
+var errTruncated = errors.New("astbin: unexpected end of input")
+
+// maxNesting bounds how deeply nodes can be nested in decoded input, so that hostile input is
+// rejected with an error instead of exhausting the stack.
+const maxNesting = 1000
+
+// UnmarshalQueryDocument decodes a document written by MarshalQueryDocument.
+//
+// Input that isn't a well formed document, including documents missing the parts the ast package
+// relies on such as the value of an argument, is reported as an error.
+func UnmarshalQueryDocument(b []byte) (*ast.QueryDocument, error) {
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return nil, ErrUnsupportedVersion
+	}
+	u := unmarshaler{buf: b[len(magic):]}
+	if version := u.uint(); u.err == nil && version != Version {
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, version)
+	}
+
+	u.strings = make([]string, u.count())
+	for i := range u.strings {
+		size := u.uint()
+		if size > len(u.buf) {
+			u.fail(errTruncated)
+			break
+		}
+		u.strings[i] = string(u.buf[:size])
+		u.buf = u.buf[size:]
+	}
+	u.sources = make([]*ast.Source, u.count())
+	for i := range u.sources {
+		u.sources[i] = &ast.Source{Name: u.string(), BuiltIn: u.bool()}
+	}
+
+	doc := u.document()
+	if u.err == nil && len(u.buf) > 0 {
+		u.fail(errors.New("astbin: unexpected data after the document"))
+	}
+	if u.err != nil {
+		return nil, u.err
+	}
+	return doc, nil
+}
+
+// unmarshaler reads the input, keeping the first error it runs into. Once an error is set every
+// read returns a zero value, so decoding finishes quickly and the error is checked once at the
+// end.
+type unmarshaler struct {
+	buf     []byte
+	err     error
+	depth   int
+	strings []string
+	sources []*ast.Source
+}
+
+func (u *unmarshaler) fail(err error) {
+	if u.err == nil {
+		u.err = err
+	}
+	u.buf = nil
+}
+
+func (u *unmarshaler) uint() int {
+	v, n := binary.Uvarint(u.buf)
+	if n <= 0 {
+		u.fail(errTruncated)
+		return 0
+	}
+	if v > math.MaxInt32 {
+		u.fail(fmt.Errorf("astbin: number %d out of range", v))
+		return 0
+	}
+	u.buf = u.buf[n:]
+	return int(v)
+}
+
+// count reads the number of items in a list. Every item takes at least one byte, so counts
+// larger than the rest of the input are rejected before anything is allocated for them.
+func (u *unmarshaler) count() int {
+	n := u.uint()
+	if n > len(u.buf) {
+		u.fail(errTruncated)
+		return 0
+	}
+	return n
+}
+
+func (u *unmarshaler) byte() byte {
+	if len(u.buf) == 0 {
+		u.fail(errTruncated)
+		return 0
+	}
+	b := u.buf[0]
+	u.buf = u.buf[1:]
+	return b
+}
+
+func (u *unmarshaler) bool() bool {
+	switch u.byte() {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		u.fail(errors.New("astbin: invalid boolean"))
+		return false
+	}
+}
+
+func (u *unmarshaler) string() string {
+	i := u.uint()
+	if i == 0 {
+		return ""
+	}
+	if i > len(u.strings) {
+		u.fail(fmt.Errorf("astbin: string %d out of range", i))
+		return ""
+	}
+	return u.strings[i-1]
+}
+
+// enter is called before decoding a node that can nest, and returns false if nodes are nested
+// too deeply. leave must be called after decoding the node.
+func (u *unmarshaler) enter() bool {
+	u.depth++
+	if u.depth > maxNesting {
+		u.fail(fmt.Errorf("astbin: nodes nested more than %d deep", maxNesting))
+		return false
+	}
+	return true
+}
+
+func (u *unmarshaler) leave() {
+	u.depth--
+}
+
+func (u *unmarshaler) position() *ast.Position {
+	if !u.bool() {
+		return nil
+	}
+	pos := &ast.Position{}
+	if source := u.uint(); source > 0 {
+		if source > len(u.sources) {
+			u.fail(fmt.Errorf("astbin: position refers to unknown source %d", source))
+			return nil
+		}
+		pos.Src = u.sources[source-1]
+	}
+	pos.Start = u.uint()
+	pos.End = u.uint()
+	pos.Line = u.uint()
+	pos.Column = u.uint()
+	return pos
+}
+
+func (u *unmarshaler) document() *ast.QueryDocument {
+	doc := &ast.QueryDocument{}
+
+	if n := u.count(); n > 0 {
+		doc.Operations = make(ast.OperationList, 0, n)
+		for i := 0; i < n && u.err == nil; i++ {
+			op := &ast.OperationDefinition{}
+			kind := int(u.byte())
+			if kind >= len(operations) {
+				u.fail(fmt.Errorf("astbin: unknown operation type %d", kind))
+				break
+			}
+			op.Operation = operations[kind]
+			op.Name = u.string()
+			op.VariableDefinitions = u.variableDefinitions()
+			op.Directives = u.directives()
+			op.SelectionSet = u.selectionSet()
+			op.Position = u.position()
+			doc.Operations = append(doc.Operations, op)
+		}
+	}
+
+	if n := u.count(); n > 0 {
+		doc.Fragments = make(ast.FragmentDefinitionList, 0, n)
+		for i := 0; i < n && u.err == nil; i++ {
+			frag := &ast.FragmentDefinition{}
+			frag.Name = u.string()
+			frag.VariableDefinition = u.variableDefinitions()
+			frag.TypeCondition = u.string()
+			frag.Directives = u.directives()
+			frag.SelectionSet = u.selectionSet()
+			frag.Position = u.position()
+			doc.Fragments = append(doc.Fragments, frag)
+		}
+	}
+
+	doc.Position = u.position()
+	return doc
+}
+
+func (u *unmarshaler) variableDefinitions() ast.VariableDefinitionList {
+	n := u.count()
+	if n == 0 {
+		return nil
+	}
+	defs := make(ast.VariableDefinitionList, 0, n)
+	for i := 0; i < n && u.err == nil; i++ {
+		def := &ast.VariableDefinition{}
+		def.Variable = u.string()
+		def.Type = u.typ()
+		def.DefaultValue = u.value()
+		def.Directives = u.directives()
+		def.Position = u.position()
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+func (u *unmarshaler) typ() *ast.Type {
+	if !u.enter() {
+		return nil
+	}
+	defer u.leave()
+
+	t := &ast.Type{}
+	flags := u.byte()
+	if flags&^(typeList|typeNonNull) != 0 {
+		u.fail(fmt.Errorf("astbin: invalid type flags %d", flags))
+		return nil
+	}
+	t.NonNull = flags&typeNonNull != 0
+	if flags&typeList != 0 {
+		t.Elem = u.typ()
+	} else if t.NamedType = u.string(); t.NamedType == "" {
+		u.fail(errors.New("astbin: type has no name"))
+		return nil
+	}
+	t.Position = u.position()
+	return t
+}
+
+func (u *unmarshaler) selectionSet() ast.SelectionSet {
+	if !u.enter() {
+		return nil
+	}
+	defer u.leave()
+
+	n := u.count()
+	if n == 0 {
+		return nil
+	}
+	set := make(ast.SelectionSet, 0, n)
+	for i := 0; i < n && u.err == nil; i++ {
+		switch tag := u.byte(); tag {
+		case tagNone:
+		case tagField:
+			f := &ast.Field{}
+			f.Alias = u.string()
+			f.Name = u.string()
+			f.Arguments = u.arguments()
+			f.Directives = u.directives()
+			f.SelectionSet = u.selectionSet()
+			f.Position = u.position()
+			set = append(set, f)
+		case tagFragmentSpread:
+			spread := &ast.FragmentSpread{}
+			spread.Name = u.string()
+			spread.Directives = u.directives()
+			spread.Position = u.position()
+			set = append(set, spread)
+		case tagInlineFragment:
+			inline := &ast.InlineFragment{}
+			inline.TypeCondition = u.string()
+			inline.Directives = u.directives()
+			inline.SelectionSet = u.selectionSet()
+			inline.Position = u.position()
+			set = append(set, inline)
+		default:
+			u.fail(fmt.Errorf("astbin: unknown selection %d", tag))
+		}
+	}
+	return set
+}
+
+func (u *unmarshaler) arguments() ast.ArgumentList {
+	n := u.count()
+	if n == 0 {
+		return nil
+	}
+	args := make(ast.ArgumentList, 0, n)
+	for i := 0; i < n && u.err == nil; i++ {
+		arg := &ast.Argument{}
+		arg.Name = u.string()
+		if arg.Value = u.value(); arg.Value == nil {
+			u.fail(fmt.Errorf("astbin: argument %s has no value", arg.Name))
+		}
+		arg.Position = u.position()
+		args = append(args, arg)
+	}
+	return args
+}
+
+func (u *unmarshaler) directives() ast.DirectiveList {
+	n := u.count()
+	if n == 0 {
+		return nil
+	}
+	directives := make(ast.DirectiveList, 0, n)
+	for i := 0; i < n && u.err == nil; i++ {
+		dir := &ast.Directive{}
+		dir.Name = u.string()
+		dir.Arguments = u.arguments()
+		dir.Position = u.position()
+		directives = append(directives, dir)
+	}
+	return directives
+}
+
+func (u *unmarshaler) value() *ast.Value {
+	kind := u.byte()
+	if kind == 0 {
+		return nil
+	}
+	if !u.enter() {
+		return nil
+	}
+	defer u.leave()
+
+	v := &ast.Value{Kind: ast.ValueKind(kind - 1)}
+	if v.Kind > ast.ObjectValue {
+		u.fail(fmt.Errorf("astbin: unknown value kind %d", v.Kind))
+		return nil
+	}
+	v.Raw = u.string()
+	if n := u.count(); n > 0 {
+		v.Children = make(ast.ChildValueList, 0, n)
+		for i := 0; i < n && u.err == nil; i++ {
+			child := &ast.ChildValue{}
+			child.Name = u.string()
+			if child.Value = u.value(); child.Value == nil {
+				u.fail(errors.New("astbin: list item or object field has no value"))
+			}
+			child.Position = u.position()
+			v.Children = append(v.Children, child)
+		}
+	}
+	v.Position = u.position()
+	return v
+}
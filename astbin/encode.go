@@ -0,0 +1,268 @@
+// Package astbin encodes parsed query documents in a compact binary format, for caching them
+// across processes in Redis, memcached and the like instead of parsing the same queries again.
+//
+// The format is versioned. Entries written by another version of the format fail to decode with
+// ErrUnsupportedVersion, so caches can treat them as misses. Decoding checks the structure of the
+// input and never panics, so a corrupt entry is an error rather than a crash.
+//
+// Unlike astpb the format is only meant to be read by this package, and favours size and speed
+// over compatibility: names are written once in a string table and referred to by index, and
+// nodes are written in a fixed order without tags.
+package astbin
+
+import (
+	"encoding/binary"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Version is the version of the format written by MarshalQueryDocument.
+const Version = 1
+
+// magic starts every encoded document.
+const magic = "GQLB"
+
+// node tags, used where a node can be of several kinds or absent
+const (
+	tagNone = iota
+	tagField
+	tagFragmentSpread
+	tagInlineFragment
+)
+
+// type flags
+const (
+	typeList = 1 << iota
+	typeNonNull
+)
+
+var operations = []ast.Operation{"", ast.Query, ast.Mutation, ast.Subscription}
+
+// MarshalQueryDocument encodes doc.
+//
+// Positions are kept, along with the names of their sources, but not the text of the sources.
+// Comments and the results of validation are left out, so the decoded document should be
+// validated again before use.
+func MarshalQueryDocument(doc *ast.QueryDocument) []byte {
+	m := marshaler{
+		strings: map[string]int{},
+		sources: map[*ast.Source]int{},
+	}
+	if doc == nil {
+		doc = &ast.QueryDocument{}
+	}
+	m.document(doc)
+
+	// the body refers to the string table and sources by index, so they are written before it
+	// once the body is complete.
+	out := make([]byte, 0, len(magic)+len(m.body)+16*len(m.stringList))
+	out = append(out, magic...)
+	out = binary.AppendUvarint(out, Version)
+	out = binary.AppendUvarint(out, uint64(len(m.stringList)))
+	for _, s := range m.stringList {
+		out = binary.AppendUvarint(out, uint64(len(s)))
+		out = append(out, s...)
+	}
+	out = binary.AppendUvarint(out, uint64(len(m.sourceList)))
+	for _, src := range m.sourceList {
+		out = binary.AppendUvarint(out, uint64(m.stringIndex(src.Name)))
+		out = appendBool(out, src.BuiltIn)
+	}
+	return append(out, m.body...)
+}
+
+type marshaler struct {
+	body []byte
+	// strings holds the 1 based index of every string in stringList, 0 is the empty string
+	strings    map[string]int
+	stringList []string
+	// sources holds the 1 based index of every source in sourceList, 0 is no source
+	sources    map[*ast.Source]int
+	sourceList []*ast.Source
+}
+
+func (m *marshaler) uint(v int) {
+	m.body = binary.AppendUvarint(m.body, uint64(v))
+}
+
+func (m *marshaler) byte(b byte) {
+	m.body = append(m.body, b)
+}
+
+func (m *marshaler) string(s string) {
+	m.uint(m.stringIndex(s))
+}
+
+func (m *marshaler) stringIndex(s string) int {
+	if s == "" {
+		return 0
+	}
+	i, ok := m.strings[s]
+	if !ok {
+		m.stringList = append(m.stringList, s)
+		i = len(m.stringList)
+		m.strings[s] = i
+	}
+	return i
+}
+
+func (m *marshaler) position(pos *ast.Position) {
+	if pos == nil {
+		m.byte(0)
+		return
+	}
+	source := 0
+	if pos.Src != nil {
+		source = m.sources[pos.Src]
+		if source == 0 {
+			m.sourceList = append(m.sourceList, pos.Src)
+			source = len(m.sourceList)
+			m.sources[pos.Src] = source
+		}
+		// make sure the name is in the string table even if nothing else uses it
+		m.stringIndex(pos.Src.Name)
+	}
+	m.byte(1)
+	m.uint(source)
+	m.uint(pos.Start)
+	m.uint(pos.End)
+	m.uint(pos.Line)
+	m.uint(pos.Column)
+}
+
+func (m *marshaler) document(doc *ast.QueryDocument) {
+	m.uint(len(doc.Operations))
+	for _, op := range doc.Operations {
+		kind := 0
+		for i, operation := range operations {
+			if op.Operation == operation {
+				kind = i
+			}
+		}
+		m.byte(byte(kind))
+		m.string(op.Name)
+		m.variableDefinitions(op.VariableDefinitions)
+		m.directives(op.Directives)
+		m.selectionSet(op.SelectionSet)
+		m.position(op.Position)
+	}
+
+	m.uint(len(doc.Fragments))
+	for _, frag := range doc.Fragments {
+		m.string(frag.Name)
+		m.variableDefinitions(frag.VariableDefinition)
+		m.string(frag.TypeCondition)
+		m.directives(frag.Directives)
+		m.selectionSet(frag.SelectionSet)
+		m.position(frag.Position)
+	}
+
+	m.position(doc.Position)
+}
+
+func (m *marshaler) variableDefinitions(defs ast.VariableDefinitionList) {
+	m.uint(len(defs))
+	for _, def := range defs {
+		m.string(def.Variable)
+		m.typ(def.Type)
+		m.value(def.DefaultValue)
+		m.directives(def.Directives)
+		m.position(def.Position)
+	}
+}
+
+func (m *marshaler) typ(t *ast.Type) {
+	if t == nil {
+		// written as a type without a name, which fails to decode
+		m.byte(0)
+		m.string("")
+		m.position(nil)
+		return
+	}
+	var flags byte
+	if t.Elem != nil {
+		flags |= typeList
+	}
+	if t.NonNull {
+		flags |= typeNonNull
+	}
+	m.byte(flags)
+	if t.Elem != nil {
+		m.typ(t.Elem)
+	} else {
+		m.string(t.NamedType)
+	}
+	m.position(t.Position)
+}
+
+func (m *marshaler) selectionSet(set ast.SelectionSet) {
+	m.uint(len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			m.byte(tagField)
+			m.string(sel.Alias)
+			m.string(sel.Name)
+			m.arguments(sel.Arguments)
+			m.directives(sel.Directives)
+			m.selectionSet(sel.SelectionSet)
+			m.position(sel.Position)
+		case *ast.FragmentSpread:
+			m.byte(tagFragmentSpread)
+			m.string(sel.Name)
+			m.directives(sel.Directives)
+			m.position(sel.Position)
+		case *ast.InlineFragment:
+			m.byte(tagInlineFragment)
+			m.string(sel.TypeCondition)
+			m.directives(sel.Directives)
+			m.selectionSet(sel.SelectionSet)
+			m.position(sel.Position)
+		default:
+			// nil selections keep the count right, and are dropped when decoding
+			m.byte(tagNone)
+		}
+	}
+}
+
+func (m *marshaler) arguments(args ast.ArgumentList) {
+	m.uint(len(args))
+	for _, arg := range args {
+		m.string(arg.Name)
+		m.value(arg.Value)
+		m.position(arg.Position)
+	}
+}
+
+func (m *marshaler) directives(directives ast.DirectiveList) {
+	m.uint(len(directives))
+	for _, dir := range directives {
+		m.string(dir.Name)
+		m.arguments(dir.Arguments)
+		m.position(dir.Position)
+	}
+}
+
+// value writes the kind of v plus one, so that 0 stands for no value.
+func (m *marshaler) value(v *ast.Value) {
+	if v == nil {
+		m.byte(0)
+		return
+	}
+	m.byte(byte(v.Kind) + 1)
+	m.string(v.Raw)
+	m.uint(len(v.Children))
+	for _, child := range v.Children {
+		m.string(child.Name)
+		m.value(child.Value)
+		m.position(child.Position)
+	}
+	m.position(v.Position)
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
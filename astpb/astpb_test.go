@@ -0,0 +1,141 @@
+package astpb_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/astpb"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+const query = `
+query Q($id: ID! = "1", $tags: [String!] @deprecated) @live {
+	user(id: $id) {
+		id
+		name: fullName(format: {case: UPPER, parts: [FIRST, LAST]}, limit: 1.5)
+		... on Admin @include(if: true) { level }
+		...F @skip(if: false)
+	}
+}
+
+fragment F on User {
+	friends(first: 10, after: null) { id }
+}
+`
+
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+func TestRoundTrip(t *testing.T) {
+	src := &ast.Source{Name: "query.graphql", Input: query}
+	doc, err := parser.ParseQuery(src)
+	require.NoError(t, err)
+
+	decoded, err := astpb.UnmarshalQueryDocument(astpb.MarshalQueryDocument(doc))
+	require.NoError(t, err)
+	require.Equal(t, format(doc), format(decoded))
+
+	field := decoded.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet[1].(*ast.Field)
+	require.Equal(t, "name", field.Alias)
+	require.Equal(t, 5, field.Position.Line)
+	require.Equal(t, 3, field.Position.Column)
+	require.Equal(t, "query.graphql", field.Position.Src.Name)
+	require.Empty(t, field.Position.Src.Input)
+	require.Same(t, field.Position.Src, decoded.Fragments[0].Position.Src)
+
+	value := field.Arguments.ForName("format").Value
+	require.Equal(t, ast.ObjectValue, value.Kind)
+	require.Equal(t, "{case:UPPER,parts:[FIRST,LAST]}", value.String())
+	require.Equal(t, ast.FloatValue, field.Arguments.ForName("limit").Value.Kind)
+	require.Equal(t, ast.NullValue, decoded.Fragments[0].SelectionSet[0].(*ast.Field).Arguments[1].Value.Kind)
+}
+
+func TestWireFormat(t *testing.T) {
+	doc := &ast.QueryDocument{Operations: ast.OperationList{{
+		Operation:    ast.Query,
+		SelectionSet: ast.SelectionSet{&ast.Field{Alias: "a", Name: "a"}},
+	}}}
+
+	b := astpb.MarshalQueryDocument(doc)
+	require.Equal(t, "0a11"+"0a057175657279"+"2a08"+"0a06"+"0a0161"+"120161", hex.EncodeToString(b))
+
+	decoded, err := astpb.UnmarshalQueryDocument(b)
+	require.NoError(t, err)
+	require.Equal(t, doc, decoded)
+
+	t.Run("unknown fields are skipped", func(t *testing.T) {
+		extra := append([]byte{0x78, 0x05, 0x7d, 1, 2, 3, 4}, b...)
+		decoded, err := astpb.UnmarshalQueryDocument(extra)
+		require.NoError(t, err)
+		require.Equal(t, doc, decoded)
+	})
+}
+
+func TestMalformed(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	require.NoError(t, err)
+	b := astpb.MarshalQueryDocument(doc)
+
+	t.Run("truncated input never panics", func(t *testing.T) {
+		for i := range b {
+			_, _ = astpb.UnmarshalQueryDocument(b[:i])
+		}
+	})
+
+	t.Run("corrupted input never panics", func(t *testing.T) {
+		for i := range b {
+			corrupt := append([]byte(nil), b...)
+			corrupt[i] ^= 0xff
+			_, _ = astpb.UnmarshalQueryDocument(corrupt)
+		}
+	})
+
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"truncated":           {"0a11", "astpb: unexpected end of input"},
+		"empty selection":     {"0a02" + "2a00", "astpb: selection is not a field, fragment spread or inline fragment"},
+		"argument with value": {"0a06" + "2a04" + "0a02" + "1a00", "astpb: argument  has no value"},
+		"bad value kind":      {"0a0a" + "2a08" + "0a06" + "1a04" + "1202" + "0863", "astpb: unknown value kind 99"},
+		"bad source":          {"2202" + "2801", "astpb: position refers to unknown source 1"},
+		"bad wire type":       {"0d00000000", "astpb: unexpected wire type 5"},
+		"field zero":          {"0000", "astpb: invalid field number 0"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			input, err := hex.DecodeString(tt.input)
+			require.NoError(t, err)
+			_, err = astpb.UnmarshalQueryDocument(input)
+			require.EqualError(t, err, tt.err)
+		})
+	}
+
+	t.Run("deep nesting", func(t *testing.T) {
+		// a type nested in itself 2000 times
+		var typ []byte
+		typ = []byte{0x0a, 0x01, 'T'}
+		for i := 0; i < 2000; i++ {
+			typ = append(append([]byte{0x12}, uvarint(len(typ))...), typ...)
+		}
+		_, err := astpb.UnmarshalQueryDocument(typ)
+		require.Error(t, err)
+	})
+}
+
+func uvarint(n int) []byte {
+	var b []byte
+	for n >= 0x80 {
+		b = append(b, byte(n)|0x80)
+		n >>= 7
+	}
+	return append(b, byte(n))
+}
@@ -0,0 +1,354 @@
+package astpb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// UnmarshalQueryDocument decodes a QueryDocument message written by MarshalQueryDocument or by
+// code generated from ast.proto. Unknown fields are skipped.
+//
+// Malformed input, including messages missing the parts the ast package relies on such as the
+// value of an argument, is reported as an error. The document should be validated against a
+// schema before use.
+func UnmarshalQueryDocument(b []byte) (*ast.QueryDocument, error) {
+	u := unmarshaler{}
+	doc := &ast.QueryDocument{}
+	err := decodeMessage(&decoder{buf: b}, func(d *decoder, field int, wireType int) error {
+		switch field {
+		case 1:
+			op := &ast.OperationDefinition{}
+			doc.Operations = append(doc.Operations, op)
+			return d.message(wireType, u.operation(op))
+		case 2:
+			frag := &ast.FragmentDefinition{}
+			doc.Fragments = append(doc.Fragments, frag)
+			return d.message(wireType, u.fragment(frag))
+		case 3:
+			src := &ast.Source{}
+			u.sources = append(u.sources, src)
+			return d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+				switch field {
+				case 1:
+					src.Name, err = d.string(wireType)
+				case 2:
+					src.BuiltIn, err = d.bool(wireType)
+				default:
+					err = d.skip(wireType)
+				}
+				return err
+			})
+		case 4:
+			return u.position(d, wireType, &doc.Position)
+		default:
+			return d.skip(wireType)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pos := range u.positions {
+		if pos.source > len(u.sources) || pos.source < 0 {
+			return nil, fmt.Errorf("astpb: position refers to unknown source %d", pos.source)
+		}
+		if pos.source > 0 {
+			pos.Src = u.sources[pos.source-1]
+		}
+	}
+	return doc, nil
+}
+
+type unmarshaler struct {
+	sources []*ast.Source
+	// positions are linked to their source once every source has been read
+	positions []position
+}
+
+type position struct {
+	*ast.Position
+	source int
+}
+
+type fieldFunc = func(d *decoder, field int, wireType int) error
+
+func (u *unmarshaler) position(d *decoder, wireType int, pos **ast.Position) error {
+	p := position{Position: &ast.Position{}}
+	*pos = p.Position
+	err := d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			p.Start, err = d.int(wireType)
+		case 2:
+			p.End, err = d.int(wireType)
+		case 3:
+			p.Line, err = d.int(wireType)
+		case 4:
+			p.Column, err = d.int(wireType)
+		case 5:
+			p.source, err = d.int(wireType)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	})
+	u.positions = append(u.positions, p)
+	return err
+}
+
+func (u *unmarshaler) operation(op *ast.OperationDefinition) fieldFunc {
+	return func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			var operation string
+			operation, err = d.string(wireType)
+			op.Operation = ast.Operation(operation)
+		case 2:
+			op.Name, err = d.string(wireType)
+		case 3:
+			err = u.variableDefinition(d, wireType, &op.VariableDefinitions)
+		case 4:
+			err = u.directive(d, wireType, &op.Directives)
+		case 5:
+			err = u.selection(d, wireType, &op.SelectionSet)
+		case 6:
+			err = u.position(d, wireType, &op.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	}
+}
+
+func (u *unmarshaler) fragment(frag *ast.FragmentDefinition) fieldFunc {
+	return func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			frag.Name, err = d.string(wireType)
+		case 2:
+			err = u.variableDefinition(d, wireType, &frag.VariableDefinition)
+		case 3:
+			frag.TypeCondition, err = d.string(wireType)
+		case 4:
+			err = u.directive(d, wireType, &frag.Directives)
+		case 5:
+			err = u.selection(d, wireType, &frag.SelectionSet)
+		case 6:
+			err = u.position(d, wireType, &frag.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	}
+}
+
+func (u *unmarshaler) variableDefinition(d *decoder, wireType int, list *ast.VariableDefinitionList) error {
+	def := &ast.VariableDefinition{}
+	*list = append(*list, def)
+	err := d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			def.Variable, err = d.string(wireType)
+		case 2:
+			err = u.typ(d, wireType, &def.Type)
+		case 3:
+			err = u.value(d, wireType, &def.DefaultValue)
+		case 4:
+			err = u.directive(d, wireType, &def.Directives)
+		case 5:
+			err = u.position(d, wireType, &def.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	})
+	if err == nil && def.Type == nil {
+		return fmt.Errorf("astpb: variable $%s has no type", def.Variable)
+	}
+	return err
+}
+
+func (u *unmarshaler) selection(d *decoder, wireType int, set *ast.SelectionSet) error {
+	var sel ast.Selection
+	err := d.message(wireType, func(d *decoder, field int, wireType int) error {
+		switch field {
+		case 1:
+			f := &ast.Field{}
+			sel = f
+			return d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+				switch field {
+				case 1:
+					f.Alias, err = d.string(wireType)
+				case 2:
+					f.Name, err = d.string(wireType)
+				case 3:
+					err = u.argument(d, wireType, &f.Arguments)
+				case 4:
+					err = u.directive(d, wireType, &f.Directives)
+				case 5:
+					err = u.selection(d, wireType, &f.SelectionSet)
+				case 6:
+					err = u.position(d, wireType, &f.Position)
+				default:
+					err = d.skip(wireType)
+				}
+				return err
+			})
+		case 2:
+			spread := &ast.FragmentSpread{}
+			sel = spread
+			return d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+				switch field {
+				case 1:
+					spread.Name, err = d.string(wireType)
+				case 2:
+					err = u.directive(d, wireType, &spread.Directives)
+				case 3:
+					err = u.position(d, wireType, &spread.Position)
+				default:
+					err = d.skip(wireType)
+				}
+				return err
+			})
+		case 3:
+			inline := &ast.InlineFragment{}
+			sel = inline
+			return d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+				switch field {
+				case 1:
+					inline.TypeCondition, err = d.string(wireType)
+				case 2:
+					err = u.directive(d, wireType, &inline.Directives)
+				case 3:
+					err = u.selection(d, wireType, &inline.SelectionSet)
+				case 4:
+					err = u.position(d, wireType, &inline.Position)
+				default:
+					err = d.skip(wireType)
+				}
+				return err
+			})
+		default:
+			return d.skip(wireType)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if sel == nil {
+		return errors.New("astpb: selection is not a field, fragment spread or inline fragment")
+	}
+	*set = append(*set, sel)
+	return nil
+}
+
+func (u *unmarshaler) argument(d *decoder, wireType int, list *ast.ArgumentList) error {
+	arg := &ast.Argument{}
+	*list = append(*list, arg)
+	err := d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			arg.Name, err = d.string(wireType)
+		case 2:
+			err = u.value(d, wireType, &arg.Value)
+		case 3:
+			err = u.position(d, wireType, &arg.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	})
+	if err == nil && arg.Value == nil {
+		return fmt.Errorf("astpb: argument %s has no value", arg.Name)
+	}
+	return err
+}
+
+func (u *unmarshaler) directive(d *decoder, wireType int, list *ast.DirectiveList) error {
+	dir := &ast.Directive{}
+	*list = append(*list, dir)
+	return d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			dir.Name, err = d.string(wireType)
+		case 2:
+			err = u.argument(d, wireType, &dir.Arguments)
+		case 3:
+			err = u.position(d, wireType, &dir.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	})
+}
+
+func (u *unmarshaler) value(d *decoder, wireType int, value **ast.Value) error {
+	v := &ast.Value{}
+	*value = v
+	err := d.message(wireType, func(d *decoder, field int, wireType int) error {
+		switch field {
+		case 1:
+			kind, err := d.int(wireType)
+			v.Kind = ast.ValueKind(kind)
+			return err
+		case 2:
+			raw, err := d.string(wireType)
+			v.Raw = raw
+			return err
+		case 3:
+			child := &ast.ChildValue{}
+			v.Children = append(v.Children, child)
+			err := d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+				switch field {
+				case 1:
+					child.Name, err = d.string(wireType)
+				case 2:
+					err = u.value(d, wireType, &child.Value)
+				case 3:
+					err = u.position(d, wireType, &child.Position)
+				default:
+					err = d.skip(wireType)
+				}
+				return err
+			})
+			if err == nil && child.Value == nil {
+				return errors.New("astpb: list item or object field has no value")
+			}
+			return err
+		case 4:
+			return u.position(d, wireType, &v.Position)
+		default:
+			return d.skip(wireType)
+		}
+	})
+	if err == nil && (v.Kind < ast.Variable || v.Kind > ast.ObjectValue) {
+		return fmt.Errorf("astpb: unknown value kind %d", v.Kind)
+	}
+	return err
+}
+
+func (u *unmarshaler) typ(d *decoder, wireType int, typ **ast.Type) error {
+	t := &ast.Type{}
+	*typ = t
+	err := d.message(wireType, func(d *decoder, field int, wireType int) (err error) {
+		switch field {
+		case 1:
+			t.NamedType, err = d.string(wireType)
+		case 2:
+			err = u.typ(d, wireType, &t.Elem)
+		case 3:
+			t.NonNull, err = d.bool(wireType)
+		case 4:
+			err = u.position(d, wireType, &t.Position)
+		default:
+			err = d.skip(wireType)
+		}
+		return err
+	})
+	if err == nil && t.NamedType == "" && t.Elem == nil {
+		return errors.New("astpb: type is neither named nor a list")
+	}
+	return err
+}
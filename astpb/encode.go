@@ -0,0 +1,181 @@
+// Package astpb encodes parsed query documents in the protocol buffers format described by
+// ast.proto, so that services can pass them around without printing and parsing them again.
+//
+// The encoding only depends on the standard library. Services in other languages can generate
+// their own code from ast.proto.
+package astpb
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// MarshalQueryDocument encodes doc as a QueryDocument message.
+//
+// Positions are kept, along with the names of their sources, but not the text of the sources.
+// Comments and the results of validation are left out.
+func MarshalQueryDocument(doc *ast.QueryDocument) []byte {
+	m := marshaler{sources: map[*ast.Source]int{}}
+	m.document(doc)
+	return m.buf
+}
+
+type marshaler struct {
+	encoder
+	// sources holds the 1 based index of every source written so far
+	sources     map[*ast.Source]int
+	sourceNames []*ast.Source
+}
+
+func (m *marshaler) document(doc *ast.QueryDocument) {
+	if doc == nil {
+		return
+	}
+	for _, op := range doc.Operations {
+		m.message(1, func(*encoder) { m.operation(op) })
+	}
+	for _, frag := range doc.Fragments {
+		m.message(2, func(*encoder) { m.fragment(frag) })
+	}
+	m.position(4, doc.Position)
+
+	// sources are written last, once every position has been seen.
+	for _, src := range m.sourceNames {
+		m.message(3, func(*encoder) {
+			m.string(1, src.Name)
+			m.bool(2, src.BuiltIn)
+		})
+	}
+}
+
+func (m *marshaler) position(field int, pos *ast.Position) {
+	if pos == nil {
+		return
+	}
+	source := 0
+	if pos.Src != nil {
+		source = m.sources[pos.Src]
+		if source == 0 {
+			m.sourceNames = append(m.sourceNames, pos.Src)
+			source = len(m.sourceNames)
+			m.sources[pos.Src] = source
+		}
+	}
+	m.message(field, func(*encoder) {
+		m.int(1, pos.Start)
+		m.int(2, pos.End)
+		m.int(3, pos.Line)
+		m.int(4, pos.Column)
+		m.int(5, source)
+	})
+}
+
+func (m *marshaler) operation(op *ast.OperationDefinition) {
+	m.string(1, string(op.Operation))
+	m.string(2, op.Name)
+	m.variableDefinitions(3, op.VariableDefinitions)
+	m.directives(4, op.Directives)
+	m.selectionSet(5, op.SelectionSet)
+	m.position(6, op.Position)
+}
+
+func (m *marshaler) fragment(frag *ast.FragmentDefinition) {
+	m.string(1, frag.Name)
+	m.variableDefinitions(2, frag.VariableDefinition)
+	m.string(3, frag.TypeCondition)
+	m.directives(4, frag.Directives)
+	m.selectionSet(5, frag.SelectionSet)
+	m.position(6, frag.Position)
+}
+
+func (m *marshaler) variableDefinitions(field int, defs ast.VariableDefinitionList) {
+	for _, def := range defs {
+		m.message(field, func(*encoder) {
+			m.string(1, def.Variable)
+			m.typ(2, def.Type)
+			m.value(3, def.DefaultValue)
+			m.directives(4, def.Directives)
+			m.position(5, def.Position)
+		})
+	}
+}
+
+func (m *marshaler) selectionSet(field int, set ast.SelectionSet) {
+	for _, sel := range set {
+		m.message(field, func(*encoder) {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				m.message(1, func(*encoder) {
+					m.string(1, sel.Alias)
+					m.string(2, sel.Name)
+					m.arguments(3, sel.Arguments)
+					m.directives(4, sel.Directives)
+					m.selectionSet(5, sel.SelectionSet)
+					m.position(6, sel.Position)
+				})
+			case *ast.FragmentSpread:
+				m.message(2, func(*encoder) {
+					m.string(1, sel.Name)
+					m.directives(2, sel.Directives)
+					m.position(3, sel.Position)
+				})
+			case *ast.InlineFragment:
+				m.message(3, func(*encoder) {
+					m.string(1, sel.TypeCondition)
+					m.directives(2, sel.Directives)
+					m.selectionSet(3, sel.SelectionSet)
+					m.position(4, sel.Position)
+				})
+			}
+		})
+	}
+}
+
+func (m *marshaler) arguments(field int, args ast.ArgumentList) {
+	for _, arg := range args {
+		m.message(field, func(*encoder) {
+			m.string(1, arg.Name)
+			m.value(2, arg.Value)
+			m.position(3, arg.Position)
+		})
+	}
+}
+
+func (m *marshaler) directives(field int, directives ast.DirectiveList) {
+	for _, dir := range directives {
+		m.message(field, func(*encoder) {
+			m.string(1, dir.Name)
+			m.arguments(2, dir.Arguments)
+			m.position(3, dir.Position)
+		})
+	}
+}
+
+func (m *marshaler) value(field int, value *ast.Value) {
+	if value == nil {
+		return
+	}
+	m.message(field, func(*encoder) {
+		m.int(1, int(value.Kind))
+		m.string(2, value.Raw)
+		for _, child := range value.Children {
+			m.message(3, func(*encoder) {
+				m.string(1, child.Name)
+				m.value(2, child.Value)
+				m.position(3, child.Position)
+			})
+		}
+		m.position(4, value.Position)
+	})
+}
+
+func (m *marshaler) typ(field int, typ *ast.Type) {
+	if typ == nil {
+		return
+	}
+	m.message(field, func(*encoder) {
+		m.string(1, typ.NamedType)
+		m.typ(2, typ.Elem)
+		m.bool(3, typ.NonNull)
+		m.position(4, typ.Position)
+	})
+}
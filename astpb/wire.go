@@ -0,0 +1,191 @@
+package astpb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// wire types of the protocol buffers encoding
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// maxNesting bounds how deeply messages can be nested in decoded input, so that hostile input is
+// rejected with an error instead of exhausting the stack.
+const maxNesting = 1000
+
+var errTruncated = errors.New("astpb: unexpected end of input")
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field int, wireType int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wireType))
+}
+
+// int encodes an int32 field, leaving out the default value.
+func (e *encoder) int(field int, v int) {
+	if v == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, uint64(int64(int32(v))))
+}
+
+func (e *encoder) bool(field int, v bool) {
+	if !v {
+		return
+	}
+	e.tag(field, wireVarint)
+	e.buf = append(e.buf, 1)
+}
+
+func (e *encoder) string(field int, s string) {
+	if s == "" {
+		return
+	}
+	e.tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// message encodes the message written by fn as a field. The length of the message is not known
+// until it has been written, so it is written to the end of the buffer and moved into place.
+func (e *encoder) message(field int, fn func(e *encoder)) {
+	e.tag(field, wireBytes)
+	start := len(e.buf)
+	fn(e)
+	size := len(e.buf) - start
+
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(size))
+	e.buf = append(e.buf, prefix[:n]...)
+	copy(e.buf[start+n:], e.buf[start:start+size])
+	copy(e.buf[start:], prefix[:n])
+}
+
+type decoder struct {
+	buf   []byte
+	depth int
+}
+
+// next reads the tag of the next field, it returns false at the end of the input.
+func (d *decoder) next() (field int, wireType int, ok bool, err error) {
+	if len(d.buf) == 0 {
+		return 0, 0, false, nil
+	}
+	tag, err := d.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+		return 0, 0, false, fmt.Errorf("astpb: invalid field number %d", tag>>3)
+	}
+	return int(tag >> 3), int(tag & 7), true, nil
+}
+
+func (d *decoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *decoder) int(wireType int) (int, error) {
+	if wireType != wireVarint {
+		return 0, errWireType(wireType)
+	}
+	v, err := d.varint()
+	return int(int32(v)), err
+}
+
+func (d *decoder) bool(wireType int) (bool, error) {
+	if wireType != wireVarint {
+		return false, errWireType(wireType)
+	}
+	v, err := d.varint()
+	return v != 0, err
+}
+
+func (d *decoder) bytes(wireType int) ([]byte, error) {
+	if wireType != wireBytes {
+		return nil, errWireType(wireType)
+	}
+	size, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(len(d.buf)) {
+		return nil, errTruncated
+	}
+	b := d.buf[:size]
+	d.buf = d.buf[size:]
+	return b, nil
+}
+
+func (d *decoder) string(wireType int) (string, error) {
+	b, err := d.bytes(wireType)
+	return string(b), err
+}
+
+// message decodes a field holding a message, calling fn for every field of the message.
+func (d *decoder) message(wireType int, fn func(d *decoder, field int, wireType int) error) error {
+	b, err := d.bytes(wireType)
+	if err != nil {
+		return err
+	}
+	if d.depth >= maxNesting {
+		return fmt.Errorf("astpb: messages nested more than %d deep", maxNesting)
+	}
+	return decodeMessage(&decoder{buf: b, depth: d.depth + 1}, fn)
+}
+
+func decodeMessage(d *decoder, fn func(d *decoder, field int, wireType int) error) error {
+	for {
+		field, wireType, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		if err := fn(d, field, wireType); err != nil {
+			return err
+		}
+	}
+}
+
+// skip discards a field that isn't known, for compatibility with newer encoders.
+func (d *decoder) skip(wireType int) error {
+	switch wireType {
+	case wireVarint:
+		_, err := d.varint()
+		return err
+	case wireFixed64:
+		return d.discard(8)
+	case wireBytes:
+		_, err := d.bytes(wireType)
+		return err
+	case wireFixed32:
+		return d.discard(4)
+	default:
+		return errWireType(wireType)
+	}
+}
+
+func (d *decoder) discard(n int) error {
+	if len(d.buf) < n {
+		return errTruncated
+	}
+	d.buf = d.buf[n:]
+	return nil
+}
+
+func errWireType(wireType int) error {
+	return fmt.Errorf("astpb: unexpected wire type %d", wireType)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// introspectionResult is the result of the standard introspection query, either the whole
+// response or only its data.
+type introspectionResult struct {
+	Data   *introspectionResult `json:"data"`
+	Schema *struct {
+		QueryType        *typeName           `json:"queryType"`
+		MutationType     *typeName           `json:"mutationType"`
+		SubscriptionType *typeName           `json:"subscriptionType"`
+		Types            []introspectionType `json:"types"`
+		Directives       []struct {
+			Name         string               `json:"name"`
+			Description  string               `json:"description"`
+			Args         []introspectionInput `json:"args"`
+			IsRepeatable bool                 `json:"isRepeatable"`
+			Locations    []string             `json:"locations"`
+		} `json:"directives"`
+	} `json:"__schema"`
+}
+
+type typeName struct {
+	Name string `json:"name"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+type introspectionType struct {
+	Kind          string               `json:"kind"`
+	Name          string               `json:"name"`
+	Description   string               `json:"description"`
+	Fields        []introspectionField `json:"fields"`
+	InputFields   []introspectionInput `json:"inputFields"`
+	Interfaces    []typeName           `json:"interfaces"`
+	EnumValues    []introspectionField `json:"enumValues"`
+	PossibleTypes []typeName           `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string               `json:"name"`
+	Description       string               `json:"description"`
+	Args              []introspectionInput `json:"args"`
+	Type              *typeRef             `json:"type"`
+	IsDeprecated      bool                 `json:"isDeprecated"`
+	DeprecationReason *string              `json:"deprecationReason"`
+}
+
+type introspectionInput struct {
+	Name              string   `json:"name"`
+	Description       string   `json:"description"`
+	Type              *typeRef `json:"type"`
+	DefaultValue      *string  `json:"defaultValue"`
+	IsDeprecated      bool     `json:"isDeprecated"`
+	DeprecationReason *string  `json:"deprecationReason"`
+}
+
+// introspectionSDL prints the schema described by an introspection result as SDL, leaving out the
+// definitions of the prelude.
+func introspectionSDL(b []byte) (string, error) {
+	var result introspectionResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		return "", err
+	}
+	if result.Data != nil {
+		result = *result.Data
+	}
+	schema := result.Schema
+	if schema == nil {
+		return "", errors.New("no __schema in introspection result")
+	}
+
+	prelude, err := parser.ParseSchema(validator.Prelude)
+	if err != nil {
+		return "", err
+	}
+
+	var sdl strings.Builder
+	sdl.WriteString("schema {")
+	for _, root := range []struct {
+		operation string
+		typ       *typeName
+	}{{"query", schema.QueryType}, {"mutation", schema.MutationType}, {"subscription", schema.SubscriptionType}} {
+		if root.typ != nil {
+			sdl.WriteString(" " + root.operation + ": " + root.typ.Name)
+		}
+	}
+	sdl.WriteString(" }\n")
+
+	for _, dir := range schema.Directives {
+		if prelude.Directives.ForName(dir.Name) != nil {
+			continue
+		}
+		writeDescription(&sdl, dir.Description, "")
+		sdl.WriteString("directive @" + dir.Name)
+		writeInputs(&sdl, dir.Args, "(", ")")
+		if dir.IsRepeatable {
+			sdl.WriteString(" repeatable")
+		}
+		sdl.WriteString(" on " + strings.Join(dir.Locations, " | ") + "\n")
+	}
+
+	for _, typ := range schema.Types {
+		if strings.HasPrefix(typ.Name, "__") || prelude.Definitions.ForName(typ.Name) != nil {
+			continue
+		}
+		writeDescription(&sdl, typ.Description, "")
+		switch ast.DefinitionKind(typ.Kind) {
+		case ast.Scalar:
+			sdl.WriteString("scalar " + typ.Name + "\n")
+		case ast.Object, ast.Interface:
+			if typ.Kind == string(ast.Object) {
+				sdl.WriteString("type " + typ.Name)
+			} else {
+				sdl.WriteString("interface " + typ.Name)
+			}
+			for i, iface := range typ.Interfaces {
+				if i == 0 {
+					sdl.WriteString(" implements ")
+				} else {
+					sdl.WriteString(" & ")
+				}
+				sdl.WriteString(iface.Name)
+			}
+			sdl.WriteString(" {\n")
+			for _, field := range typ.Fields {
+				writeDescription(&sdl, field.Description, "  ")
+				sdl.WriteString("  " + field.Name)
+				writeInputs(&sdl, field.Args, "(", ")")
+				sdl.WriteString(": " + typeString(field.Type))
+				writeDeprecated(&sdl, field.IsDeprecated, field.DeprecationReason)
+				sdl.WriteString("\n")
+			}
+			sdl.WriteString("}\n")
+		case ast.Union:
+			sdl.WriteString("union " + typ.Name + " =")
+			for i, member := range typ.PossibleTypes {
+				if i > 0 {
+					sdl.WriteString(" |")
+				}
+				sdl.WriteString(" " + member.Name)
+			}
+			sdl.WriteString("\n")
+		case ast.Enum:
+			sdl.WriteString("enum " + typ.Name + " {\n")
+			for _, value := range typ.EnumValues {
+				writeDescription(&sdl, value.Description, "  ")
+				sdl.WriteString("  " + value.Name)
+				writeDeprecated(&sdl, value.IsDeprecated, value.DeprecationReason)
+				sdl.WriteString("\n")
+			}
+			sdl.WriteString("}\n")
+		case ast.InputObject:
+			sdl.WriteString("input " + typ.Name)
+			writeInputs(&sdl, typ.InputFields, " {", "}")
+			sdl.WriteString("\n")
+		default:
+			return "", errors.New("unknown kind " + typ.Kind + " of type " + typ.Name)
+		}
+	}
+	return sdl.String(), nil
+}
+
+// writeInputs writes arguments or input fields between open and close, one per line.
+func writeInputs(sdl *strings.Builder, inputs []introspectionInput, open string, close string) {
+	if len(inputs) == 0 {
+		return
+	}
+	sdl.WriteString(open + "\n")
+	for _, input := range inputs {
+		writeDescription(sdl, input.Description, "  ")
+		sdl.WriteString("  " + input.Name + ": " + typeString(input.Type))
+		if input.DefaultValue != nil {
+			// default values are given as GraphQL literals
+			sdl.WriteString(" = " + *input.DefaultValue)
+		}
+		writeDeprecated(sdl, input.IsDeprecated, input.DeprecationReason)
+		sdl.WriteString("\n")
+	}
+	sdl.WriteString(close)
+}
+
+func writeDescription(sdl *strings.Builder, description string, indent string) {
+	if description != "" {
+		sdl.WriteString(indent + quote(description) + "\n")
+	}
+}
+
+func writeDeprecated(sdl *strings.Builder, deprecated bool, reason *string) {
+	if !deprecated {
+		return
+	}
+	sdl.WriteString(" @deprecated")
+	if reason != nil {
+		sdl.WriteString("(reason: " + quote(*reason) + ")")
+	}
+}
+
+func typeString(ref *typeRef) string {
+	if ref == nil {
+		return ""
+	}
+	switch ref.Kind {
+	case "NON_NULL":
+		return typeString(ref.OfType) + "!"
+	case "LIST":
+		return "[" + typeString(ref.OfType) + "]"
+	default:
+		return ref.Name
+	}
+}
+
+// quote returns s as a GraphQL string. The escapes produced by encoding/json are all valid in
+// GraphQL strings.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
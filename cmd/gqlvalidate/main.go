@@ -0,0 +1,268 @@
+// Command gqlvalidate validates GraphQL operations against a schema, for use as a CI check of the
+// queries of a frontend.
+//
+//	gqlvalidate -schema schema.graphql src/queries
+//
+// The schema is loaded from SDL files, or from the JSON result of an introspection query when the
+// file name ends in .json. -schema can be given several times, and like the operations it accepts
+// files, directories and glob patterns. Directories are searched for .graphql, .graphqls and .gql
+// files.
+//
+// Fragments can be defined in any of the operation files and used in the others. Files holding
+// only fragments are not validated on their own, their fragments are validated where they are
+// used.
+//
+// Errors are printed with an excerpt of the source. gqlvalidate exits with status 1 if any
+// operation is invalid, and 2 if the schema or the operations could not be loaded.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gqlvalidate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var schemaPaths listFlag
+	flags.Var(&schemaPaths, "schema", "schema `file`, directory or glob pattern, can be repeated")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gqlvalidate -schema schema.graphql operations...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(schemaPaths) == 0 || flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	schema, schemaSources, err := loadSchema(schemaPaths)
+	if err != nil {
+		printErrors(stderr, err, schemaSources)
+		return 2
+	}
+
+	files, err := expand(flags.Args())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	errs, checked := validate(schema, files)
+	for _, err := range errs {
+		fmt.Fprintln(stdout, gqlerror.Print(err, files...))
+		fmt.Fprintln(stdout)
+	}
+	if len(errs) > 0 {
+		fmt.Fprintf(stdout, "%d errors in %d operation files\n", len(errs), checked)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%d operation files are valid\n", checked)
+	return 0
+}
+
+// loadSchema loads the schema from SDL and introspection files. The sources are returned even when
+// loading fails, so errors can be printed with their excerpts.
+func loadSchema(paths []string) (*ast.Schema, []*ast.Source, error) {
+	files, err := expand(paths)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sources []*ast.Source
+	for _, file := range files {
+		if strings.HasSuffix(file.Name, ".json") {
+			sdl, err := introspectionSDL([]byte(file.Input))
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %w", file.Name, err)
+			}
+			file = &ast.Source{Name: file.Name, Input: sdl}
+		}
+		sources = append(sources, file)
+	}
+	schema, err := gqlparser.LoadSchema(sources...)
+	return schema, sources, err
+}
+
+// expand reads the files named by paths, walking directories and expanding glob patterns.
+func expand(paths []string) ([]*ast.Source, error) {
+	var names []string
+	for _, p := range paths {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: no such file or directory", p)
+		}
+		for _, match := range matches {
+			err := filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() && (path == match || isGraphQLFile(path)) {
+					names = append(names, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var sources []*ast.Source
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		b, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, &ast.Source{Name: name, Input: string(b)})
+	}
+	return sources, nil
+}
+
+func isGraphQLFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".graphql", ".graphqls", ".gql":
+		return true
+	}
+	return false
+}
+
+// validate checks every file holding operations, along with the fragments they use from other
+// files. It returns the errors sorted by position, and the number of files checked.
+func validate(schema *ast.Schema, files []*ast.Source) (gqlerror.List, int) {
+	var errs gqlerror.List
+	docs := make([]*ast.QueryDocument, 0, len(files))
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, file := range files {
+		doc, err := parser.ParseQuery(file)
+		if err != nil {
+			errs = append(errs, gqlerror.WrapIfUnwrapped(err))
+			continue
+		}
+		for _, frag := range doc.Fragments {
+			if fragments[frag.Name] == nil {
+				fragments[frag.Name] = frag
+			}
+		}
+		docs = append(docs, doc)
+	}
+
+	// errors in shared fragments are reported by every file using them, but only printed once.
+	seen := map[string]bool{}
+	checked := 0
+	for _, doc := range docs {
+		if len(doc.Operations) == 0 {
+			continue
+		}
+		checked++
+		for _, err := range validator.Validate(schema, withFragments(doc, fragments)) {
+			key := err.Error() + fmt.Sprint(err.Locations)
+			if !seen[key] {
+				seen[key] = true
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		fi, _ := errs[i].Extensions["file"].(string)
+		fj, _ := errs[j].Extensions["file"].(string)
+		if fi != fj {
+			return fi < fj
+		}
+		return line(errs[i]) < line(errs[j])
+	})
+	return errs, checked
+}
+
+// withFragments returns doc with the fragments it uses from other files added.
+func withFragments(doc *ast.QueryDocument, fragments map[string]*ast.FragmentDefinition) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Operations: doc.Operations,
+		Fragments:  append(ast.FragmentDefinitionList(nil), doc.Fragments...),
+		Position:   doc.Position,
+	}
+	var walk func(set ast.SelectionSet)
+	walk = func(set ast.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				walk(sel.SelectionSet)
+			case *ast.InlineFragment:
+				walk(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				if result.Fragments.ForName(sel.Name) != nil {
+					continue
+				}
+				if frag := fragments[sel.Name]; frag != nil {
+					result.Fragments = append(result.Fragments, frag)
+					walk(frag.SelectionSet)
+				}
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(op.SelectionSet)
+	}
+	for _, frag := range doc.Fragments {
+		walk(frag.SelectionSet)
+	}
+	return result
+}
+
+func line(err *gqlerror.Error) int {
+	if len(err.Locations) == 0 {
+		return 0
+	}
+	return err.Locations[0].Line
+}
+
+func printErrors(w io.Writer, err error, sources []*ast.Source) {
+	var list gqlerror.List
+	switch e := err.(type) {
+	case gqlerror.List:
+		list = e
+	case *gqlerror.Error:
+		list = gqlerror.List{e}
+	default:
+		fmt.Fprintln(w, err)
+		return
+	}
+	for _, e := range list {
+		fmt.Fprintln(w, gqlerror.Print(e, sources...))
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const schemaSDL = `
+type Query {
+	user(id: ID!): User
+	users(role: Role = MEMBER): [User!]!
+}
+"A person."
+type User {
+	id: ID!
+	name: String @deprecated(reason: "use fullName")
+	fullName: String
+	role: Role
+}
+enum Role { ADMIN MEMBER }
+`
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func runCLI(t *testing.T, dir string, args ...string) (int, string, string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("valid operations", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"schema.graphql":                 schemaSDL,
+			"queries/user.graphql":           "query User { user(id: 1) { ...UserFields } }",
+			"queries/users.gql":              "query Users { users { ...UserFields } }",
+			"queries/fragments/user.graphql": "fragment UserFields on User { id fullName }",
+			"queries/readme.md":              "not graphql",
+		})
+
+		code, stdout, stderr := runCLI(t, dir, "-schema", "schema.graphql", "queries")
+		require.Empty(t, stderr)
+		require.Equal(t, "2 operation files are valid\n", stdout)
+		require.Equal(t, 0, code)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"schema.graphqls": schemaSDL,
+			"a.graphql":       "query A {\n  user(id: 1) {\n    nmae\n  }\n}\n",
+			"b.graphql":       "query B { users { ...F } }",
+			"c.graphql":       "query C { user(id: 2) { ...F } }",
+			"f.graphql":       "fragment F on User { id zzz }",
+		})
+
+		code, stdout, _ := runCLI(t, dir, "-schema", "schema.graphqls", "*.graphql")
+		require.Equal(t, 1, code)
+		require.Equal(t, `Cannot query field "nmae" on type "User". Did you mean "name"?
+
+a.graphql:3:5
+2 |   user(id: 1) {
+3 |     nmae
+  |     ^
+4 |   }
+
+Cannot query field "zzz" on type "User".
+
+f.graphql:1:25
+1 | fragment F on User { id zzz }
+  |                         ^
+
+2 errors in 3 operation files
+`, stdout)
+	})
+
+	t.Run("introspection schema", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"schema.json": introspectionJSON,
+			"a.graphql":   "query A($role: Role) { users(role: $role) { id name } }",
+			"b.graphql":   "query B { users(role: OWNER) { id } }",
+		})
+
+		code, stdout, stderr := runCLI(t, dir, "-schema", "schema.json", "a.graphql")
+		require.Empty(t, stderr)
+		require.Equal(t, 0, code, stdout)
+
+		code, stdout, _ = runCLI(t, dir, "-schema", "schema.json", "b.graphql")
+		require.Equal(t, 1, code)
+		require.Contains(t, stdout, `Value "OWNER" does not exist in "Role" enum.`)
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"schema.graphql": "type Query { user: Nope }",
+			"a.graphql":      "{ user }",
+		})
+
+		code, _, stderr := runCLI(t, dir, "-schema", "schema.graphql", "a.graphql")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "Undefined type Nope.")
+		require.Contains(t, stderr, "schema.graphql:1:")
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		code, _, stderr := runCLI(t, t.TempDir(), "a.graphql")
+		require.Equal(t, 2, code)
+		require.Contains(t, stderr, "usage: gqlvalidate")
+
+		code, _, stderr = runCLI(t, t.TempDir(), "-schema", "missing.graphql", "a.graphql")
+		require.Equal(t, 2, code)
+		require.Equal(t, "missing.graphql: no such file or directory\n", stderr)
+	})
+}
+
+func TestIntrospectionSDL(t *testing.T) {
+	sdl, err := introspectionSDL([]byte(introspectionJSON))
+	require.NoError(t, err)
+	require.Equal(t, `schema { query: Query }
+directive @cached(
+  ttl: Int = 60
+) repeatable on FIELD_DEFINITION | OBJECT
+type Query {
+  users(
+  role: Role
+): [User!]!
+}
+"A \"person\"."
+type User implements Node {
+  id: ID!
+  name: String @deprecated(reason: "use fullName")
+}
+interface Node {
+  id: ID!
+}
+enum Role {
+  ADMIN
+  MEMBER
+  GUEST @deprecated
+}
+input Filter {
+  "Exact match."
+  name: String = "bob"
+}
+union Result = User | Query
+`, sdl)
+
+	_, err = introspectionSDL([]byte(`{"errors": [{"message": "introspection is disabled"}]}`))
+	require.EqualError(t, err, "no __schema in introspection result")
+}
+
+const introspectionJSON = `{"data": {"__schema": {
+	"queryType": {"name": "Query"},
+	"mutationType": null,
+	"subscriptionType": null,
+	"directives": [
+		{"name": "skip", "locations": ["FIELD"], "args": []},
+		{"name": "cached", "isRepeatable": true, "locations": ["FIELD_DEFINITION", "OBJECT"], "args": [
+			{"name": "ttl", "type": {"kind": "SCALAR", "name": "Int"}, "defaultValue": "60"}
+		]}
+	],
+	"types": [
+		{"kind": "OBJECT", "name": "Query", "interfaces": [], "fields": [
+			{"name": "users", "args": [{"name": "role", "type": {"kind": "ENUM", "name": "Role"}, "defaultValue": null}],
+			 "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "User"}}}}}
+		]},
+		{"kind": "OBJECT", "name": "User", "description": "A \"person\".", "interfaces": [{"name": "Node"}], "fields": [
+			{"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+			{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}, "isDeprecated": true, "deprecationReason": "use fullName"}
+		]},
+		{"kind": "INTERFACE", "name": "Node", "fields": [
+			{"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+		]},
+		{"kind": "ENUM", "name": "Role", "enumValues": [
+			{"name": "ADMIN"}, {"name": "MEMBER"}, {"name": "GUEST", "isDeprecated": true}
+		]},
+		{"kind": "INPUT_OBJECT", "name": "Filter", "inputFields": [
+			{"name": "name", "description": "Exact match.", "type": {"kind": "SCALAR", "name": "String"}, "defaultValue": "\"bob\""}
+		]},
+		{"kind": "UNION", "name": "Result", "possibleTypes": [{"name": "User"}, {"name": "Query"}]},
+		{"kind": "SCALAR", "name": "String"},
+		{"kind": "OBJECT", "name": "__Schema", "fields": []}
+	]
+}}}`
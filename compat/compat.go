@@ -0,0 +1,200 @@
+// Package compat converts AST values between this module's ast package and another copy of it,
+// such as the github.com/vektah/gqlparser/v2/ast package gqlgen and its plugins are built on, so
+// projects can adopt this parser without rewriting their plugin code at once.
+//
+// Conversion is structural: struct fields are matched by name, and fields that only exist on one
+// side are left out. Pointers shared by the source, such as the definitions the validator attaches
+// to fields or the Source of positions, stay shared in the result, and cycles are followed once.
+package compat
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Converter converts AST values between two ast packages.
+type Converter struct {
+	// types holds the concrete types interface values can be converted into, by type name
+	types map[string][]reflect.Type
+}
+
+// New returns a Converter. Selections are interface values, so the Converter can't find the types
+// of the other package on its own: give it its Field, FragmentSpread and InlineFragment types,
+// eg:
+//
+//	c := compat.New(&upstream.Field{}, &upstream.FragmentSpread{}, &upstream.InlineFragment{})
+//
+// The selection types of this module's ast package are always known.
+func New(selections ...interface{}) *Converter {
+	c := &Converter{types: map[string][]reflect.Type{}}
+	for _, v := range append([]interface{}{&ast.Field{}, &ast.FragmentSpread{}, &ast.InlineFragment{}}, selections...) {
+		t := reflect.TypeOf(v)
+		c.types[typeName(t)] = append(c.types[typeName(t)], t)
+	}
+	return c
+}
+
+// Convert converts src into dst, a pointer to a variable of the matching type of the other
+// package, eg:
+//
+//	var doc *upstream.QueryDocument
+//	err := c.Convert(&doc, query)
+//
+// A schema and the documents validated against it should be converted in a single call, as the
+// fields of a struct holding them, for the definitions referenced by the documents to be the ones
+// of the converted schema.
+func (c *Converter) Convert(dst, src interface{}) error {
+	out := reflect.ValueOf(dst)
+	if out.Kind() != reflect.Ptr || out.IsNil() {
+		return fmt.Errorf("compat: expected a non nil pointer to convert into, got %T", dst)
+	}
+	conv := conversion{Converter: c, seen: map[seenKey]reflect.Value{}}
+	return conv.convert(out.Elem(), reflect.ValueOf(src))
+}
+
+type conversion struct {
+	*Converter
+	// seen holds the result of each pointer already converted
+	seen map[seenKey]reflect.Value
+}
+
+type seenKey struct {
+	ptr      uintptr
+	src, dst reflect.Type
+}
+
+func (c *conversion) convert(dst, src reflect.Value) error {
+	if !src.IsValid() {
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if src.Kind() != reflect.Ptr {
+			return mismatch(dst, src)
+		}
+		if src.IsNil() {
+			return nil
+		}
+		key := seenKey{ptr: src.Pointer(), src: src.Type(), dst: dst.Type()}
+		if v, ok := c.seen[key]; ok {
+			dst.Set(v)
+			return nil
+		}
+		v := reflect.New(dst.Type().Elem())
+		c.seen[key] = v
+		dst.Set(v)
+		return c.convert(v.Elem(), src.Elem())
+
+	case reflect.Struct:
+		if src.Kind() != reflect.Struct {
+			return mismatch(dst, src)
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			field := dst.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			from := src.FieldByName(field.Name)
+			if !from.IsValid() || !from.CanInterface() {
+				continue
+			}
+			if err := c.convert(dst.Field(i), from); err != nil {
+				return fmt.Errorf("%s.%s: %w", dst.Type().Name(), field.Name, err)
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			return mismatch(dst, src)
+		}
+		if src.IsNil() {
+			return nil
+		}
+		v := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := c.convert(v.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(v)
+		return nil
+
+	case reflect.Map:
+		if src.Kind() != reflect.Map {
+			return mismatch(dst, src)
+		}
+		if src.IsNil() {
+			return nil
+		}
+		v := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			key := reflect.New(dst.Type().Key()).Elem()
+			if err := c.convert(key, iter.Key()); err != nil {
+				return err
+			}
+			value := reflect.New(dst.Type().Elem()).Elem()
+			if err := c.convert(value, iter.Value()); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, value)
+		}
+		dst.Set(v)
+		return nil
+
+	case reflect.Interface:
+		if src.Kind() == reflect.Interface {
+			if src.IsNil() {
+				return nil
+			}
+			src = src.Elem()
+		}
+		t := c.concreteType(dst.Type(), src.Type())
+		if t == nil {
+			return fmt.Errorf("no type implementing %s matches %s, pass it to compat.New", dst.Type(), src.Type())
+		}
+		v := reflect.New(t).Elem()
+		if err := c.convert(v, src); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return mismatch(dst, src)
+
+	default:
+		if src.Kind() != dst.Kind() || !src.Type().ConvertibleTo(dst.Type()) {
+			return mismatch(dst, src)
+		}
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+}
+
+// concreteType returns the type implementing iface to convert a value of type t into.
+func (c *conversion) concreteType(iface reflect.Type, t reflect.Type) reflect.Type {
+	if t.Implements(iface) {
+		return t
+	}
+	for _, candidate := range c.types[typeName(t)] {
+		if candidate.Implements(iface) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+func mismatch(dst, src reflect.Value) error {
+	return fmt.Errorf("cannot convert %s to %s", src.Type(), dst.Type())
+}
@@ -0,0 +1,162 @@
+package compat_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/compat"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		user(id: ID!, role: Role = ADMIN): User
+	}
+	enum Role { ADMIN GUEST }
+	type User {
+		id: ID!
+		name: String
+		friends(first: Int): [User!]!
+	}
+`})
+
+const query = `query Q($id: ID!) {
+	user(id: $id, role: GUEST) {
+		... on User {
+			n: name
+		}
+		...UserFields
+	}
+}
+fragment UserFields on User {
+	friends(first: 10) {
+		id
+	}
+}
+`
+
+// The types below stand in for the ast package of another module, with only some of its fields.
+
+type QueryDocument struct {
+	Operations []*OperationDefinition
+	Fragments  []*FragmentDefinition
+}
+
+type OperationDefinition struct {
+	Operation           string
+	Name                string
+	VariableDefinitions []*VariableDefinition
+	SelectionSet        []Selection
+}
+
+type VariableDefinition struct {
+	Variable string
+	Type     *Type
+}
+
+type Type struct {
+	NamedType string
+	Elem      *Type
+	NonNull   bool
+}
+
+type FragmentDefinition struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+type Selection interface {
+	isSelection()
+}
+
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	SelectionSet []Selection
+}
+
+type FragmentSpread struct {
+	Name string
+}
+
+type InlineFragment struct {
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+func (*Field) isSelection()          {}
+func (*FragmentSpread) isSelection() {}
+func (*InlineFragment) isSelection() {}
+
+type Argument struct {
+	Name  string
+	Value *Value
+}
+
+type Value struct {
+	Raw  string
+	Kind int
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc, err := gqlparser.LoadQuery(schema, query)
+	require.Nil(t, err)
+
+	c := compat.New(&Field{}, &FragmentSpread{}, &InlineFragment{})
+	var other *QueryDocument
+	require.NoError(t, c.Convert(&other, doc))
+
+	user := other.Operations[0].SelectionSet[0].(*Field)
+	require.Equal(t, "user", user.Name)
+	require.Equal(t, "GUEST", user.Arguments[1].Value.Raw)
+	require.Equal(t, int(ast.EnumValue), user.Arguments[1].Value.Kind)
+	require.Equal(t, "User", user.SelectionSet[0].(*InlineFragment).TypeCondition)
+	require.Equal(t, "UserFields", user.SelectionSet[1].(*FragmentSpread).Name)
+	require.Equal(t, "ID", other.Operations[0].VariableDefinitions[0].Type.NamedType)
+
+	var back *ast.QueryDocument
+	require.NoError(t, c.Convert(&back, other))
+	require.Equal(t, format(doc), format(back))
+}
+
+func TestSharedPointers(t *testing.T) {
+	doc, err := gqlparser.LoadQuery(schema, query)
+	require.Nil(t, err)
+
+	type validated struct {
+		Schema *ast.Schema
+		Doc    *ast.QueryDocument
+	}
+	var copied validated
+	require.NoError(t, compat.New().Convert(&copied, validated{schema, doc}))
+	require.Equal(t, format(doc), format(copied.Doc))
+	require.NotSame(t, doc, copied.Doc)
+
+	user := copied.Doc.Operations[0].SelectionSet[0].(*ast.Field)
+	require.Same(t, copied.Schema.Query.Fields.ForName("user"), user.Definition)
+	require.Same(t, copied.Schema.Types["User"], user.SelectionSet[1].(*ast.FragmentSpread).ObjectDefinition)
+	require.Same(t, copied.Doc.Fragments[0], user.SelectionSet[1].(*ast.FragmentSpread).Definition)
+	require.Same(t, user.Position.Src, copied.Doc.Fragments[0].Position.Src)
+	require.Equal(t, schema.Types["User"].Fields.ForName("friends").Type.String(), copied.Schema.Types["User"].Fields.ForName("friends").Type.String())
+}
+
+func TestUnknownSelection(t *testing.T) {
+	doc, err := gqlparser.LoadQuery(schema, query)
+	require.Nil(t, err)
+
+	var other *QueryDocument
+	require.EqualError(t, compat.New().Convert(&other, doc), "QueryDocument.Operations: OperationDefinition.SelectionSet: no type implementing compat_test.Selection matches *ast.Field, pass it to compat.New")
+
+	require.EqualError(t, compat.New().Convert((*QueryDocument)(nil), doc), "compat: expected a non nil pointer to convert into, got *compat_test.QueryDocument")
+}
+
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
@@ -0,0 +1,324 @@
+// Package complexity estimates how expensive an operation is to execute, so that servers can
+// reject or rate limit expensive operations before running them.
+package complexity
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/internal/saturating"
+)
+
+// DefaultListArguments are the arguments read as the size of the list returned by a field.
+var DefaultListArguments = []string{"first", "last", "limit"}
+
+// CostFunc returns the cost of selecting field on parent, or false to use the default cost.
+type CostFunc func(parent *ast.Definition, field *ast.FieldDefinition) (int, bool)
+
+// Option configures Estimate.
+type Option func(e *estimator)
+
+// WithFieldCost sets the cost of fields without a cost of their own, 1 by default.
+func WithFieldCost(cost int) Option {
+	return func(e *estimator) {
+		e.fieldCost = cost
+	}
+}
+
+// WithDefaultListSize sets the size assumed for lists returned by fields without a list
+// argument, 1 by default.
+func WithDefaultListSize(size int) Option {
+	return func(e *estimator) {
+		e.defaultListSize = size
+	}
+}
+
+// WithListArguments replaces DefaultListArguments as the arguments read as the size of the list
+// returned by a field.
+func WithListArguments(names ...string) Option {
+	return func(e *estimator) {
+		e.listArguments = names
+	}
+}
+
+// WithCostFunc uses fn to find the cost of each field. Fields fn has no cost for fall back to
+// the default cost.
+func WithCostFunc(fn CostFunc) Option {
+	return func(e *estimator) {
+		e.costFunc = fn
+	}
+}
+
+// WithCostDirective reads the cost of fields from the weight argument of the named directive, eg
+// `@cost(weight: 5)`, on the field definition or, failing that, on the type the field returns.
+// The weight may be given as an Int or as a String holding an integer.
+func WithCostDirective(name string) Option {
+	return func(e *estimator) {
+		e.costFunc = func(parent *ast.Definition, field *ast.FieldDefinition) (int, bool) {
+			if weight, ok := directiveInt(field.Directives, name, "weight"); ok {
+				return weight, true
+			}
+			if def := e.schema.Types[field.Type.Name()]; def != nil {
+				return directiveInt(def.Directives, name, "weight")
+			}
+			return 0, false
+		}
+	}
+}
+
+// WithListSizeDirective reads the size of the lists returned by fields from the named directive
+// on their definition, eg `@listSize(assumedSize: 50, slicingArguments: ["first"])`.
+// slicingArguments replace the list arguments for the field, and assumedSize is used when none of
+// them are given in the query.
+func WithListSizeDirective(name string) Option {
+	return func(e *estimator) {
+		e.listSizeDirective = name
+	}
+}
+
+// Estimate returns the cost of the most expensive operation in doc, the only one executed when
+// doc holds several. doc must have been validated against schema.
+//
+// The cost of a field is its own cost plus the cost of its selections, multiplied by the size of
+// the list it returns. List sizes are read from the first of the list arguments given in the
+// query, using variables for the values of variables. Selections on different types of an
+// abstract type are all counted, so the estimate is an upper bound. __typename is free.
+//
+// Fragments are counted every time they are spread, but only walked once, so estimating is
+// cheap even for documents that would be huge once expanded. Costs are capped at math.MaxInt
+// rather than overflowing.
+func Estimate(schema *ast.Schema, doc *ast.QueryDocument, variables map[string]interface{}, options ...Option) int {
+	e := newEstimator(schema, doc, variables, options)
+
+	max := 0
+	for _, op := range doc.Operations {
+		if cost := e.selectionSet(op.SelectionSet); cost > max {
+			max = cost
+		}
+	}
+	return max
+}
+
+// EstimateOperation returns the cost of operation, which must be one of the operations in doc.
+// The cost is worked out as described by Estimate.
+func EstimateOperation(schema *ast.Schema, doc *ast.QueryDocument, operation *ast.OperationDefinition, variables map[string]interface{}, options ...Option) int {
+	e := newEstimator(schema, doc, variables, options)
+	return e.selectionSet(operation.SelectionSet)
+}
+
+func newEstimator(schema *ast.Schema, doc *ast.QueryDocument, variables map[string]interface{}, options []Option) *estimator {
+	e := &estimator{
+		schema:          schema,
+		doc:             doc,
+		variables:       variables,
+		fieldCost:       1,
+		defaultListSize: 1,
+		listArguments:   DefaultListArguments,
+		fragments:       map[string]int{},
+		visiting:        map[string]bool{},
+	}
+	for _, o := range options {
+		o(e)
+	}
+	return e
+}
+
+type estimator struct {
+	schema          *ast.Schema
+	doc             *ast.QueryDocument
+	variables       map[string]interface{}
+	fieldCost       int
+	defaultListSize int
+	listArguments   []string
+	costFunc        CostFunc
+
+	listSizeDirective string
+
+	// fragments holds the cost of the fragments walked so far
+	fragments map[string]int
+	// visiting holds the fragments being walked, so cycles in unvalidated documents end
+	visiting map[string]bool
+}
+
+func (e *estimator) selectionSet(set ast.SelectionSet) int {
+	total := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			total = saturating.Add(total, e.field(sel))
+		case *ast.InlineFragment:
+			total = saturating.Add(total, e.selectionSet(sel.SelectionSet))
+		case *ast.FragmentSpread:
+			total = saturating.Add(total, e.fragment(sel.Name))
+		}
+	}
+	return total
+}
+
+func (e *estimator) fragment(name string) int {
+	if cost, ok := e.fragments[name]; ok {
+		return cost
+	}
+	def := e.doc.Fragments.ForName(name)
+	if def == nil || e.visiting[name] {
+		return 0
+	}
+
+	e.visiting[name] = true
+	cost := e.selectionSet(def.SelectionSet)
+	delete(e.visiting, name)
+
+	e.fragments[name] = cost
+	return cost
+}
+
+func (e *estimator) field(field *ast.Field) int {
+	if field.Name == "__typename" {
+		return 0
+	}
+
+	cost := e.fieldCost
+	if e.costFunc != nil && field.Definition != nil {
+		if c, ok := e.costFunc(field.ObjectDefinition, field.Definition); ok {
+			cost = c
+		}
+	}
+	if cost < 0 {
+		cost = 0
+	}
+	if len(field.SelectionSet) == 0 {
+		return cost
+	}
+
+	children := e.selectionSet(field.SelectionSet)
+	if field.Definition != nil && field.Definition.Type.Elem != nil {
+		children = saturating.Mul(children, e.listSize(field))
+	}
+	return saturating.Add(cost, children)
+}
+
+// listSize returns the size of the list returned by field.
+func (e *estimator) listSize(field *ast.Field) int {
+	listArguments, defaultSize := e.listArguments, e.defaultListSize
+	if e.listSizeDirective != "" && field.Definition != nil {
+		if dir := field.Definition.Directives.ForName(e.listSizeDirective); dir != nil {
+			if arg := dir.Arguments.ForName("slicingArguments"); arg != nil && arg.Value != nil && arg.Value.Kind == ast.ListValue {
+				listArguments = nil
+				for _, child := range arg.Value.Children {
+					listArguments = append(listArguments, child.Value.Raw)
+				}
+			}
+			if size, ok := directiveInt(field.Definition.Directives, e.listSizeDirective, "assumedSize"); ok {
+				defaultSize = size
+			}
+		}
+	}
+
+	for _, name := range listArguments {
+		arg := field.Arguments.ForName(name)
+		if arg == nil {
+			continue
+		}
+		value, err := arg.Value.Value(e.variables)
+		if err != nil {
+			continue
+		}
+		if size, ok := toInt(value); ok {
+			if size < 0 {
+				return 0
+			}
+			return size
+		}
+	}
+	return defaultSize
+}
+
+// toInt returns value as an int, clamped to the range of a GraphQL Int so that sizes multiply the
+// same way on every platform.
+func toInt(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return clamp(int64(value)), true
+	case int8:
+		return int(value), true
+	case int16:
+		return int(value), true
+	case int32:
+		return int(value), true
+	case int64:
+		return clamp(value), true
+	case uint:
+		return clampUnsigned(uint64(value)), true
+	case uint8:
+		return int(value), true
+	case uint16:
+		return int(value), true
+	case uint32:
+		return clampUnsigned(uint64(value)), true
+	case uint64:
+		return clampUnsigned(value), true
+	case float64:
+		if value > math.MaxInt32 {
+			return math.MaxInt32, true
+		}
+		if value < math.MinInt32 {
+			return math.MinInt32, true
+		}
+		return int(value), true
+	case json.Number:
+		i, err := value.Int64()
+		if err != nil {
+			return 0, false
+		}
+		return toInt(i)
+	case string:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return toInt(i)
+	default:
+		return 0, false
+	}
+}
+
+func clamp(value int64) int {
+	if value > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if value < math.MinInt32 {
+		return math.MinInt32
+	}
+	return int(value)
+}
+
+func clampUnsigned(value uint64) int {
+	if value > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(value)
+}
+
+// directiveInt returns the integer value of an argument of the named directive.
+func directiveInt(directives ast.DirectiveList, name string, argument string) (int, bool) {
+	dir := directives.ForName(name)
+	if dir == nil {
+		return 0, false
+	}
+	arg := dir.Arguments.ForName(argument)
+	if arg == nil || arg.Value == nil {
+		return 0, false
+	}
+	switch arg.Value.Kind {
+	case ast.IntValue, ast.StringValue, ast.FloatValue:
+		f, err := strconv.ParseFloat(arg.Value.Raw, 64)
+		if err != nil {
+			return 0, false
+		}
+		return toInt(f)
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,118 @@
+package complexity_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/complexity"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	directive @cost(weight: String!) on FIELD_DEFINITION | OBJECT
+	directive @listSize(assumedSize: Int, slicingArguments: [String!]) on FIELD_DEFINITION
+	type Query {
+		user(id: ID!): User
+		users(first: Int, last: Int): [User!]!
+		page(size: Int, first: Int): [User!]! @listSize(assumedSize: 20, slicingArguments: ["size"])
+		search(limit: Int): [Result!]! @cost(weight: "10")
+		expensive: Report
+	}
+	type User {
+		id: ID!
+		name: String
+		friends(first: Int): [User!]!
+		tags: [String!]!
+	}
+	type Report @cost(weight: "50") {
+		total: Int
+	}
+	union Result = User | Report
+`})
+
+func TestEstimate(t *testing.T) {
+	estimate := func(t *testing.T, query string, variables map[string]interface{}, options ...complexity.Option) int {
+		t.Helper()
+		doc := gqlparser.MustLoadQuery(schema, query)
+		return complexity.Estimate(schema, doc, variables, options...)
+	}
+
+	t.Run("fields", func(t *testing.T) {
+		require.Equal(t, 3, estimate(t, `{ user(id: 1) { __typename id name } }`, nil))
+		require.Equal(t, 2, estimate(t, `{ user(id: 1) { tags } }`, nil))
+	})
+
+	t.Run("lists are multiplied by their size", func(t *testing.T) {
+		require.Equal(t, 1+10*(1+1), estimate(t, `{ users(first: 10) { id name } }`, nil))
+		require.Equal(t, 1+10*(1+5*1), estimate(t, `{ users(last: 10) { friends(first: 5) { id } } }`, nil))
+		require.Equal(t, 1+1*2, estimate(t, `{ users { id name } }`, nil))
+		require.Equal(t, 1+20*2, estimate(t, `{ users { id name } }`, nil, complexity.WithDefaultListSize(20)))
+		require.Equal(t, 1, estimate(t, `{ users(first: -5) { id } }`, nil))
+	})
+
+	t.Run("list sizes from variables", func(t *testing.T) {
+		query := `query Q($n: Int) { users(first: $n) { id } }`
+		require.Equal(t, 1+7, estimate(t, query, map[string]interface{}{"n": 7}))
+		require.Equal(t, 1+7, estimate(t, query, map[string]interface{}{"n": json.Number("7")}))
+		require.Equal(t, 1+7, estimate(t, query, map[string]interface{}{"n": float64(7)}))
+		require.Equal(t, 1+math.MaxInt32, estimate(t, query, map[string]interface{}{"n": math.MaxInt}))
+		require.Equal(t, 1+math.MaxInt32, estimate(t, query, map[string]interface{}{"n": int64(math.MaxInt64)}))
+		require.Equal(t, 1+math.MaxInt32, estimate(t, query, map[string]interface{}{"n": uint64(math.MaxUint64)}))
+		require.Equal(t, 1, estimate(t, query, map[string]interface{}{"n": math.MinInt}))
+		require.Equal(t, 1+1, estimate(t, query, nil))
+	})
+
+	t.Run("custom list arguments", func(t *testing.T) {
+		query := `{ users(first: 5) { id } search(limit: 3) { __typename } }`
+		require.Equal(t, 1+1+1, estimate(t, query, nil, complexity.WithListArguments("limit")))
+	})
+
+	t.Run("fragments count every spread", func(t *testing.T) {
+		require.Equal(t, 1+3*(1+1+1), estimate(t, `
+			{ users(first: 3) { ...F ... on User { ...F } id } }
+			fragment F on User { name }
+		`, nil))
+	})
+
+	t.Run("most expensive operation", func(t *testing.T) {
+		require.Equal(t, 1+10, estimate(t, `query A { user(id: 1) { id } } query B { users(first: 10) { id } }`, nil))
+	})
+
+	t.Run("cost directive", func(t *testing.T) {
+		query := `{ search(limit: 2) { ... on User { id } ... on Report { total } } expensive { total } }`
+		require.Equal(t, 1+2*(1+1)+1+1, estimate(t, query, nil))
+		require.Equal(t, 10+2*(1+1)+50+1, estimate(t, query, nil, complexity.WithCostDirective("cost")))
+	})
+
+	t.Run("list size directive", func(t *testing.T) {
+		listSize := complexity.WithListSizeDirective("listSize")
+		require.Equal(t, 1+20*1, estimate(t, `{ page { id } }`, nil, listSize))
+		require.Equal(t, 1+4*1, estimate(t, `{ page(size: 4) { id } }`, nil, listSize))
+		require.Equal(t, 1+20*1, estimate(t, `{ page(first: 4) { id } }`, nil, listSize))
+		require.Equal(t, 1+4*1, estimate(t, `{ page(first: 4) { id } }`, nil))
+	})
+
+	t.Run("cost func", func(t *testing.T) {
+		cost := func(parent *ast.Definition, field *ast.FieldDefinition) (int, bool) {
+			if parent.Name == "User" && field.Name == "name" {
+				return 3, true
+			}
+			return 0, false
+		}
+		require.Equal(t, 2+2+3, estimate(t, `{ user(id: 1) { id name } }`, nil, complexity.WithCostFunc(cost), complexity.WithFieldCost(2)))
+	})
+
+	t.Run("single operation", func(t *testing.T) {
+		doc := gqlparser.MustLoadQuery(schema, `query A { users(first: 5) { id } } query B { user(id: 1) { id } }`)
+		require.Equal(t, 1+5, complexity.EstimateOperation(schema, doc, doc.Operations.ForName("A"), nil))
+		require.Equal(t, 1+1, complexity.EstimateOperation(schema, doc, doc.Operations.ForName("B"), nil))
+	})
+
+	t.Run("does not overflow", func(t *testing.T) {
+		query := `{ users(first: 2147483647) { friends(first: 2147483647) { friends(first: 2147483647) { id } } } }`
+		require.Equal(t, math.MaxInt, estimate(t, query, nil))
+	})
+}
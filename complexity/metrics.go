@@ -0,0 +1,159 @@
+package complexity
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/internal/saturating"
+)
+
+// MetricOption configures FieldCount and AliasCount.
+type MetricOption func(m *metrics)
+
+// CountFragmentsOnce counts the selections of each fragment once, however many times it is
+// spread, measuring the size of the document rather than the size of the response.
+func CountFragmentsOnce() MetricOption {
+	return func(m *metrics) {
+		m.once = true
+	}
+}
+
+// MaxDepth returns the depth of the deepest field in doc, following fragment spreads. Root fields
+// are at depth 1, fragments don't add to the depth.
+func MaxDepth(doc *ast.QueryDocument) int {
+	d := depths{
+		doc:       doc,
+		fragments: map[string]int{},
+		visiting:  map[string]bool{},
+	}
+	max := 0
+	for _, op := range doc.Operations {
+		if depth := d.selectionSet(op.SelectionSet); depth > max {
+			max = depth
+		}
+	}
+	return max
+}
+
+// FieldCount returns the number of fields selected by all the operations in doc. By default the
+// fields of a fragment are counted every time it is spread.
+func FieldCount(doc *ast.QueryDocument, options ...MetricOption) int {
+	return count(doc, func(*ast.Field) bool { return true }, options)
+}
+
+// AliasCount returns the number of aliased fields selected by all the operations in doc. By
+// default the fields of a fragment are counted every time it is spread.
+func AliasCount(doc *ast.QueryDocument, options ...MetricOption) int {
+	return count(doc, func(field *ast.Field) bool {
+		return field.Alias != "" && field.Alias != field.Name
+	}, options)
+}
+
+type depths struct {
+	doc       *ast.QueryDocument
+	fragments map[string]int
+	visiting  map[string]bool
+}
+
+func (d *depths) selectionSet(set ast.SelectionSet) int {
+	max := 0
+	for _, sel := range set {
+		depth := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			depth = 1 + d.selectionSet(sel.SelectionSet)
+		case *ast.InlineFragment:
+			depth = d.selectionSet(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			depth = d.fragment(sel.Name)
+		}
+		if depth > max {
+			max = depth
+		}
+	}
+	return max
+}
+
+func (d *depths) fragment(name string) int {
+	if depth, ok := d.fragments[name]; ok {
+		return depth
+	}
+	def := d.doc.Fragments.ForName(name)
+	if def == nil || d.visiting[name] {
+		return 0
+	}
+
+	d.visiting[name] = true
+	depth := d.selectionSet(def.SelectionSet)
+	delete(d.visiting, name)
+
+	d.fragments[name] = depth
+	return depth
+}
+
+type metrics struct {
+	doc   *ast.QueryDocument
+	match func(field *ast.Field) bool
+	once  bool
+
+	// fragments holds the count of the fragments walked so far
+	fragments map[string]int
+	// visiting holds the fragments being walked, so cycles in unvalidated documents end
+	visiting map[string]bool
+}
+
+func count(doc *ast.QueryDocument, match func(field *ast.Field) bool, options []MetricOption) int {
+	m := metrics{
+		doc:       doc,
+		match:     match,
+		fragments: map[string]int{},
+		visiting:  map[string]bool{},
+	}
+	for _, o := range options {
+		o(&m)
+	}
+
+	total := 0
+	for _, op := range doc.Operations {
+		total = saturating.Add(total, m.selectionSet(op.SelectionSet))
+	}
+	return total
+}
+
+func (m *metrics) selectionSet(set ast.SelectionSet) int {
+	total := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if m.match(sel) {
+				total = saturating.Add(total, 1)
+			}
+			total = saturating.Add(total, m.selectionSet(sel.SelectionSet))
+		case *ast.InlineFragment:
+			total = saturating.Add(total, m.selectionSet(sel.SelectionSet))
+		case *ast.FragmentSpread:
+			total = saturating.Add(total, m.fragment(sel.Name))
+		}
+	}
+	return total
+}
+
+// fragment returns the count for a spread of the named fragment.
+func (m *metrics) fragment(name string) int {
+	count, ok := m.fragments[name]
+	if ok {
+		if m.once {
+			return 0
+		}
+		return count
+	}
+	def := m.doc.Fragments.ForName(name)
+	if def == nil || m.visiting[name] {
+		return 0
+	}
+
+	m.visiting[name] = true
+	count = m.selectionSet(def.SelectionSet)
+	delete(m.visiting, name)
+
+	m.fragments[name] = count
+	return count
+}
@@ -0,0 +1,51 @@
+package complexity_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/complexity"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestMetrics(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query A {
+			me: user(id: 1) { ...F }
+			other: user(id: 2) { id ...F }
+		}
+		query B { users { ... on User { ...F } } }
+		fragment F on User { name best: friends(first: 1) { ...G } }
+		fragment G on User { id tags }
+	`})
+	require.NoError(t, err)
+
+	require.Equal(t, 3, complexity.MaxDepth(doc))
+
+	// A: 2 roots + 1 + 2 F, B: 1 root + F, F: 2 fields + G, G: 2 fields
+	require.Equal(t, 2+1+2*4+1+4, complexity.FieldCount(doc))
+	require.Equal(t, 2+1+1+2+2, complexity.FieldCount(doc, complexity.CountFragmentsOnce()))
+
+	require.Equal(t, 2+3, complexity.AliasCount(doc))
+	require.Equal(t, 2+1, complexity.AliasCount(doc, complexity.CountFragmentsOnce()))
+}
+
+func TestMetricsFragmentBomb(t *testing.T) {
+	// every fragment spreads the next one twice, the document is small but expands exponentially.
+	var query strings.Builder
+	query.WriteString("{ ...F0 }\n")
+	for i := 0; i < 80; i++ {
+		query.WriteString("fragment F" + strconv.Itoa(i) + " on Query { a: f ...F" + strconv.Itoa(i+1) + " ...F" + strconv.Itoa(i+1) + " }\n")
+	}
+	query.WriteString("fragment F80 on Query { f }\n")
+	doc, err := parser.ParseQuery(&ast.Source{Input: query.String()})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, complexity.MaxDepth(doc))
+	require.Equal(t, 81, complexity.FieldCount(doc, complexity.CountFragmentsOnce()))
+	require.Greater(t, complexity.FieldCount(doc), 1<<62)
+	require.Equal(t, 80, complexity.AliasCount(doc, complexity.CountFragmentsOnce()))
+}
@@ -0,0 +1,337 @@
+// Package docs extracts a documentation model from a schema: its types, fields, arguments,
+// descriptions, deprecations and directives, with the references between them resolved.
+//
+// The model is meant to feed static documentation generators, through templates or encoded as
+// JSON, without them walking the AST or computing which fields use a type.
+package docs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Schema is the documentation of a schema. Types and directives are sorted by name, and built in
+// ones, such as String or @deprecated, are left out.
+type Schema struct {
+	Description      string        `json:"description,omitempty"`
+	QueryType        string        `json:"queryType,omitempty"`
+	MutationType     string        `json:"mutationType,omitempty"`
+	SubscriptionType string        `json:"subscriptionType,omitempty"`
+	Directives       []*Annotation `json:"directives,omitempty"`
+
+	Types                []*Type      `json:"types"`
+	DirectiveDefinitions []*Directive `json:"directiveDefinitions,omitempty"`
+}
+
+// Type returns the documentation of the named type, or nil.
+func (s *Schema) Type(name string) *Type {
+	i := sort.Search(len(s.Types), func(i int) bool { return s.Types[i].Name >= name })
+	if i < len(s.Types) && s.Types[i].Name == name {
+		return s.Types[i]
+	}
+	return nil
+}
+
+// Type is the documentation of a named type.
+type Type struct {
+	Name        string             `json:"name"`
+	Kind        ast.DefinitionKind `json:"kind"`
+	Description string             `json:"description,omitempty"`
+	Directives  []*Annotation      `json:"directives,omitempty"`
+	Location    *Location          `json:"location,omitempty"`
+
+	// Fields holds the fields of objects, interfaces and input objects.
+	Fields     []*Field     `json:"fields,omitempty"`
+	EnumValues []*EnumValue `json:"enumValues,omitempty"`
+	// Interfaces holds the interfaces implemented by an object or interface.
+	Interfaces []string `json:"interfaces,omitempty"`
+	// PossibleTypes holds the members of a union, or the objects implementing an interface.
+	PossibleTypes []string `json:"possibleTypes,omitempty"`
+	// ReferencedBy holds the fields, arguments and input fields of the schema using the type,
+	// sorted.
+	ReferencedBy []Reference `json:"referencedBy,omitempty"`
+}
+
+// Field is the documentation of a field or input field. Arguments are only set on fields, and
+// default values only on input fields.
+type Field struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description,omitempty"`
+	Type              TypeRef       `json:"type"`
+	Arguments         []*Argument   `json:"arguments,omitempty"`
+	DefaultValue      string        `json:"defaultValue,omitempty"`
+	Deprecated        bool          `json:"deprecated,omitempty"`
+	DeprecationReason string        `json:"deprecationReason,omitempty"`
+	Directives        []*Annotation `json:"directives,omitempty"`
+	Location          *Location     `json:"location,omitempty"`
+}
+
+// Argument is the documentation of an argument of a field or directive.
+type Argument struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description,omitempty"`
+	Type              TypeRef       `json:"type"`
+	DefaultValue      string        `json:"defaultValue,omitempty"`
+	Deprecated        bool          `json:"deprecated,omitempty"`
+	DeprecationReason string        `json:"deprecationReason,omitempty"`
+	Directives        []*Annotation `json:"directives,omitempty"`
+}
+
+// EnumValue is the documentation of an enum value.
+type EnumValue struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description,omitempty"`
+	Deprecated        bool          `json:"deprecated,omitempty"`
+	DeprecationReason string        `json:"deprecationReason,omitempty"`
+	Directives        []*Annotation `json:"directives,omitempty"`
+}
+
+// Directive is the documentation of a directive definition.
+type Directive struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Arguments   []*Argument `json:"arguments,omitempty"`
+	Locations   []string    `json:"locations"`
+	Repeatable  bool        `json:"repeatable,omitempty"`
+	Location    *Location   `json:"location,omitempty"`
+}
+
+// Annotation is a directive applied to a definition, other than @deprecated which is documented
+// by the Deprecated fields.
+type Annotation struct {
+	Name      string               `json:"name"`
+	Arguments []AnnotationArgument `json:"arguments,omitempty"`
+}
+
+// AnnotationArgument is an argument given to an applied directive, with its value printed as
+// GraphQL.
+type AnnotationArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TypeRef is a reference to a type, such as [User!]!.
+type TypeRef struct {
+	// Name is the named type at the bottom of the lists and non-null wrappers, to link to.
+	Name string `json:"name"`
+	// String is the type as written in GraphQL.
+	String string `json:"string"`
+	// BuiltIn is set for the types that are left out of Schema.Types.
+	BuiltIn bool `json:"builtIn,omitempty"`
+}
+
+// Reference is a use of a type by the field Type.Field, the argument Type.Field(Argument), or
+// the argument @Directive(Argument).
+type Reference struct {
+	Directive string `json:"directive,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Argument  string `json:"argument,omitempty"`
+}
+
+func (r Reference) String() string {
+	s := r.Type + "." + r.Field
+	if r.Directive != "" {
+		s = "@" + r.Directive
+	}
+	if r.Argument != "" {
+		s += "(" + r.Argument + ")"
+	}
+	return s
+}
+
+// Location is where a definition is in the schema sources, for linking to them.
+type Location struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// Extract returns the documentation of schema.
+func Extract(schema *ast.Schema) *Schema {
+	e := extractor{schema: schema, references: map[string][]Reference{}}
+	doc := &Schema{
+		Description: schema.Description,
+		Directives:  annotations(schema.SchemaDirectives),
+	}
+	if schema.Query != nil {
+		doc.QueryType = schema.Query.Name
+	}
+	if schema.Mutation != nil {
+		doc.MutationType = schema.Mutation.Name
+	}
+	if schema.Subscription != nil {
+		doc.SubscriptionType = schema.Subscription.Name
+	}
+
+	for _, def := range schema.Types {
+		if def.BuiltIn {
+			continue
+		}
+		doc.Types = append(doc.Types, e.typ(def))
+	}
+	sort.Slice(doc.Types, func(i, j int) bool { return doc.Types[i].Name < doc.Types[j].Name })
+
+	for _, def := range schema.Directives {
+		if isBuiltIn(def.Position) {
+			continue
+		}
+		dir := &Directive{
+			Name:        def.Name,
+			Description: def.Description,
+			Repeatable:  def.IsRepeatable,
+			Location:    location(def.Position),
+		}
+		for _, arg := range def.Arguments {
+			dir.Arguments = append(dir.Arguments, e.argument(arg, Reference{Directive: def.Name, Argument: arg.Name}))
+		}
+		for _, loc := range def.Locations {
+			dir.Locations = append(dir.Locations, string(loc))
+		}
+		doc.DirectiveDefinitions = append(doc.DirectiveDefinitions, dir)
+	}
+	sort.Slice(doc.DirectiveDefinitions, func(i, j int) bool {
+		return doc.DirectiveDefinitions[i].Name < doc.DirectiveDefinitions[j].Name
+	})
+
+	// references are only complete once every type has been seen
+	for _, typ := range doc.Types {
+		refs := e.references[typ.Name]
+		sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+		typ.ReferencedBy = refs
+	}
+	return doc
+}
+
+type extractor struct {
+	schema *ast.Schema
+	// references holds the uses of each type, by name
+	references map[string][]Reference
+}
+
+func (e *extractor) typ(def *ast.Definition) *Type {
+	typ := &Type{
+		Name:        def.Name,
+		Kind:        def.Kind,
+		Description: def.Description,
+		Directives:  annotations(def.Directives),
+		Location:    location(def.Position),
+		Interfaces:  def.Interfaces,
+	}
+	for _, field := range def.Fields {
+		// introspection fields such as __typename are added to the root types
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		typ.Fields = append(typ.Fields, e.field(def, field))
+	}
+	for _, value := range def.EnumValues {
+		v := &EnumValue{
+			Name:        value.Name,
+			Description: value.Description,
+			Directives:  annotations(value.Directives),
+		}
+		v.Deprecated, v.DeprecationReason = deprecation(value.Directives)
+		typ.EnumValues = append(typ.EnumValues, v)
+	}
+
+	switch def.Kind {
+	case ast.Union:
+		typ.PossibleTypes = def.Types
+	case ast.Interface:
+		for _, possible := range e.schema.GetPossibleTypes(def) {
+			typ.PossibleTypes = append(typ.PossibleTypes, possible.Name)
+		}
+		sort.Strings(typ.PossibleTypes)
+	}
+	return typ
+}
+
+func (e *extractor) field(parent *ast.Definition, def *ast.FieldDefinition) *Field {
+	field := &Field{
+		Name:        def.Name,
+		Description: def.Description,
+		Type:        e.typeRef(def.Type, Reference{Type: parent.Name, Field: def.Name}),
+		Directives:  annotations(def.Directives),
+		Location:    location(def.Position),
+	}
+	if def.DefaultValue != nil {
+		field.DefaultValue = def.DefaultValue.String()
+	}
+	field.Deprecated, field.DeprecationReason = deprecation(def.Directives)
+	for _, arg := range def.Arguments {
+		field.Arguments = append(field.Arguments, e.argument(arg, Reference{Type: parent.Name, Field: def.Name, Argument: arg.Name}))
+	}
+	return field
+}
+
+func (e *extractor) argument(def *ast.ArgumentDefinition, ref Reference) *Argument {
+	arg := &Argument{
+		Name:        def.Name,
+		Description: def.Description,
+		Type:        e.typeRef(def.Type, ref),
+		Directives:  annotations(def.Directives),
+	}
+	if def.DefaultValue != nil {
+		arg.DefaultValue = def.DefaultValue.String()
+	}
+	arg.Deprecated, arg.DeprecationReason = deprecation(def.Directives)
+	return arg
+}
+
+// typeRef returns a reference to t, recording that it is used by ref.
+func (e *extractor) typeRef(t *ast.Type, ref Reference) TypeRef {
+	name := t.Name()
+	e.references[name] = append(e.references[name], ref)
+	def := e.schema.Types[name]
+	return TypeRef{
+		Name:    name,
+		String:  t.String(),
+		BuiltIn: def == nil || def.BuiltIn,
+	}
+}
+
+func annotations(directives ast.DirectiveList) []*Annotation {
+	var result []*Annotation
+	for _, dir := range directives {
+		if dir.Name == "deprecated" {
+			continue
+		}
+		a := &Annotation{Name: dir.Name}
+		for _, arg := range dir.Arguments {
+			a.Arguments = append(a.Arguments, AnnotationArgument{Name: arg.Name, Value: arg.Value.String()})
+		}
+		result = append(result, a)
+	}
+	return result
+}
+
+// deprecation reports whether directives hold @deprecated, and its reason. The default reason is
+// used when none is given, like in introspection.
+func deprecation(directives ast.DirectiveList) (bool, string) {
+	deprecated := directives.ForName("deprecated")
+	if deprecated == nil {
+		return false, ""
+	}
+	if reason := deprecated.Arguments.ForName("reason"); reason != nil && reason.Value != nil {
+		return true, reason.Value.Raw
+	}
+	return true, "No longer supported"
+}
+
+func isBuiltIn(pos *ast.Position) bool {
+	return pos != nil && pos.Src != nil && pos.Src.BuiltIn
+}
+
+func location(pos *ast.Position) *Location {
+	if pos == nil || isBuiltIn(pos) {
+		return nil
+	}
+	loc := &Location{Line: pos.Line, Column: pos.Column}
+	if pos.Src != nil {
+		loc.File = pos.Src.Name
+	}
+	return loc
+}
@@ -0,0 +1,152 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/docs"
+)
+
+func TestExtract(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `"The API."
+schema @link(url: "https://example.com") { query: Query }
+
+directive @link(url: String!) on SCHEMA
+"Marks fields only admins can see."
+directive @admin(role: Role = ADMIN) repeatable on FIELD_DEFINITION | OBJECT
+
+type Query {
+  "Finds a user."
+  user(id: ID!, "Whether to include deleted users." deleted: Boolean = false @deprecated): User
+  search(text: String!): [SearchResult!]!
+  node(id: ID!): Node @deprecated(reason: "Use user.")
+}
+
+"Something with an id."
+interface Node { id: ID! }
+
+type User implements Node @admin {
+  id: ID!
+  role: Role! @admin(role: OWNER)
+  friends: [User]
+}
+
+type Post implements Node { id: ID! author: User }
+
+union SearchResult = User | Post
+
+enum Role {
+  ADMIN
+  OWNER @deprecated
+  MEMBER
+}
+
+input Filter { role: Role = MEMBER }
+`})
+
+	doc := docs.Extract(schema)
+	require.Equal(t, "The API.", doc.Description)
+	require.Equal(t, "Query", doc.QueryType)
+	require.Empty(t, doc.MutationType)
+	require.Equal(t, []*docs.Annotation{{Name: "link", Arguments: []docs.AnnotationArgument{{Name: "url", Value: `"https://example.com"`}}}}, doc.Directives)
+
+	var names []string
+	for _, typ := range doc.Types {
+		names = append(names, typ.Name)
+	}
+	require.Equal(t, []string{"Filter", "Node", "Post", "Query", "Role", "SearchResult", "User"}, names)
+	require.Nil(t, doc.Type("String"))
+	require.Nil(t, doc.Type("__Schema"))
+
+	t.Run("fields and arguments", func(t *testing.T) {
+		query := doc.Type("Query")
+		require.Equal(t, ast.Object, query.Kind)
+		require.Equal(t, &docs.Location{File: "schema.graphql", Line: 8, Column: 6}, query.Location)
+		require.Len(t, query.Fields, 3)
+
+		user := query.Fields[0]
+		require.Equal(t, "Finds a user.", user.Description)
+		require.Equal(t, docs.TypeRef{Name: "User", String: "User"}, user.Type)
+		require.Len(t, user.Arguments, 2)
+		require.Equal(t, docs.TypeRef{Name: "ID", String: "ID!", BuiltIn: true}, user.Arguments[0].Type)
+		deleted := user.Arguments[1]
+		require.Equal(t, "Whether to include deleted users.", deleted.Description)
+		require.Equal(t, "false", deleted.DefaultValue)
+		require.True(t, deleted.Deprecated)
+		require.Equal(t, "No longer supported", deleted.DeprecationReason)
+		require.Empty(t, deleted.Directives)
+
+		require.Equal(t, docs.TypeRef{Name: "SearchResult", String: "[SearchResult!]!"}, query.Fields[1].Type)
+		require.True(t, query.Fields[2].Deprecated)
+		require.Equal(t, "Use user.", query.Fields[2].DeprecationReason)
+
+		filter := doc.Type("Filter")
+		require.Equal(t, "MEMBER", filter.Fields[0].DefaultValue)
+	})
+
+	t.Run("abstract types", func(t *testing.T) {
+		require.Equal(t, []string{"Post", "User"}, doc.Type("Node").PossibleTypes)
+		require.Equal(t, "Something with an id.", doc.Type("Node").Description)
+		require.Equal(t, []string{"User", "Post"}, doc.Type("SearchResult").PossibleTypes)
+		require.Equal(t, []string{"Node"}, doc.Type("User").Interfaces)
+	})
+
+	t.Run("enums and annotations", func(t *testing.T) {
+		role := doc.Type("Role")
+		require.Len(t, role.EnumValues, 3)
+		require.False(t, role.EnumValues[0].Deprecated)
+		require.True(t, role.EnumValues[1].Deprecated)
+
+		user := doc.Type("User")
+		require.Equal(t, []*docs.Annotation{{Name: "admin"}}, user.Directives)
+		require.Equal(t, []*docs.Annotation{{Name: "admin", Arguments: []docs.AnnotationArgument{{Name: "role", Value: "OWNER"}}}}, user.Fields[1].Directives)
+	})
+
+	t.Run("references", func(t *testing.T) {
+		require.Equal(t, []docs.Reference{
+			{Type: "Post", Field: "author"},
+			{Type: "Query", Field: "user"},
+			{Type: "User", Field: "friends"},
+		}, doc.Type("User").ReferencedBy)
+		require.Equal(t, []docs.Reference{
+			{Directive: "admin", Argument: "role"},
+			{Type: "Filter", Field: "role"},
+			{Type: "User", Field: "role"},
+		}, doc.Type("Role").ReferencedBy)
+		require.Equal(t, "@admin(role)", doc.Type("Role").ReferencedBy[0].String())
+		require.Empty(t, doc.Type("Query").ReferencedBy)
+	})
+
+	t.Run("directives", func(t *testing.T) {
+		require.Len(t, doc.DirectiveDefinitions, 2)
+		admin := doc.DirectiveDefinitions[0]
+		require.Equal(t, "admin", admin.Name)
+		require.Equal(t, "Marks fields only admins can see.", admin.Description)
+		require.True(t, admin.Repeatable)
+		require.Equal(t, []string{"FIELD_DEFINITION", "OBJECT"}, admin.Locations)
+		require.Equal(t, "ADMIN", admin.Arguments[0].DefaultValue)
+		require.Equal(t, "link", doc.DirectiveDefinitions[1].Name)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(doc.Type("Node"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"name": "Node",
+			"kind": "INTERFACE",
+			"description": "Something with an id.",
+			"location": {"file": "schema.graphql", "line": 16, "column": 11},
+			"fields": [{
+				"name": "id",
+				"type": {"name": "ID", "string": "ID!", "builtIn": true},
+				"location": {"file": "schema.graphql", "line": 16, "column": 18}
+			}],
+			"possibleTypes": ["Post", "User"],
+			"referencedBy": [{"type": "Query", "field": "node"}]
+		}`, string(b))
+	})
+}
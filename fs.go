@@ -0,0 +1,198 @@
+package gqlparser
+
+import (
+	"io/fs"
+	"path"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// LoadSchemaFS walks fsys, parses every file matching pattern and loads the merged result as a
+// single schema.
+//
+// pattern uses path.Match syntax. Patterns containing a slash are matched against the full
+// slash separated path of the file, all other patterns are matched against the base name, so
+// "*.graphql" finds schema files at any depth. A pattern that matches no files is an error.
+//
+// Files are read and parsed concurrently. If any file fails to parse the returned error is a
+// gqlerror.List holding one error per broken file, in path order.
+func LoadSchemaFS(fsys fs.FS, pattern string) (*ast.Schema, error) {
+	paths, err := GlobFS(fsys, pattern)
+	if err != nil {
+		return nil, gqlerror.Wrap(err)
+	}
+	if len(paths) == 0 {
+		return nil, gqlerror.Errorf("pattern %s matches no files", strconv.Quote(pattern))
+	}
+
+	docs, errs := parseSchemaFiles(fsys, paths)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	merged := &ast.SchemaDocument{}
+	for _, doc := range docs {
+		merged.Merge(doc)
+	}
+	return LoadSchemaDocument(merged)
+}
+
+// LoadSchemaDocument loads doc, such as one returned by LoadSources, as a schema along with the
+// prelude, like LoadSchema does with sources.
+func LoadSchemaDocument(doc *ast.SchemaDocument) (*ast.Schema, error) {
+	prelude, err := parser.ParseSchema(validator.Prelude)
+	if err != nil {
+		return nil, gqlerror.WrapIfUnwrapped(err)
+	}
+	prelude.Merge(doc)
+
+	schema, err := validator.ValidateSchemaDocument(prelude)
+	if err != nil {
+		return schema, gqlerror.WrapIfUnwrapped(err)
+	}
+	return schema, nil
+}
+
+// LoadSources parses every file in fsys matching one of patterns and returns their definitions
+// merged in a single document, without validating it. It suits schemas embedded with go:embed
+// that need more than LoadSchemaFS, such as adding definitions before loading the schema with
+// validator.ValidateSchemaDocument.
+//
+// patterns use the syntax described on LoadSchemaFS. Files matched by several patterns are only
+// read once, and a pattern that matches no files is an error. Every definition keeps the position
+// in the file it came from. If any file fails to parse the returned error is a gqlerror.List
+// holding one error per broken file, in path order.
+func LoadSources(fsys fs.FS, patterns ...string) (*ast.SchemaDocument, error) {
+	seen := map[string]bool{}
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := GlobFS(fsys, pattern)
+		if err != nil {
+			return nil, gqlerror.Errorf("pattern %s: %s", strconv.Quote(pattern), err)
+		}
+		if len(matches) == 0 {
+			return nil, gqlerror.Errorf("pattern %s matches no files", strconv.Quote(pattern))
+		}
+		for _, p := range matches {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	docs, errs := parseSchemaFiles(fsys, paths)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	merged := &ast.SchemaDocument{}
+	for _, doc := range docs {
+		merged.Merge(doc)
+	}
+	return merged, nil
+}
+
+// GlobFS returns the sorted paths of all regular files in fsys matching pattern, which uses the
+// syntax described on LoadSchemaFS. Unlike fs.Glob, patterns without a slash match files at any
+// depth.
+func GlobFS(fsys fs.FS, pattern string) ([]string, error) {
+	// validate the pattern up front, path.Match only reports bad patterns when it gets far enough
+	// into the name to notice.
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		if MatchPath(pattern, p) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// MatchPath reports whether the slash separated path p matches pattern, the way LoadSchemaFS and
+// LoadSources match files. Malformed patterns match nothing.
+func MatchPath(pattern, p string) bool {
+	name := p
+	if !strings.Contains(pattern, "/") {
+		name = path.Base(p)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// parseSchemaFiles reads and parses the given paths using a pool of workers. The returned
+// documents are in the same order as paths, regardless of which worker finished first.
+func parseSchemaFiles(fsys fs.FS, paths []string) ([]*ast.SchemaDocument, gqlerror.List) {
+	docs := make([]*ast.SchemaDocument, len(paths))
+	errs := make([]*gqlerror.Error, len(paths))
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				docs[i], errs[i] = parseSchemaFile(fsys, paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var list gqlerror.List
+	for _, err := range errs {
+		if err != nil {
+			list = append(list, err)
+		}
+	}
+	return docs, list
+}
+
+func parseSchemaFile(fsys fs.FS, name string) (*ast.SchemaDocument, *gqlerror.Error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		gqlErr := gqlerror.Wrap(err)
+		gqlErr.SetFile(name)
+		return nil, gqlErr
+	}
+
+	doc, err := parser.ParseSchema(&ast.Source{Name: name, Input: string(b)})
+	if err != nil {
+		return nil, gqlerror.WrapIfUnwrapped(err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,137 @@
+package gqlparser_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestLoadSchemaFS(t *testing.T) {
+	t.Run("merges matching files", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"schema/query.graphql":       {Data: []byte("type Query { user: User }")},
+			"schema/types/user.graphql":  {Data: []byte("type User { id: ID! }")},
+			"schema/types/extra.graphql": {Data: []byte("extend type User { name: String }")},
+			"schema/readme.md":           {Data: []byte("# not a schema")},
+		}
+
+		s, err := gqlparser.LoadSchemaFS(fsys, "*.graphql")
+		require.NoError(t, err)
+		require.Equal(t, "Query", s.Query.Name)
+		require.NotNil(t, s.Types["User"].Fields.ForName("id"))
+		require.NotNil(t, s.Types["User"].Fields.ForName("name"))
+	})
+
+	t.Run("matches full paths when the pattern has a slash", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a/query.graphql": {Data: []byte("type Query { a: String }")},
+			"b/query.graphql": {Data: []byte("type Query { b: String }")},
+		}
+
+		s, err := gqlparser.LoadSchemaFS(fsys, "a/*.graphql")
+		require.NoError(t, err)
+		require.NotNil(t, s.Query.Fields.ForName("a"))
+		require.Nil(t, s.Query.Fields.ForName("b"))
+	})
+
+	t.Run("reports an error per broken file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("type Query {")},
+			"b.graphql": {Data: []byte("type User { id: ID! }")},
+			"c.graphql": {Data: []byte("type Foo {}")},
+		}
+
+		_, err := gqlparser.LoadSchemaFS(fsys, "*.graphql")
+		require.Error(t, err)
+
+		var list gqlerror.List
+		require.ErrorAs(t, err, &list)
+		require.Len(t, list, 2)
+		require.Equal(t, "a.graphql", list[0].Extensions["file"])
+		require.Equal(t, "c.graphql", list[1].Extensions["file"])
+	})
+
+	t.Run("validates the merged schema", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("type Query { a: String }")},
+			"b.graphql": {Data: []byte("type Query { b: String }")},
+		}
+
+		_, err := gqlparser.LoadSchemaFS(fsys, "*.graphql")
+		require.EqualError(t, err, "b.graphql:1:6: Cannot redeclare type Query.")
+	})
+
+	t.Run("pattern matching nothing", func(t *testing.T) {
+		_, err := gqlparser.LoadSchemaFS(fstest.MapFS{"schema.graphql": {Data: []byte("type Query { a: String }")}}, "*.graphqls")
+		require.EqualError(t, err, `input: pattern "*.graphqls" matches no files`)
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		_, err := gqlparser.LoadSchemaFS(fstest.MapFS{}, "[")
+		require.Error(t, err)
+	})
+}
+
+func TestLoadSources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"schema/query.graphql":        {Data: []byte("type Query { user: User }")},
+		"schema/types/user.graphql":   {Data: []byte("type User { id: ID! }")},
+		"schema/types/extra.graphqls": {Data: []byte("extend type User { name: String }")},
+		"schema/readme.md":            {Data: []byte("# not a schema")},
+	}
+
+	t.Run("merges files matching any pattern", func(t *testing.T) {
+		doc, err := gqlparser.LoadSources(fsys, "*.graphql", "*.graphqls", "schema/query.graphql")
+		require.NoError(t, err)
+		require.Len(t, doc.Definitions, 2)
+		require.Len(t, doc.Extensions, 1)
+		require.Equal(t, "schema/query.graphql", doc.Definitions.ForName("Query").Position.Src.Name)
+		require.Equal(t, "schema/types/user.graphql", doc.Definitions.ForName("User").Position.Src.Name)
+		require.Equal(t, "schema/types/extra.graphqls", doc.Extensions[0].Position.Src.Name)
+	})
+
+	t.Run("loads as a schema", func(t *testing.T) {
+		doc, err := gqlparser.LoadSources(fsys, "*.graphql", "*.graphqls")
+		require.NoError(t, err)
+		s, err := gqlparser.LoadSchemaDocument(doc)
+		require.NoError(t, err)
+		require.Equal(t, "Query", s.Query.Name)
+		require.NotNil(t, s.Types["User"].Fields.ForName("name"))
+		require.NotNil(t, s.Types["String"])
+	})
+
+	t.Run("pattern matching nothing", func(t *testing.T) {
+		_, err := gqlparser.LoadSources(fsys, "*.graphql", "*.gql")
+		require.EqualError(t, err, `input: pattern "*.gql" matches no files`)
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		_, err := gqlparser.LoadSources(fsys, "[")
+		require.EqualError(t, err, `input: pattern "[": syntax error in pattern`)
+	})
+
+	t.Run("reports an error per broken file", func(t *testing.T) {
+		_, err := gqlparser.LoadSources(fstest.MapFS{
+			"a.graphql": {Data: []byte("type Query {")},
+			"b.graphql": {Data: []byte("type Foo {}")},
+		}, "*.graphql")
+
+		var list gqlerror.List
+		require.ErrorAs(t, err, &list)
+		require.Len(t, list, 2)
+		require.Equal(t, "a.graphql", list[0].Extensions["file"])
+		require.Equal(t, "b.graphql", list[1].Extensions["file"])
+	})
+}
+
+func TestMatchPath(t *testing.T) {
+	require.True(t, gqlparser.MatchPath("*.graphql", "schema.graphql"))
+	require.True(t, gqlparser.MatchPath("*.graphql", "schema/types/user.graphql"))
+	require.True(t, gqlparser.MatchPath("schema/*.graphql", "schema/query.graphql"))
+	require.False(t, gqlparser.MatchPath("schema/*.graphql", "schema/types/user.graphql"))
+	require.False(t, gqlparser.MatchPath("*.graphql", "readme.md"))
+	require.False(t, gqlparser.MatchPath("[", "["))
+}
@@ -0,0 +1,48 @@
+package gqlerror
+
+import "errors"
+
+// Sentinel categories matched by errors.Is, so callers can branch on the kind of failure without
+// matching messages:
+//
+//	if errors.Is(err, gqlerror.ErrSyntax) {
+//		...
+//	}
+//
+// The category of an Error is derived from its code, see Code. These work through a List too,
+// which matches if any of its errors do.
+var (
+	// ErrSyntax matches errors with CodeParseFailed.
+	ErrSyntax = errors.New("syntax error")
+	// ErrValidation matches errors reported by validation rules, and errors with
+	// CodeValidationFailed, CodeSchemaInvalid or CodeBadUserInput.
+	ErrValidation = errors.New("validation error")
+	// ErrLimitExceeded matches errors with CodeLimitExceeded.
+	ErrLimitExceeded = errors.New("limit exceeded")
+	// ErrUnsupportedFeature matches errors with CodeUnsupportedFeature.
+	ErrUnsupportedFeature = errors.New("unsupported feature")
+	// ErrInternal matches errors with CodeInternal.
+	ErrInternal = errors.New("internal error")
+)
+
+// Is reports whether err belongs to the target category. It is used by errors.Is, which falls
+// back to the wrapped error when the target is not a category.
+func (err *Error) Is(target error) bool {
+	switch target {
+	case ErrSyntax:
+		return err.Code() == CodeParseFailed
+	case ErrValidation:
+		switch err.Code() {
+		case CodeValidationFailed, CodeSchemaInvalid, CodeBadUserInput:
+			return true
+		}
+		return err.Rule != ""
+	case ErrLimitExceeded:
+		return err.Code() == CodeLimitExceeded
+	case ErrUnsupportedFeature:
+		return err.Code() == CodeUnsupportedFeature
+	case ErrInternal:
+		return err.Code() == CodeInternal
+	}
+	return false
+}
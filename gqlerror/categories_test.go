@@ -0,0 +1,45 @@
+package gqlerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories(t *testing.T) {
+	withCode := func(code string) *Error {
+		err := Errorf("kabloom")
+		err.SetCode(code)
+		return err
+	}
+
+	require.ErrorIs(t, withCode(CodeParseFailed), ErrSyntax)
+	require.ErrorIs(t, withCode(CodeValidationFailed), ErrValidation)
+	require.ErrorIs(t, withCode(CodeSchemaInvalid), ErrValidation)
+	require.ErrorIs(t, withCode(CodeBadUserInput), ErrValidation)
+	require.ErrorIs(t, &Error{Message: "kabloom", Rule: "FieldsOnCorrectType"}, ErrValidation)
+	require.ErrorIs(t, withCode(CodeLimitExceeded), ErrLimitExceeded)
+	require.ErrorIs(t, withCode(CodeUnsupportedFeature), ErrUnsupportedFeature)
+	require.ErrorIs(t, withCode(CodeInternal), ErrInternal)
+
+	require.NotErrorIs(t, withCode(CodeParseFailed), ErrValidation)
+	require.NotErrorIs(t, withCode(CodeInternal), ErrSyntax)
+	require.NotErrorIs(t, Errorf("kabloom"), ErrSyntax)
+
+	t.Run("through lists and wrapping", func(t *testing.T) {
+		list := List{Errorf("a"), withCode(CodeLimitExceeded)}
+		require.ErrorIs(t, list, ErrLimitExceeded)
+		require.ErrorIs(t, fmt.Errorf("loading: %w", list), ErrLimitExceeded)
+		require.NotErrorIs(t, list, ErrSyntax)
+	})
+
+	t.Run("wrapped errors are still matched", func(t *testing.T) {
+		require.ErrorIs(t, Wrap(underlyingError), underlyingError)
+
+		var gqlErr *Error
+		require.True(t, errors.As(fmt.Errorf("loading: %w", withCode(CodeParseFailed)), &gqlErr))
+		require.ErrorIs(t, gqlErr, ErrSyntax)
+	})
+}
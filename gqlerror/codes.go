@@ -0,0 +1,71 @@
+package gqlerror
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Stable, machine readable error codes. The code of an error is stored under the "code" key of
+// its Extensions, so clients can categorise failures without matching on messages.
+//
+// Errors reported by validation rules use the rule name in upper snake case instead, for example
+// the FieldsOnCorrectType rule reports FIELDS_ON_CORRECT_TYPE, see RuleCode.
+const (
+	// CodeParseFailed is used for syntax errors in a query or schema document.
+	CodeParseFailed = "GRAPHQL_PARSE_FAILED"
+	// CodeValidationFailed is used for validation errors not produced by a specific rule.
+	CodeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	// CodeSchemaInvalid is used when a schema document does not describe a valid schema.
+	CodeSchemaInvalid = "GRAPHQL_SCHEMA_INVALID"
+	// CodeBadUserInput is used when variable values can't be coerced to their declared types.
+	CodeBadUserInput = "BAD_USER_INPUT"
+	// CodeLimitExceeded is used when a document exceeds a configured size or complexity limit.
+	CodeLimitExceeded = "LIMIT_EXCEEDED"
+	// CodeUnsupportedFeature is used for valid GraphQL that this package does not support, such as
+	// type system definitions in a query document.
+	CodeUnsupportedFeature = "UNSUPPORTED_FEATURE"
+	// CodeInternal is used for bugs in this package caught at run time, such as a recovered panic
+	// while parsing. The input is not to blame.
+	CodeInternal = "INTERNAL_SERVER_ERROR"
+)
+
+// SetCode stores a machine readable code in the error's extensions.
+func (err *Error) SetCode(code string) {
+	if code == "" {
+		return
+	}
+	if err.Extensions == nil {
+		err.Extensions = map[string]interface{}{}
+	}
+
+	err.Extensions["code"] = code
+}
+
+// Code returns the machine readable code of the error, or "" if it has none.
+func (err *Error) Code() string {
+	if err == nil {
+		return ""
+	}
+	code, _ := err.Extensions["code"].(string)
+	return code
+}
+
+// RuleCode converts a validation rule name into the code used for its errors, eg
+// "FieldsOnCorrectType" becomes "FIELDS_ON_CORRECT_TYPE".
+func RuleCode(rule string) string {
+	var b strings.Builder
+	b.Grow(len(rule) + 4)
+
+	runes := []rune(rule)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package gqlerror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleCode(t *testing.T) {
+	for rule, code := range map[string]string{
+		"FieldsOnCorrectType":       "FIELDS_ON_CORRECT_TYPE",
+		"NoUnusedVariables":         "NO_UNUSED_VARIABLES",
+		"KnownRootType":             "KNOWN_ROOT_TYPE",
+		"UniqueInputFieldNames":     "UNIQUE_INPUT_FIELD_NAMES",
+		"MaxAliasesPerSelectionSet": "MAX_ALIASES_PER_SELECTION_SET",
+		"NoGraphQLIntrospection":    "NO_GRAPH_QL_INTROSPECTION",
+		"Rule2Name":                 "RULE2_NAME",
+		"already_snake":             "ALREADY_SNAKE",
+		"":                          "",
+	} {
+		require.Equal(t, code, RuleCode(rule), rule)
+	}
+}
+
+func TestCode(t *testing.T) {
+	err := Errorf("kabloom")
+	require.Equal(t, "", err.Code())
+
+	err.SetCode(CodeParseFailed)
+	require.Equal(t, CodeParseFailed, err.Code())
+	require.Equal(t, CodeParseFailed, err.Extensions["code"])
+
+	var nilErr *Error
+	require.Equal(t, "", nilErr.Code())
+}
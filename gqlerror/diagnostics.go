@@ -0,0 +1,91 @@
+package gqlerror
+
+// Severity tells apart errors that make a document unusable from diagnostics callers may only
+// want to log.
+type Severity int
+
+const (
+	// SeverityError is the severity of every error unless set otherwise.
+	SeverityError Severity = iota
+	// SeverityWarning is used for problems that don't stop a document from being executed, such
+	// as the use of deprecated fields.
+	SeverityWarning
+	// SeverityNotice is used for informational diagnostics.
+	SeverityNotice
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityNotice:
+		return "notice"
+	default:
+		return "unknown"
+	}
+}
+
+// SetSeverity changes the severity of the error, see Severity.
+func (err *Error) SetSeverity(severity Severity) {
+	err.severity = severity
+}
+
+// Severity returns the severity of the error, errors are SeverityError unless set otherwise.
+func (err *Error) Severity() Severity {
+	if err == nil {
+		return SeverityError
+	}
+	return err.severity
+}
+
+// Diagnostics holds everything reported about a document, split by severity so callers can fail
+// on Errors and log the rest without filtering by message.
+type Diagnostics struct {
+	Errors   List
+	Warnings List
+	Notices  List
+}
+
+// NewDiagnostics sorts errs into Diagnostics by their severity.
+func NewDiagnostics(errs List) Diagnostics {
+	var d Diagnostics
+	for _, err := range errs {
+		d.Add(err)
+	}
+	return d
+}
+
+// Add files err under its severity.
+func (d *Diagnostics) Add(err *Error) {
+	switch err.Severity() {
+	case SeverityWarning:
+		d.Warnings = append(d.Warnings, err)
+	case SeverityNotice:
+		d.Notices = append(d.Notices, err)
+	default:
+		d.Errors = append(d.Errors, err)
+	}
+}
+
+// HasErrors reports whether any fatal errors were found.
+func (d Diagnostics) HasErrors() bool {
+	return len(d.Errors) > 0
+}
+
+// Err returns the fatal errors as an error, or nil if there are none.
+func (d Diagnostics) Err() error {
+	if len(d.Errors) == 0 {
+		return nil
+	}
+	return d.Errors
+}
+
+// All returns every diagnostic, errors first, then warnings and notices.
+func (d Diagnostics) All() List {
+	all := make(List, 0, len(d.Errors)+len(d.Warnings)+len(d.Notices))
+	all = append(all, d.Errors...)
+	all = append(all, d.Warnings...)
+	return append(all, d.Notices...)
+}
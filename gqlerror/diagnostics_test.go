@@ -0,0 +1,32 @@
+package gqlerror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics(t *testing.T) {
+	warning := Errorf("deprecated")
+	warning.SetSeverity(SeverityWarning)
+	notice := Errorf("fyi")
+	notice.SetSeverity(SeverityNotice)
+	fatal := Errorf("boom")
+
+	d := NewDiagnostics(List{warning, fatal, notice})
+	require.Equal(t, List{fatal}, d.Errors)
+	require.Equal(t, List{warning}, d.Warnings)
+	require.Equal(t, List{notice}, d.Notices)
+	require.True(t, d.HasErrors())
+	require.EqualError(t, d.Err(), "input: boom\n")
+	require.Equal(t, List{fatal, warning, notice}, d.All())
+
+	d = NewDiagnostics(List{warning})
+	require.False(t, d.HasErrors())
+	require.NoError(t, d.Err())
+}
+
+func TestSeverity(t *testing.T) {
+	require.Equal(t, SeverityError, Errorf("boom").Severity())
+	require.Equal(t, "warning", SeverityWarning.String())
+}
@@ -2,6 +2,7 @@ package gqlerror
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -17,6 +18,10 @@ type Error struct {
 	Locations  []Location             `json:"locations,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
 	Rule       string                 `json:"-"`
+
+	nodes    []interface{}
+	severity Severity
+	fixes    []Fix
 }
 
 func (err *Error) SetFile(file string) {
@@ -30,9 +35,25 @@ func (err *Error) SetFile(file string) {
 	err.Extensions["file"] = file
 }
 
+// AddNodes records AST nodes the error was reported against, nil nodes are ignored.
+func (err *Error) AddNodes(nodes ...interface{}) {
+	for _, node := range nodes {
+		if node != nil {
+			err.nodes = append(err.nodes, node)
+		}
+	}
+}
+
+// Nodes returns the AST nodes the error was reported against, such as the *ast.Field of an unknown
+// field, so tooling can act on the document directly instead of resolving Locations back to nodes.
+// Nodes are not part of the serialized error.
+func (err *Error) Nodes() []interface{} {
+	return err.nodes
+}
+
 type Location struct {
-	Line   int `json:"line,omitempty"`
-	Column int `json:"column,omitempty"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
 
 type List []*Error
@@ -51,6 +72,10 @@ func (err *Error) Error() string {
 	if len(err.Locations) > 0 {
 		res.WriteByte(':')
 		res.WriteString(strconv.Itoa(err.Locations[0].Line))
+		if err.Locations[0].Column != 0 {
+			res.WriteByte(':')
+			res.WriteString(strconv.Itoa(err.Locations[0].Column))
+		}
 	}
 
 	res.WriteString(": ")
@@ -64,6 +89,22 @@ func (err *Error) Error() string {
 	return res.String()
 }
 
+// MarshalJSON encodes the error in the format required by the GraphQL response spec, with the
+// fields in the order recommended by the spec and empty fields left out.
+func (err Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Message    string                 `json:"message"`
+		Locations  []Location             `json:"locations,omitempty"`
+		Path       ast.Path               `json:"path,omitempty"`
+		Extensions map[string]interface{} `json:"extensions,omitempty"`
+	}{
+		Message:    err.Message,
+		Locations:  err.Locations,
+		Path:       err.Path,
+		Extensions: err.Extensions,
+	})
+}
+
 func (err *Error) pathString() string {
 	return err.Path.String()
 }
@@ -162,6 +203,12 @@ func ErrorPathf(path ast.Path, message string, args ...interface{}) *Error {
 }
 
 func ErrorPosf(pos *ast.Position, message string, args ...interface{}) *Error {
+	if pos == nil {
+		return Errorf(message, args...)
+	}
+	if pos.Src == nil {
+		return ErrorLocf("", pos.Line, pos.Column, message, args...)
+	}
 	return ErrorLocf(
 		pos.Src.Name,
 		pos.Line,
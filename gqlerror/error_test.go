@@ -1,6 +1,7 @@
 package gqlerror
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -34,17 +35,29 @@ func TestErrorFormatting(t *testing.T) {
 	t.Run("without filename", func(t *testing.T) {
 		err := ErrorLocf("", 66, 2, "kabloom")
 
-		require.Equal(t, `input:66: kabloom`, err.Error())
+		require.Equal(t, `input:66:2: kabloom`, err.Error())
 		require.Nil(t, err.Extensions["file"])
 	})
 
 	t.Run("with filename", func(t *testing.T) {
 		err := ErrorLocf("schema.graphql", 66, 2, "kabloom")
 
-		require.Equal(t, `schema.graphql:66: kabloom`, err.Error())
+		require.Equal(t, `schema.graphql:66:2: kabloom`, err.Error())
 		require.Equal(t, "schema.graphql", err.Extensions["file"])
 	})
 
+	t.Run("with position", func(t *testing.T) {
+		err := ErrorPosf(&ast.Position{Line: 3, Column: 7, Src: &ast.Source{Name: "schema.graphql"}}, "kabloom")
+
+		require.Equal(t, `schema.graphql:3:7: kabloom`, err.Error())
+		require.Equal(t, []Location{{Line: 3, Column: 7}}, err.Locations)
+	})
+
+	t.Run("without position", func(t *testing.T) {
+		require.Equal(t, `input: kabloom`, ErrorPosf(nil, "kabloom").Error())
+		require.Equal(t, `input:3:7: kabloom`, ErrorPosf(&ast.Position{Line: 3, Column: 7}, "kabloom").Error())
+	})
+
 	t.Run("with path", func(t *testing.T) {
 		err := ErrorPathf(ast.Path{ast.PathName("a"), ast.PathIndex(1), ast.PathName("b")}, "kabloom")
 
@@ -52,6 +65,44 @@ func TestErrorFormatting(t *testing.T) {
 	})
 }
 
+func TestErrorJSON(t *testing.T) {
+	t.Run("all fields", func(t *testing.T) {
+		err := ErrorLocf("schema.graphql", 66, 2, "kabloom")
+		err.Path = ast.Path{ast.PathName("a"), ast.PathIndex(1), ast.PathName("b")}
+		err.Rule = "SomeRule"
+		err.Err = underlyingError
+		err.AddNodes(&ast.Field{Name: "b"})
+
+		b, jsonErr := json.Marshal(err)
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"kabloom","locations":[{"line":66,"column":2}],"path":["a",1,"b"],"extensions":{"file":"schema.graphql"}}`, string(b))
+	})
+
+	t.Run("message only", func(t *testing.T) {
+		b, jsonErr := json.Marshal(Errorf("kabloom"))
+		require.NoError(t, jsonErr)
+		require.Equal(t, `{"message":"kabloom"}`, string(b))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		b, jsonErr := json.Marshal(List{Errorf("a"), ErrorLocf("", 1, 1, "b")})
+		require.NoError(t, jsonErr)
+		require.Equal(t, `[{"message":"a"},{"message":"b","locations":[{"line":1,"column":1}]}]`, string(b))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		err := ErrorLocf("schema.graphql", 66, 2, "kabloom")
+		err.Path = ast.Path{ast.PathName("a"), ast.PathIndex(1)}
+
+		b, jsonErr := json.Marshal(err)
+		require.NoError(t, jsonErr)
+
+		var decoded Error
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		require.Equal(t, err, &decoded)
+	})
+}
+
 func TestList_As(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,75 @@
+package gqlerror
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TextEdit replaces the text of a source between Start and End with NewText. Offsets are counted
+// in runes, like ast.Position, and End is exclusive. Insertions have Start equal to End.
+type TextEdit struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	NewText string `json:"newText"`
+}
+
+// Fix is a change repairing the problem reported by an error. Its edits apply to the source the
+// error is in.
+type Fix struct {
+	// Message describes the fix, such as `Replace with "name"`.
+	Message string     `json:"message"`
+	Edits   []TextEdit `json:"edits"`
+}
+
+// AddFix proposes fix for the error. Errors can have several fixes, which are alternatives to each
+// other, the first being the most likely.
+func (err *Error) AddFix(fix Fix) {
+	err.fixes = append(err.fixes, fix)
+}
+
+// Fixes returns the fixes proposed for the error. Like Nodes, they are not part of the serialized
+// error.
+func (err *Error) Fixes() []Fix {
+	if err == nil {
+		return nil
+	}
+	return err.fixes
+}
+
+// ApplyFixes applies edits to source and returns the result. Edits can be given in any order, and
+// edits repeated by several fixes are applied once. Edits that overlap, or insert at the same
+// offset, can't be applied together and are reported as an error.
+func ApplyFixes(source string, edits []TextEdit) (string, error) {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Start != sorted[j].Start {
+			return sorted[i].Start < sorted[j].Start
+		}
+		return sorted[i].End < sorted[j].End
+	})
+
+	runes := []rune(source)
+	var b strings.Builder
+	b.Grow(len(source))
+	offset := 0
+	for i, edit := range sorted {
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > len(runes) {
+			return "", fmt.Errorf("edit of %d:%d is outside of the source", edit.Start, edit.End)
+		}
+		if i > 0 {
+			prev := sorted[i-1]
+			if edit == prev {
+				continue
+			}
+			if edit.Start < prev.End || edit.Start == prev.Start {
+				return "", fmt.Errorf("edits of %d:%d and %d:%d overlap", prev.Start, prev.End, edit.Start, edit.End)
+			}
+		}
+		b.WriteString(string(runes[offset:edit.Start]))
+		b.WriteString(edit.NewText)
+		offset = edit.End
+	}
+	b.WriteString(string(runes[offset:]))
+	return b.String(), nil
+}
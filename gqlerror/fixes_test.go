@@ -0,0 +1,57 @@
+package gqlerror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixes(t *testing.T) {
+	err := Errorf("boom")
+	require.Empty(t, err.Fixes())
+	fix := Fix{Message: "Replace with \"name\"", Edits: []TextEdit{{Start: 2, End: 5, NewText: "name"}}}
+	err.AddFix(fix)
+	require.Equal(t, []Fix{fix}, err.Fixes())
+	b, jsonErr := err.MarshalJSON()
+	require.NoError(t, jsonErr)
+	require.NotContains(t, string(b), "edits")
+
+	var nilErr *Error
+	require.Nil(t, nilErr.Fixes())
+}
+
+func TestApplyFixes(t *testing.T) {
+	t.Run("edits in any order", func(t *testing.T) {
+		out, err := ApplyFixes("{ nam ü }", []TextEdit{
+			{Start: 9, End: 9, NewText: " id"},
+			{Start: 2, End: 5, NewText: "name"},
+			{Start: 6, End: 7, NewText: "u"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "{ name u } id", out)
+	})
+
+	t.Run("repeated edits", func(t *testing.T) {
+		edit := TextEdit{Start: 1, End: 1, NewText: " __typename"}
+		out, err := ApplyFixes("{ id }", []TextEdit{edit, edit})
+		require.NoError(t, err)
+		require.Equal(t, "{ __typename id }", out)
+	})
+
+	t.Run("no edits", func(t *testing.T) {
+		out, err := ApplyFixes("{ id }", nil)
+		require.NoError(t, err)
+		require.Equal(t, "{ id }", out)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		_, err := ApplyFixes("{ id }", []TextEdit{{Start: 0, End: 4}, {Start: 2, End: 6}})
+		require.EqualError(t, err, "edits of 0:4 and 2:6 overlap")
+
+		_, err = ApplyFixes("{ id }", []TextEdit{{Start: 2, End: 2, NewText: "a"}, {Start: 2, End: 2, NewText: "b"}})
+		require.EqualError(t, err, "edits of 2:2 and 2:2 overlap")
+
+		_, err = ApplyFixes("{ id }", []TextEdit{{Start: 4, End: 10}})
+		require.EqualError(t, err, "edit of 4:10 is outside of the source")
+	})
+}
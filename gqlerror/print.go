@@ -0,0 +1,191 @@
+package gqlerror
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Print renders err for humans, similar to printError in graphql-js. The message is followed by
+// an excerpt of the source around each location, with a caret under the offending column:
+//
+//	Cannot query field "nme" on type "User". Did you mean "name"?
+//
+//	query.graphql:3:5
+//	2 |   user {
+//	3 |     nme
+//	  |     ^
+//	4 |   }
+//
+// Lines longer than 120 characters, such as minified documents, are cut down to a window around
+// the column with the parts left out marked by "...".
+//
+// The source of the error is the one named by the "file" entry of its Extensions, as set by
+// SetFile, or the only source given when err has no file. When no matching source is given Print
+// falls back to err.Error().
+func Print(err *Error, sources ...*ast.Source) string {
+	if err == nil {
+		return ""
+	}
+
+	src := findSource(err, sources)
+	if src == nil || len(err.Locations) == 0 {
+		return err.Error()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(err.Message)
+	for _, loc := range err.Locations {
+		buf.WriteString("\n\n")
+		printLocation(&buf, src, loc)
+	}
+	return buf.String()
+}
+
+func findSource(err *Error, sources []*ast.Source) *ast.Source {
+	filename, _ := err.Extensions["file"].(string)
+	if filename == "" {
+		if len(sources) == 1 {
+			return sources[0]
+		}
+		return nil
+	}
+
+	for _, src := range sources {
+		if src != nil && src.Name == filename {
+			return src
+		}
+	}
+	return nil
+}
+
+func printLocation(buf *bytes.Buffer, src *ast.Source, loc Location) {
+	name := src.Name
+	if name == "" {
+		name = "input"
+	}
+	buf.WriteString(name)
+	buf.WriteByte(':')
+	buf.WriteString(strconv.Itoa(loc.Line))
+	buf.WriteByte(':')
+	buf.WriteString(strconv.Itoa(loc.Column))
+
+	// locations are relative to the file the source was taken from, undo the offset to find the
+	// line within the input.
+	lines := splitLines(src.Input)
+	lineOffset := 0
+	if src.LocationOffset.Line > 1 {
+		lineOffset = src.LocationOffset.Line - 1
+	}
+	lineIndex := loc.Line - 1 - lineOffset
+	if lineIndex < 0 || lineIndex >= len(lines) {
+		return
+	}
+
+	column := loc.Column
+	if lineIndex == 0 && src.LocationOffset.Column > 1 {
+		column -= src.LocationOffset.Column - 1
+	}
+	if column < 1 {
+		column = 1
+	}
+
+	type excerptLine struct {
+		prefix string
+		text   string
+	}
+	// long lines, typically minified documents, are cut down to a window around the column.
+	start := 0
+	if utf8.RuneCountInString(lines[lineIndex]) > excerptMaxWidth && column > excerptWindow/2 {
+		start = column - 1 - excerptWindow/2
+	}
+	line, caret := window(lines[lineIndex], start, column)
+
+	var excerpt []excerptLine
+	if lineIndex > 0 {
+		prev, _ := window(lines[lineIndex-1], start, 0)
+		excerpt = append(excerpt, excerptLine{strconv.Itoa(loc.Line-1) + " |", prev})
+	}
+	excerpt = append(excerpt,
+		excerptLine{strconv.Itoa(loc.Line) + " |", line},
+		excerptLine{"|", strings.Repeat(" ", caret-1) + "^"},
+	)
+	if lineIndex+1 < len(lines) {
+		next, _ := window(lines[lineIndex+1], start, 0)
+		excerpt = append(excerpt, excerptLine{strconv.Itoa(loc.Line+1) + " |", next})
+	}
+
+	padLen := 0
+	for _, line := range excerpt {
+		if len(line.prefix) > padLen {
+			padLen = len(line.prefix)
+		}
+	}
+	for _, line := range excerpt {
+		buf.WriteByte('\n')
+		buf.WriteString(strings.Repeat(" ", padLen-len(line.prefix)))
+		buf.WriteString(line.prefix)
+		if line.text != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(line.text)
+		}
+	}
+}
+
+const (
+	// excerptMaxWidth is the longest line printed in full
+	excerptMaxWidth = 120
+	// excerptWindow is the number of characters printed from lines that are too long
+	excerptWindow = 80
+)
+
+// window cuts line down to excerptWindow runes starting at the rune offset start, marking
+// anything left out with an ellipsis. Lines no longer than excerptMaxWidth are returned as is.
+// It also returns where column ends up in the result.
+func window(line string, start int, column int) (string, int) {
+	runes := []rune(line)
+	if len(runes) <= excerptMaxWidth {
+		return line, column
+	}
+	if start > len(runes) {
+		start = len(runes)
+	}
+	end := start + excerptWindow
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	var buf strings.Builder
+	if start > 0 {
+		buf.WriteString("...")
+		column += 3
+	}
+	buf.WriteString(string(runes[start:end]))
+	if end < len(runes) {
+		buf.WriteString("...")
+	}
+	return buf.String(), column - start
+}
+
+// splitLines splits input on any of the line terminators recognised by the lexer.
+func splitLines(input string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case '\n':
+			lines = append(lines, input[start:i])
+			start = i + 1
+		case '\r':
+			lines = append(lines, input[start:i])
+			if i+1 < len(input) && input[i+1] == '\n' {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	return append(lines, input[start:])
+}
@@ -0,0 +1,116 @@
+package gqlerror
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestPrint(t *testing.T) {
+	src := &ast.Source{Name: "query.graphql", Input: "query {\n  user {\n    nme\n  }\n}\n"}
+
+	t.Run("excerpt with context lines", func(t *testing.T) {
+		err := ErrorLocf("query.graphql", 3, 5, `Cannot query field "nme" on type "User".`)
+
+		require.Equal(t, `Cannot query field "nme" on type "User".
+
+query.graphql:3:5
+2 |   user {
+3 |     nme
+  |     ^
+4 |   }`, Print(err, &ast.Source{Name: "other.graphql"}, src))
+	})
+
+	t.Run("first line", func(t *testing.T) {
+		err := ErrorLocf("", 1, 7, `Expected Name, found {`)
+
+		require.Equal(t, `Expected Name, found {
+
+input:1:7
+1 | query {
+  |       ^
+2 |   user {`, Print(err, &ast.Source{Input: src.Input}))
+	})
+
+	t.Run("pads line numbers", func(t *testing.T) {
+		input := "\n\n\n\n\n\n\n\n{\n  a\n}"
+		err := ErrorLocf("", 10, 3, `boom`)
+
+		require.Equal(t, `boom
+
+input:10:3
+ 9 | {
+10 |   a
+   |   ^
+11 | }`, Print(err, &ast.Source{Input: input}))
+	})
+
+	t.Run("location offset", func(t *testing.T) {
+		embedded := &ast.Source{
+			Name:           "main.go",
+			Input:          "{\n  nme\n}",
+			LocationOffset: ast.LocationOffset{Line: 20, Column: 12},
+		}
+		err := ErrorLocf("main.go", 21, 3, `boom`)
+
+		require.Equal(t, `boom
+
+main.go:21:3
+20 | {
+21 |   nme
+   |   ^
+22 | }`, Print(err, embedded))
+	})
+
+	t.Run("multiple locations", func(t *testing.T) {
+		err := ErrorLocf("query.graphql", 1, 1, `boom`)
+		err.Locations = append(err.Locations, Location{Line: 5, Column: 1})
+
+		require.Equal(t, `boom
+
+query.graphql:1:1
+1 | query {
+  | ^
+2 |   user {
+
+query.graphql:5:1
+4 |   }
+5 | }
+  | ^
+6 |`, Print(err, src))
+	})
+
+	t.Run("minified documents", func(t *testing.T) {
+		fields := strings.Repeat("a ", 5000)
+		input := "{ " + fields + "nme " + fields + "}"
+		err := ErrorLocf("", 1, 10003, `boom`)
+
+		require.Equal(t, `boom
+
+input:1:10003
+1 | ...a a a a a a a a a a a a a a a a a a a a nme a a a a a a a a a a a a a a a a a a ...
+  |                                            ^`, Print(err, &ast.Source{Input: input}))
+	})
+
+	t.Run("long lines near the start", func(t *testing.T) {
+		input := "{\n  nme " + strings.Repeat("a ", 100) + "\n}"
+		err := ErrorLocf("", 2, 3, `boom`)
+
+		require.Equal(t, `boom
+
+input:2:3
+1 | {
+2 |   nme a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a a ...
+  |   ^
+3 | }`, Print(err, &ast.Source{Input: input}))
+	})
+
+	t.Run("without a matching source", func(t *testing.T) {
+		err := ErrorLocf("query.graphql", 3, 5, `boom`)
+
+		require.Equal(t, `query.graphql:3:5: boom`, Print(err))
+		require.Equal(t, `query.graphql:3:5: boom`, Print(err, &ast.Source{Name: "other.graphql"}))
+	})
+}
@@ -12,12 +12,8 @@ import (
 
 func LoadSchema(str ...*ast.Source) (*ast.Schema, error) {
 	schema, err := validator.LoadSchema(append([]*ast.Source{validator.Prelude}, str...)...)
-	gqlErr, ok := err.(*gqlerror.Error)
-	if ok {
-		return schema, gqlErr
-	}
 	if err != nil {
-		return schema, gqlerror.Wrap(err)
+		return schema, gqlerror.WrapIfUnwrapped(err)
 	}
 	return schema, nil
 }
@@ -33,11 +29,7 @@ func MustLoadSchema(str ...*ast.Source) *ast.Schema {
 func LoadQuery(schema *ast.Schema, str string) (*ast.QueryDocument, gqlerror.List) {
 	query, err := parser.ParseQuery(&ast.Source{Input: str})
 	if err != nil {
-		gqlErr, ok := err.(*gqlerror.Error)
-		if ok {
-			return nil, gqlerror.List{gqlErr}
-		}
-		return nil, gqlerror.List{gqlerror.Wrap(err)}
+		return nil, gqlerror.List{gqlerror.WrapIfUnwrapped(err)}
 	}
 	errs := validator.Validate(schema, query)
 	if len(errs) > 0 {
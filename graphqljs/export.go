@@ -0,0 +1,192 @@
+// Package graphqljs converts query documents to and from the JSON form of the graphql-js AST, as
+// produced by JSON.stringify on the result of parse in graphql-js, or by graphql-tag and other
+// build tools that precompile queries.
+//
+// Locations are kept as the start and end offsets of the token each node starts with. graphql-js
+// counts offsets in UTF-16 code units where this package counts runes, so they only agree for
+// documents without characters outside the basic multilingual plane.
+package graphqljs
+
+import (
+	"encoding/json"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type object = map[string]interface{}
+
+// MarshalQueryDocument encodes doc as a graphql-js Document node.
+func MarshalQueryDocument(doc *ast.QueryDocument) ([]byte, error) {
+	definitions := []interface{}{}
+	for _, op := range doc.Operations {
+		definitions = append(definitions, operation(op))
+	}
+	for _, frag := range doc.Fragments {
+		definitions = append(definitions, fragment(frag))
+	}
+	return json.Marshal(node("Document", doc.Position, object{"definitions": definitions}))
+}
+
+// node builds a node of kind, adding its location.
+func node(kind string, pos *ast.Position, fields object) object {
+	fields["kind"] = kind
+	if pos != nil {
+		fields["loc"] = object{"start": pos.Start, "end": pos.End}
+	}
+	return fields
+}
+
+func name(value string, pos *ast.Position) object {
+	return node("Name", pos, object{"value": value})
+}
+
+func operation(op *ast.OperationDefinition) object {
+	operation := op.Operation
+	if operation == "" {
+		operation = ast.Query
+	}
+	fields := object{
+		"operation":           string(operation),
+		"variableDefinitions": variableDefinitions(op.VariableDefinitions),
+		"directives":          directives(op.Directives),
+		"selectionSet":        selectionSet(op.SelectionSet, nil),
+	}
+	if op.Name != "" {
+		fields["name"] = name(op.Name, nil)
+	}
+	return node("OperationDefinition", op.Position, fields)
+}
+
+func fragment(frag *ast.FragmentDefinition) object {
+	fields := object{
+		"name":          name(frag.Name, nil),
+		"typeCondition": node("NamedType", nil, object{"name": name(frag.TypeCondition, nil)}),
+		"directives":    directives(frag.Directives),
+		"selectionSet":  selectionSet(frag.SelectionSet, nil),
+	}
+	if len(frag.VariableDefinition) > 0 {
+		fields["variableDefinitions"] = variableDefinitions(frag.VariableDefinition)
+	}
+	return node("FragmentDefinition", frag.Position, fields)
+}
+
+func variableDefinitions(defs ast.VariableDefinitionList) []interface{} {
+	list := []interface{}{}
+	for _, def := range defs {
+		fields := object{
+			"variable":   node("Variable", def.Position, object{"name": name(def.Variable, nil)}),
+			"type":       typ(def.Type),
+			"directives": directives(def.Directives),
+		}
+		if def.DefaultValue != nil {
+			fields["defaultValue"] = value(def.DefaultValue)
+		}
+		list = append(list, node("VariableDefinition", def.Position, fields))
+	}
+	return list
+}
+
+func typ(t *ast.Type) object {
+	var n object
+	if t.Elem != nil {
+		n = node("ListType", t.Position, object{"type": typ(t.Elem)})
+	} else {
+		n = node("NamedType", t.Position, object{"name": name(t.NamedType, t.Position)})
+	}
+	if t.NonNull {
+		n = node("NonNullType", t.Position, object{"type": n})
+	}
+	return n
+}
+
+func selectionSet(set ast.SelectionSet, pos *ast.Position) object {
+	selections := []interface{}{}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			fields := object{
+				"name":       name(sel.Name, nil),
+				"arguments":  arguments(sel.Arguments),
+				"directives": directives(sel.Directives),
+			}
+			if sel.Alias != "" && sel.Alias != sel.Name {
+				fields["alias"] = name(sel.Alias, sel.Position)
+			}
+			if len(sel.SelectionSet) > 0 {
+				fields["selectionSet"] = selectionSet(sel.SelectionSet, nil)
+			}
+			selections = append(selections, node("Field", sel.Position, fields))
+		case *ast.FragmentSpread:
+			selections = append(selections, node("FragmentSpread", sel.Position, object{
+				"name":       name(sel.Name, sel.Position),
+				"directives": directives(sel.Directives),
+			}))
+		case *ast.InlineFragment:
+			fields := object{
+				"directives":   directives(sel.Directives),
+				"selectionSet": selectionSet(sel.SelectionSet, nil),
+			}
+			if sel.TypeCondition != "" {
+				fields["typeCondition"] = node("NamedType", nil, object{"name": name(sel.TypeCondition, nil)})
+			}
+			selections = append(selections, node("InlineFragment", sel.Position, fields))
+		}
+	}
+	return node("SelectionSet", pos, object{"selections": selections})
+}
+
+func arguments(args ast.ArgumentList) []interface{} {
+	list := []interface{}{}
+	for _, arg := range args {
+		list = append(list, node("Argument", arg.Position, object{
+			"name":  name(arg.Name, arg.Position),
+			"value": value(arg.Value),
+		}))
+	}
+	return list
+}
+
+func directives(dirs ast.DirectiveList) []interface{} {
+	list := []interface{}{}
+	for _, dir := range dirs {
+		list = append(list, node("Directive", dir.Position, object{
+			"name":      name(dir.Name, dir.Position),
+			"arguments": arguments(dir.Arguments),
+		}))
+	}
+	return list
+}
+
+func value(v *ast.Value) object {
+	switch v.Kind {
+	case ast.Variable:
+		return node("Variable", v.Position, object{"name": name(v.Raw, nil)})
+	case ast.IntValue:
+		return node("IntValue", v.Position, object{"value": v.Raw})
+	case ast.FloatValue:
+		return node("FloatValue", v.Position, object{"value": v.Raw})
+	case ast.StringValue, ast.BlockValue:
+		return node("StringValue", v.Position, object{"value": v.Raw, "block": v.Kind == ast.BlockValue})
+	case ast.BooleanValue:
+		return node("BooleanValue", v.Position, object{"value": v.Raw == "true"})
+	case ast.NullValue:
+		return node("NullValue", v.Position, object{})
+	case ast.EnumValue:
+		return node("EnumValue", v.Position, object{"value": v.Raw})
+	case ast.ListValue:
+		values := []interface{}{}
+		for _, child := range v.Children {
+			values = append(values, value(child.Value))
+		}
+		return node("ListValue", v.Position, object{"values": values})
+	default:
+		fields := []interface{}{}
+		for _, child := range v.Children {
+			fields = append(fields, node("ObjectField", child.Position, object{
+				"name":  name(child.Name, child.Position),
+				"value": value(child.Value),
+			}))
+		}
+		return node("ObjectValue", v.Position, object{"fields": fields})
+	}
+}
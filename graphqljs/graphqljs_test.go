@@ -0,0 +1,148 @@
+package graphqljs_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/graphqljs"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+func TestRoundTrip(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query Q($id: ID! = "1", $tags: [[String!]]!) @live {
+			user(id: $id) {
+				id
+				name: fullName(format: {case: UPPER, parts: [FIRST, LAST]}, limit: 1.5, on: true)
+				... on Admin @include(if: false) { level }
+				... { bio(text: """block""") }
+				...F
+			}
+		}
+		fragment F on User { friends(first: 10, after: null) { id } }
+	`})
+	require.NoError(t, err)
+
+	b, err := graphqljs.MarshalQueryDocument(doc)
+	require.NoError(t, err)
+
+	decoded, err := graphqljs.UnmarshalQueryDocument(b)
+	require.NoError(t, err)
+	require.Equal(t, format(doc), format(decoded))
+
+	field := decoded.Operations[0].SelectionSet[0].(*ast.Field)
+	require.Equal(t, doc.Operations[0].SelectionSet[0].GetPosition().Start, field.Position.Start)
+	require.Equal(t, ast.BlockValue, decoded.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet[3].(*ast.InlineFragment).SelectionSet[0].(*ast.Field).Arguments[0].Value.Kind)
+}
+
+func TestMarshalQueryDocument(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `{ a: b(x: [1]) }`})
+	require.NoError(t, err)
+
+	b, err := graphqljs.MarshalQueryDocument(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"kind": "Document",
+		"loc": {"start": 0, "end": 1},
+		"definitions": [{
+			"kind": "OperationDefinition",
+			"loc": {"start": 0, "end": 1},
+			"operation": "query",
+			"variableDefinitions": [],
+			"directives": [],
+			"selectionSet": {
+				"kind": "SelectionSet",
+				"selections": [{
+					"kind": "Field",
+					"loc": {"start": 2, "end": 3},
+					"alias": {"kind": "Name", "loc": {"start": 2, "end": 3}, "value": "a"},
+					"name": {"kind": "Name", "value": "b"},
+					"arguments": [{
+						"kind": "Argument",
+						"loc": {"start": 7, "end": 8},
+						"name": {"kind": "Name", "loc": {"start": 7, "end": 8}, "value": "x"},
+						"value": {
+							"kind": "ListValue",
+							"loc": {"start": 10, "end": 11},
+							"values": [{"kind": "IntValue", "loc": {"start": 11, "end": 12}, "value": "1"}]
+						}
+					}],
+					"directives": []
+				}]
+			}
+		}]
+	}`, string(b))
+}
+
+func TestUnmarshalQueryDocument(t *testing.T) {
+	t.Run("graphql-tag output", func(t *testing.T) {
+		doc, err := graphqljs.UnmarshalQueryDocument([]byte(`{
+			"kind": "Document",
+			"definitions": [{
+				"kind": "OperationDefinition",
+				"operation": "query",
+				"name": {"kind": "Name", "value": "Q"},
+				"variableDefinitions": [{
+					"kind": "VariableDefinition",
+					"variable": {"kind": "Variable", "name": {"kind": "Name", "value": "n"}},
+					"type": {"kind": "NonNullType", "type": {"kind": "NamedType", "name": {"kind": "Name", "value": "Int"}}},
+					"directives": []
+				}],
+				"directives": [],
+				"selectionSet": {"kind": "SelectionSet", "selections": [{
+					"kind": "Field",
+					"name": {"kind": "Name", "value": "users"},
+					"arguments": [{
+						"kind": "Argument",
+						"name": {"kind": "Name", "value": "first"},
+						"value": {"kind": "Variable", "name": {"kind": "Name", "value": "n"}}
+					}],
+					"directives": [],
+					"selectionSet": {"kind": "SelectionSet", "selections": [
+						{"kind": "Field", "name": {"kind": "Name", "value": "id"}, "arguments": [], "directives": [], "loc": {"start": 45, "end": 47}}
+					]}
+				}]}
+			}],
+			"loc": {"start": 0, "end": 52, "source": {"body": "query Q($n: Int!) {\n  users(first: $n) {\n    id\n  }\n}", "name": "GraphQL request"}}
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, "query Q ($n: Int!) {\n\tusers(first: $n) {\n\t\tid\n\t}\n}\n", format(doc))
+
+		id := doc.Operations[0].SelectionSet[0].(*ast.Field).SelectionSet[0].(*ast.Field)
+		require.Equal(t, 3, id.Position.Line)
+		require.Equal(t, 5, id.Position.Column)
+		require.Equal(t, "GraphQL request", id.Position.Src.Name)
+		require.Same(t, doc.Position.Src, id.Position.Src)
+	})
+
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"not a document":  {`{"kind": "Field"}`, "graphqljs: expected Document, found Field"},
+		"null definition": {`{"kind": "Document", "definitions": [null]}`, "graphqljs: expected a definition, found null"},
+		"schema":          {`{"kind": "Document", "definitions": [{"kind": "ObjectTypeDefinition"}]}`, "graphqljs: unexpected ObjectTypeDefinition in an executable document"},
+		"bad operation":   {`{"kind": "Document", "definitions": [{"kind": "OperationDefinition", "operation": "delete"}]}`, `graphqljs: unknown operation "delete"`},
+		"missing name":    {`{"kind": "Document", "definitions": [{"kind": "FragmentDefinition"}]}`, "graphqljs: expected Name, found null"},
+		"missing kind":    {`{"kind": "Document", "definitions": [{"kind": "OperationDefinition", "operation": "query", "selectionSet": {"kind": "SelectionSet", "selections": [{}]}}]}`, "graphqljs: expected a selection, found a node without a kind"},
+		"bad value":       {`{"kind": "Document", "definitions": [{"kind": "OperationDefinition", "operation": "query", "selectionSet": {"kind": "SelectionSet", "selections": [{"kind": "Field", "name": {"kind": "Name", "value": "a"}, "arguments": [{"kind": "Argument", "name": {"kind": "Name", "value": "x"}, "value": {"kind": "IntValue", "value": 1}}]}]}}]}`, "graphqljs: IntValue: json: cannot unmarshal number into Go value of type string"},
+		"not json":        {`{`, "unexpected end of JSON input"},
+		"double non null": {`{"kind": "Document", "definitions": [{"kind": "OperationDefinition", "operation": "query", "variableDefinitions": [{"kind": "VariableDefinition", "variable": {"kind": "Variable", "name": {"kind": "Name", "value": "a"}}, "type": {"kind": "NonNullType", "type": {"kind": "NonNullType"}}}]}]}`, "graphqljs: non null type of a non null type"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := graphqljs.UnmarshalQueryDocument([]byte(tt.input))
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
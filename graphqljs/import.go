@@ -0,0 +1,473 @@
+package graphqljs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// jsNode holds any graphql-js node, only the fields used by its kind are set.
+type jsNode struct {
+	Kind                string          `json:"kind"`
+	Loc                 *jsLocation     `json:"loc"`
+	Name                *jsNode         `json:"name"`
+	Alias               *jsNode         `json:"alias"`
+	Value               json.RawMessage `json:"value"`
+	Block               bool            `json:"block"`
+	Operation           string          `json:"operation"`
+	Definitions         []*jsNode       `json:"definitions"`
+	VariableDefinitions []*jsNode       `json:"variableDefinitions"`
+	Variable            *jsNode         `json:"variable"`
+	Type                *jsNode         `json:"type"`
+	DefaultValue        *jsNode         `json:"defaultValue"`
+	Directives          []*jsNode       `json:"directives"`
+	Arguments           []*jsNode       `json:"arguments"`
+	TypeCondition       *jsNode         `json:"typeCondition"`
+	SelectionSet        *jsNode         `json:"selectionSet"`
+	Selections          []*jsNode       `json:"selections"`
+	Values              []*jsNode       `json:"values"`
+	Fields              []*jsNode       `json:"fields"`
+}
+
+type jsLocation struct {
+	Start  int       `json:"start"`
+	End    int       `json:"end"`
+	Source *jsSource `json:"source"`
+}
+
+type jsSource struct {
+	Body string `json:"body"`
+	Name string `json:"name"`
+}
+
+// UnmarshalQueryDocument decodes a graphql-js Document node holding executable definitions.
+//
+// When locations include their source, as the location of the document does in the output of
+// graphql-tag, positions get their line and column and point at an ast.Source holding the source
+// body. Otherwise only the offsets are known.
+func UnmarshalQueryDocument(b []byte) (*ast.QueryDocument, error) {
+	var root jsNode
+	if err := json.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+
+	i := importer{sources: map[jsSource]*ast.Source{}}
+	doc, err := i.document(&root)
+	if err != nil {
+		return nil, fmt.Errorf("graphqljs: %w", err)
+	}
+	return doc, nil
+}
+
+type importer struct {
+	sources map[jsSource]*ast.Source
+	// source is the source of the document, graphql-tag only includes it in the location of the
+	// document node.
+	source *ast.Source
+}
+
+func (i *importer) document(n *jsNode) (*ast.QueryDocument, error) {
+	if err := expect(n, "Document"); err != nil {
+		return nil, err
+	}
+	doc := &ast.QueryDocument{Position: i.position(n)}
+	if doc.Position != nil {
+		i.source = doc.Position.Src
+	}
+	for _, def := range n.Definitions {
+		if def == nil {
+			return nil, fmt.Errorf("expected a definition, found null")
+		}
+		switch def.Kind {
+		case "OperationDefinition":
+			op, err := i.operation(def)
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case "FragmentDefinition":
+			frag, err := i.fragment(def)
+			if err != nil {
+				return nil, err
+			}
+			doc.Fragments = append(doc.Fragments, frag)
+		default:
+			return nil, fmt.Errorf("unexpected %s in an executable document", kindOf(def))
+		}
+	}
+	return doc, nil
+}
+
+func (i *importer) operation(n *jsNode) (*ast.OperationDefinition, error) {
+	op := &ast.OperationDefinition{
+		Operation: ast.Operation(n.Operation),
+		Position:  i.position(n),
+	}
+	switch op.Operation {
+	case ast.Query, ast.Mutation, ast.Subscription:
+	default:
+		return nil, fmt.Errorf("unknown operation %q", n.Operation)
+	}
+
+	var err error
+	if n.Name != nil {
+		if op.Name, err = nameOf(n.Name); err != nil {
+			return nil, err
+		}
+	}
+	if op.VariableDefinitions, err = i.variableDefinitions(n.VariableDefinitions); err != nil {
+		return nil, err
+	}
+	if op.Directives, err = i.directives(n.Directives); err != nil {
+		return nil, err
+	}
+	if op.SelectionSet, err = i.selectionSet(n.SelectionSet); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (i *importer) fragment(n *jsNode) (*ast.FragmentDefinition, error) {
+	frag := &ast.FragmentDefinition{Position: i.position(n)}
+	var err error
+	if frag.Name, err = nameOf(n.Name); err != nil {
+		return nil, err
+	}
+	if frag.TypeCondition, err = namedType(n.TypeCondition); err != nil {
+		return nil, err
+	}
+	if frag.VariableDefinition, err = i.variableDefinitions(n.VariableDefinitions); err != nil {
+		return nil, err
+	}
+	if frag.Directives, err = i.directives(n.Directives); err != nil {
+		return nil, err
+	}
+	if frag.SelectionSet, err = i.selectionSet(n.SelectionSet); err != nil {
+		return nil, err
+	}
+	return frag, nil
+}
+
+func (i *importer) variableDefinitions(nodes []*jsNode) (ast.VariableDefinitionList, error) {
+	var list ast.VariableDefinitionList
+	for _, n := range nodes {
+		if err := expect(n, "VariableDefinition"); err != nil {
+			return nil, err
+		}
+		def := &ast.VariableDefinition{Position: i.position(n)}
+		if err := expect(n.Variable, "Variable"); err != nil {
+			return nil, err
+		}
+		var err error
+		if def.Variable, err = nameOf(n.Variable.Name); err != nil {
+			return nil, err
+		}
+		if def.Type, err = i.typ(n.Type); err != nil {
+			return nil, err
+		}
+		if n.DefaultValue != nil {
+			if def.DefaultValue, err = i.value(n.DefaultValue); err != nil {
+				return nil, err
+			}
+		}
+		if def.Directives, err = i.directives(n.Directives); err != nil {
+			return nil, err
+		}
+		list = append(list, def)
+	}
+	return list, nil
+}
+
+func (i *importer) typ(n *jsNode) (*ast.Type, error) {
+	if n == nil {
+		return nil, fmt.Errorf("missing type")
+	}
+	switch n.Kind {
+	case "NamedType":
+		name, err := namedType(n)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Type{NamedType: name, Position: i.position(n)}, nil
+	case "ListType":
+		elem, err := i.typ(n.Type)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Type{Elem: elem, Position: i.position(n)}, nil
+	case "NonNullType":
+		if n.Type != nil && n.Type.Kind == "NonNullType" {
+			return nil, fmt.Errorf("non null type of a non null type")
+		}
+		t, err := i.typ(n.Type)
+		if err != nil {
+			return nil, err
+		}
+		t.NonNull = true
+		return t, nil
+	default:
+		return nil, fmt.Errorf("expected a type, found %s", kindOf(n))
+	}
+}
+
+func (i *importer) selectionSet(n *jsNode) (ast.SelectionSet, error) {
+	if err := expect(n, "SelectionSet"); err != nil {
+		return nil, err
+	}
+	var set ast.SelectionSet
+	for _, sel := range n.Selections {
+		if sel == nil {
+			return nil, fmt.Errorf("expected a selection, found null")
+		}
+		switch sel.Kind {
+		case "Field":
+			field := &ast.Field{Position: i.position(sel)}
+			var err error
+			if field.Name, err = nameOf(sel.Name); err != nil {
+				return nil, err
+			}
+			field.Alias = field.Name
+			if sel.Alias != nil {
+				if field.Alias, err = nameOf(sel.Alias); err != nil {
+					return nil, err
+				}
+			}
+			if field.Arguments, err = i.arguments(sel.Arguments); err != nil {
+				return nil, err
+			}
+			if field.Directives, err = i.directives(sel.Directives); err != nil {
+				return nil, err
+			}
+			if sel.SelectionSet != nil {
+				if field.SelectionSet, err = i.selectionSet(sel.SelectionSet); err != nil {
+					return nil, err
+				}
+			}
+			set = append(set, field)
+		case "FragmentSpread":
+			spread := &ast.FragmentSpread{Position: i.position(sel)}
+			var err error
+			if spread.Name, err = nameOf(sel.Name); err != nil {
+				return nil, err
+			}
+			if spread.Directives, err = i.directives(sel.Directives); err != nil {
+				return nil, err
+			}
+			set = append(set, spread)
+		case "InlineFragment":
+			inline := &ast.InlineFragment{Position: i.position(sel)}
+			var err error
+			if sel.TypeCondition != nil {
+				if inline.TypeCondition, err = namedType(sel.TypeCondition); err != nil {
+					return nil, err
+				}
+			}
+			if inline.Directives, err = i.directives(sel.Directives); err != nil {
+				return nil, err
+			}
+			if inline.SelectionSet, err = i.selectionSet(sel.SelectionSet); err != nil {
+				return nil, err
+			}
+			set = append(set, inline)
+		default:
+			return nil, fmt.Errorf("expected a selection, found %s", kindOf(sel))
+		}
+	}
+	return set, nil
+}
+
+func (i *importer) arguments(nodes []*jsNode) (ast.ArgumentList, error) {
+	var list ast.ArgumentList
+	for _, n := range nodes {
+		if err := expect(n, "Argument"); err != nil {
+			return nil, err
+		}
+		arg := &ast.Argument{Position: i.position(n)}
+		var err error
+		if arg.Name, err = nameOf(n.Name); err != nil {
+			return nil, err
+		}
+		var value jsNode
+		if err := json.Unmarshal(n.Value, &value); err != nil {
+			return nil, fmt.Errorf("argument %s: %w", arg.Name, err)
+		}
+		if arg.Value, err = i.value(&value); err != nil {
+			return nil, err
+		}
+		list = append(list, arg)
+	}
+	return list, nil
+}
+
+func (i *importer) directives(nodes []*jsNode) (ast.DirectiveList, error) {
+	var list ast.DirectiveList
+	for _, n := range nodes {
+		if err := expect(n, "Directive"); err != nil {
+			return nil, err
+		}
+		dir := &ast.Directive{Position: i.position(n)}
+		var err error
+		if dir.Name, err = nameOf(n.Name); err != nil {
+			return nil, err
+		}
+		if dir.Arguments, err = i.arguments(n.Arguments); err != nil {
+			return nil, err
+		}
+		list = append(list, dir)
+	}
+	return list, nil
+}
+
+func (i *importer) value(n *jsNode) (*ast.Value, error) {
+	if n == nil {
+		return nil, fmt.Errorf("expected a value, found null")
+	}
+	v := &ast.Value{Position: i.position(n)}
+	var err error
+	switch n.Kind {
+	case "Variable":
+		v.Kind = ast.Variable
+		v.Raw, err = nameOf(n.Name)
+	case "IntValue":
+		v.Kind = ast.IntValue
+		err = json.Unmarshal(n.Value, &v.Raw)
+	case "FloatValue":
+		v.Kind = ast.FloatValue
+		err = json.Unmarshal(n.Value, &v.Raw)
+	case "StringValue":
+		v.Kind = ast.StringValue
+		if n.Block {
+			v.Kind = ast.BlockValue
+		}
+		err = json.Unmarshal(n.Value, &v.Raw)
+	case "BooleanValue":
+		var b bool
+		err = json.Unmarshal(n.Value, &b)
+		v.Kind, v.Raw = ast.BooleanValue, "false"
+		if b {
+			v.Raw = "true"
+		}
+	case "NullValue":
+		v.Kind, v.Raw = ast.NullValue, "null"
+	case "EnumValue":
+		v.Kind = ast.EnumValue
+		err = json.Unmarshal(n.Value, &v.Raw)
+	case "ListValue":
+		v.Kind = ast.ListValue
+		for _, item := range n.Values {
+			child, err := i.value(item)
+			if err != nil {
+				return nil, err
+			}
+			v.Children = append(v.Children, &ast.ChildValue{Value: child})
+		}
+	case "ObjectValue":
+		v.Kind = ast.ObjectValue
+		for _, field := range n.Fields {
+			if err := expect(field, "ObjectField"); err != nil {
+				return nil, err
+			}
+			child := &ast.ChildValue{Position: i.position(field)}
+			if child.Name, err = nameOf(field.Name); err != nil {
+				return nil, err
+			}
+			var value jsNode
+			if err := json.Unmarshal(field.Value, &value); err != nil {
+				return nil, fmt.Errorf("object field %s: %w", child.Name, err)
+			}
+			if child.Value, err = i.value(&value); err != nil {
+				return nil, err
+			}
+			v.Children = append(v.Children, child)
+		}
+	default:
+		return nil, fmt.Errorf("expected a value, found %s", kindOf(n))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.Kind, err)
+	}
+	return v, nil
+}
+
+// position converts the location of n. When the location, or failing that the location of the
+// document, includes the source the line and column are worked out too.
+func (i *importer) position(n *jsNode) *ast.Position {
+	if n.Loc == nil {
+		return nil
+	}
+	pos := &ast.Position{Start: n.Loc.Start, End: n.Loc.End}
+	src := i.source
+	if n.Loc.Source != nil {
+		src = i.sources[*n.Loc.Source]
+		if src == nil {
+			src = &ast.Source{Name: n.Loc.Source.Name, Input: n.Loc.Source.Body}
+			i.sources[*n.Loc.Source] = src
+		}
+	}
+	if src != nil {
+		pos.Src = src
+		pos.Line, pos.Column = lineColumn(src.Input, pos.Start)
+	}
+	return pos
+}
+
+// lineColumn returns the 1 based line and column of the rune at offset in input.
+func lineColumn(input string, offset int) (int, int) {
+	line, column := 1, 1
+	prev := rune(0)
+	n := 0
+	for _, r := range input {
+		if n == offset {
+			break
+		}
+		n++
+		switch {
+		case r == '\n' && prev == '\r':
+		case r == '\n' || r == '\r':
+			line, column = line+1, 1
+		default:
+			column++
+		}
+		prev = r
+	}
+	return line, column
+}
+
+func expect(n *jsNode, kind string) error {
+	if n == nil || n.Kind != kind {
+		return fmt.Errorf("expected %s, found %s", kind, kindOf(n))
+	}
+	return nil
+}
+
+func kindOf(n *jsNode) string {
+	if n == nil {
+		return "null"
+	}
+	if n.Kind == "" {
+		return "a node without a kind"
+	}
+	return n.Kind
+}
+
+func nameOf(n *jsNode) (string, error) {
+	if err := expect(n, "Name"); err != nil {
+		return "", err
+	}
+	var value string
+	if err := json.Unmarshal(n.Value, &value); err != nil {
+		return "", fmt.Errorf("Name: %w", err)
+	}
+	if value == "" {
+		return "", fmt.Errorf("empty Name")
+	}
+	return value, nil
+}
+
+func namedType(n *jsNode) (string, error) {
+	if err := expect(n, "NamedType"); err != nil {
+		return "", err
+	}
+	return nameOf(n.Name)
+}
@@ -0,0 +1,143 @@
+package gqlparser
+
+import (
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// ParseQueryFS parses the named query file in fsys along with every file it imports, and returns
+// their definitions combined in a single document.
+//
+// Imports use the `#import "./fragments.graphql"` comment convention of graphql-tag and other
+// JavaScript tooling. Paths are relative to the importing file. Each file is read once however
+// often it is imported, and a fragment defined the same way in several files is only kept once.
+// Import cycles are reported as errors.
+//
+// Every definition keeps the position in the file it came from, so validation errors on the
+// result point at the right file. The document is not validated.
+func ParseQueryFS(fsys fs.FS, name string) (*ast.QueryDocument, error) {
+	l := importLoader{
+		fsys:      fsys,
+		loaded:    map[string]bool{},
+		fragments: map[string]*ast.FragmentDefinition{},
+		doc:       &ast.QueryDocument{},
+	}
+	if err := l.load(name, nil); err != nil {
+		return nil, err
+	}
+	return l.doc, nil
+}
+
+type importLoader struct {
+	fsys   fs.FS
+	loaded map[string]bool
+	// stack holds the files being loaded, from the first file down to the current one
+	stack     []string
+	fragments map[string]*ast.FragmentDefinition
+	doc       *ast.QueryDocument
+}
+
+// fileImport is an #import comment, positioned at the quoted path.
+type fileImport struct {
+	path string
+	line int
+	col  int
+}
+
+// load adds the definitions of name and of the files it imports. from is the import that asked
+// for name, if any.
+func (l *importLoader) load(name string, from *gqlerror.Error) error {
+	for i, loading := range l.stack {
+		if loading == name {
+			from.Message = "import cycle: " + strings.Join(append(l.stack[i:], name), " -> ")
+			return from
+		}
+	}
+	if l.loaded[name] {
+		return nil
+	}
+	l.loaded[name] = true
+
+	b, err := fs.ReadFile(l.fsys, name)
+	if err != nil {
+		if from != nil {
+			from.Message = err.Error()
+			return from
+		}
+		gqlErr := gqlerror.Wrap(err)
+		gqlErr.SetFile(name)
+		return gqlErr
+	}
+
+	src := &ast.Source{Name: name, Input: string(b)}
+	doc, err := parser.ParseQuery(src)
+	if err != nil {
+		return gqlerror.WrapIfUnwrapped(err)
+	}
+
+	l.doc.Operations = append(l.doc.Operations, doc.Operations...)
+	for _, fragment := range doc.Fragments {
+		if existing := l.fragments[fragment.Name]; existing != nil && sameFragment(existing, fragment) {
+			continue
+		}
+		l.fragments[fragment.Name] = fragment
+		l.doc.Fragments = append(l.doc.Fragments, fragment)
+	}
+
+	l.stack = append(l.stack, name)
+	for _, imp := range parseImports(src.Input) {
+		importErr := gqlerror.ErrorLocf(name, imp.line, imp.col, "")
+		target := path.Join(path.Dir(name), imp.path)
+		if !fs.ValidPath(target) {
+			importErr.Message = "import " + strconv.Quote(imp.path) + " is outside of the file system"
+			return importErr
+		}
+		if err := l.load(target, importErr); err != nil {
+			return err
+		}
+	}
+	l.stack = l.stack[:len(l.stack)-1]
+	return nil
+}
+
+// parseImports returns the #import comments of a file. They must be on lines of their own.
+func parseImports(input string) []fileImport {
+	var imports []fileImport
+	for i, line := range strings.Split(input, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		rest := strings.TrimPrefix(trimmed, "#import")
+		if rest == trimmed || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		quoted := strings.TrimSpace(rest)
+		if len(quoted) < 2 || (quoted[0] != '"' && quoted[0] != '\'') || quoted[len(quoted)-1] != quoted[0] {
+			continue
+		}
+		imports = append(imports, fileImport{
+			path: quoted[1 : len(quoted)-1],
+			line: i + 1,
+			col:  strings.Index(line, quoted) + 1,
+		})
+	}
+	return imports
+}
+
+// sameFragment reports whether a and b print the same way, ignoring formatting and comments.
+func sameFragment(a, b *ast.FragmentDefinition) bool {
+	return printFragment(a) == printFragment(b)
+}
+
+func printFragment(fragment *ast.FragmentDefinition) string {
+	var buf strings.Builder
+	formatter.NewFormatter(&buf).FormatQueryDocument(&ast.QueryDocument{
+		Fragments: ast.FragmentDefinitionList{fragment},
+	})
+	return buf.String()
+}
@@ -0,0 +1,108 @@
+package gqlparser_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func TestParseQueryFS(t *testing.T) {
+	t.Run("combines imported files", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"queries/user.graphql": {Data: []byte(`#import "./fragments/user.graphql"
+#import '../shared/node.graphql'
+query User { user { ...UserFields ...NodeFields } }
+`)},
+			"queries/fragments/user.graphql": {Data: []byte(`  #import "../../shared/node.graphql"
+fragment UserFields on User { name }
+`)},
+			"shared/node.graphql": {Data: []byte(`fragment NodeFields on User { id }`)},
+		}
+
+		doc, err := gqlparser.ParseQueryFS(fsys, "queries/user.graphql")
+		require.NoError(t, err)
+		require.Len(t, doc.Operations, 1)
+		require.Len(t, doc.Fragments, 2)
+		require.Equal(t, "queries/fragments/user.graphql", doc.Fragments.ForName("UserFields").Position.Src.Name)
+		require.Equal(t, "shared/node.graphql", doc.Fragments.ForName("NodeFields").Position.Src.Name)
+
+		schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+			type Query { user: User }
+			type User { id: ID! name: String }
+		`})
+		require.Empty(t, validator.Validate(schema, doc))
+	})
+
+	t.Run("keeps identical fragments once", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"b.graphql\"\nquery A { ...F }\nfragment F on User { id }")},
+			"b.graphql": {Data: []byte("fragment F on User {\n  id\n}")},
+		}
+
+		doc, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.NoError(t, err)
+		require.Len(t, doc.Fragments, 1)
+	})
+
+	t.Run("keeps different fragments with the same name for validation to report", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"b.graphql\"\nquery A { ...F }\nfragment F on User { id }")},
+			"b.graphql": {Data: []byte("fragment F on User { name }")},
+		}
+
+		doc, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.NoError(t, err)
+		require.Len(t, doc.Fragments, 2)
+	})
+
+	t.Run("reports cycles", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"b.graphql\"\nquery A { ...B }")},
+			"b.graphql": {Data: []byte("#import \"c.graphql\"\nfragment B on User { ...C }")},
+			"c.graphql": {Data: []byte("\n  #import \"./a.graphql\"\nfragment C on User { id }")},
+		}
+
+		_, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.EqualError(t, err, "c.graphql:2:11: import cycle: a.graphql -> b.graphql -> c.graphql -> a.graphql")
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, err, &gqlErr)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 11}}, gqlErr.Locations)
+	})
+
+	t.Run("reports missing imports where they are imported", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"missing.graphql\"\nquery A { id }")},
+		}
+
+		_, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.EqualError(t, err, "a.graphql:1:9: open missing.graphql: file does not exist")
+
+		_, err = gqlparser.ParseQueryFS(fsys, "nope.graphql")
+		require.EqualError(t, err, "nope.graphql: open nope.graphql: file does not exist")
+	})
+
+	t.Run("reports imports outside of the file system", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"../b.graphql\"\nquery A { id }")},
+		}
+
+		_, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.EqualError(t, err, `a.graphql:1:9: import "../b.graphql" is outside of the file system`)
+	})
+
+	t.Run("reports parse errors in imported files", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("#import \"b.graphql\"\nquery A { id }")},
+			"b.graphql": {Data: []byte("fragment B on User {")},
+		}
+
+		_, err := gqlparser.ParseQueryFS(fsys, "a.graphql")
+		require.EqualError(t, err, "b.graphql:1:21: Expected Name, found <EOF>")
+	})
+}
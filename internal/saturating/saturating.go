@@ -0,0 +1,21 @@
+// Package saturating implements arithmetic on non negative counts that sticks at math.MaxInt
+// instead of overflowing, for counting over documents that spread fragments exponentially.
+package saturating
+
+import "math"
+
+// Add returns a+b, capped at math.MaxInt.
+func Add(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// Mul returns a*b, capped at math.MaxInt.
+func Mul(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
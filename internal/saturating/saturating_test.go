@@ -0,0 +1,18 @@
+package saturating
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaturating(t *testing.T) {
+	require.Equal(t, 5, Add(2, 3))
+	require.Equal(t, math.MaxInt, Add(math.MaxInt-1, 2))
+	require.Equal(t, math.MaxInt, Add(math.MaxInt, math.MaxInt))
+
+	require.Equal(t, 6, Mul(2, 3))
+	require.Equal(t, 0, Mul(0, math.MaxInt))
+	require.Equal(t, math.MaxInt, Mul(math.MaxInt/2+1, 2))
+}
@@ -0,0 +1,257 @@
+// Package jsonschema converts the input types of a schema into JSON Schema documents, so that
+// validation layers and form builders outside of GraphQL can reuse the same definitions.
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Draft is the JSON Schema version of the generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, holding the keywords used by the generator. It encodes to
+// JSON with encoding/json.
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+
+	// Type is a string, or a list of strings for types that also allow null.
+	Type        interface{}   `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Deprecated  bool          `json:"deprecated,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	AnyOf       []*Schema     `json:"anyOf,omitempty"`
+
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// Option configures the generator.
+type Option func(g *generator)
+
+// WithScalar sets the JSON Schema of the named custom scalar. Custom scalars accept any value by
+// default.
+func WithScalar(name string, schema *Schema) Option {
+	return func(g *generator) {
+		g.scalars[name] = schema
+	}
+}
+
+// Generate returns a JSON Schema document validating values of the named input object, enum or
+// scalar, as they would be given in variables.
+//
+// Input objects and enums are added to $defs and referred to by name, so recursive input types
+// are supported. Nullable types also allow null, and non null fields without a default value are
+// required. Unknown fields are not allowed.
+func Generate(schema *ast.Schema, typeName string, options ...Option) (*Schema, error) {
+	g := newGenerator(schema, options)
+	root, err := g.named(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return g.document(root), nil
+}
+
+// GenerateArguments returns a JSON Schema document validating the arguments of a field, as an
+// object with a property per argument.
+func GenerateArguments(schema *ast.Schema, typeName string, fieldName string, options ...Option) (*Schema, error) {
+	def := schema.Types[typeName]
+	if def == nil {
+		return nil, fmt.Errorf("unknown type %s", typeName)
+	}
+	field := def.Fields.ForName(fieldName)
+	if field == nil {
+		return nil, fmt.Errorf("type %s has no field %s", typeName, fieldName)
+	}
+
+	g := newGenerator(schema, options)
+	root := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: new(bool),
+	}
+	for _, arg := range field.Arguments {
+		prop, err := g.input(arg.Type, arg.DefaultValue, arg.Description, arg.Directives)
+		if err != nil {
+			return nil, err
+		}
+		root.Properties[arg.Name] = prop
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			root.Required = append(root.Required, arg.Name)
+		}
+	}
+	return g.document(root), nil
+}
+
+type generator struct {
+	schema  *ast.Schema
+	scalars map[string]*Schema
+	defs    map[string]*Schema
+}
+
+func newGenerator(schema *ast.Schema, options []Option) *generator {
+	g := &generator{
+		schema:  schema,
+		scalars: map[string]*Schema{},
+		defs:    map[string]*Schema{},
+	}
+	for _, o := range options {
+		o(g)
+	}
+	return g
+}
+
+func (g *generator) document(root *Schema) *Schema {
+	root.Schema = Draft
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// named returns the schema of a non null value of the named type.
+func (g *generator) named(name string) (*Schema, error) {
+	def := g.schema.Types[name]
+	if def == nil {
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+
+	switch def.Kind {
+	case ast.Scalar:
+		return g.scalar(def), nil
+	case ast.Enum, ast.InputObject:
+		if _, ok := g.defs[name]; !ok {
+			// the definition is registered before it is filled in, so recursive types find it.
+			s := &Schema{}
+			g.defs[name] = s
+			if err := g.define(def, s); err != nil {
+				return nil, err
+			}
+		}
+		return &Schema{Ref: "#/$defs/" + name}, nil
+	default:
+		return nil, fmt.Errorf("%s is an %s, not an input type", name, def.Kind)
+	}
+}
+
+func (g *generator) define(def *ast.Definition, s *Schema) error {
+	s.Description = def.Description
+
+	if def.Kind == ast.Enum {
+		s.Type = "string"
+		for _, value := range def.EnumValues {
+			s.Enum = append(s.Enum, value.Name)
+		}
+		return nil
+	}
+
+	s.Type = "object"
+	s.Properties = map[string]*Schema{}
+	s.AdditionalProperties = new(bool)
+	for _, field := range def.Fields {
+		prop, err := g.input(field.Type, field.DefaultValue, field.Description, field.Directives)
+		if err != nil {
+			return err
+		}
+		s.Properties[field.Name] = prop
+		if field.Type.NonNull && field.DefaultValue == nil {
+			s.Required = append(s.Required, field.Name)
+		}
+	}
+	return nil
+}
+
+// input returns the schema of an input field or argument.
+func (g *generator) input(typ *ast.Type, defaultValue *ast.Value, description string, directives ast.DirectiveList) (*Schema, error) {
+	s, err := g.typ(typ)
+	if err != nil {
+		return nil, err
+	}
+	// since draft 2019-09 keywords next to $ref apply along with the referenced schema, so
+	// annotations can be added to any schema.
+	if description != "" {
+		s.Description = description
+	}
+	s.Deprecated = directives.ForName("deprecated") != nil
+	if defaultValue != nil {
+		if s.Default, err = defaultValue.Value(nil); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (g *generator) typ(typ *ast.Type) (*Schema, error) {
+	var s *Schema
+	if typ.Elem != nil {
+		items, err := g.typ(typ.Elem)
+		if err != nil {
+			return nil, err
+		}
+		s = &Schema{Type: "array", Items: items}
+	} else {
+		var err error
+		if s, err = g.named(typ.NamedType); err != nil {
+			return nil, err
+		}
+	}
+	if typ.NonNull {
+		return s, nil
+	}
+	return nullable(s), nil
+}
+
+func (g *generator) scalar(def *ast.Definition) *Schema {
+	if s, ok := g.scalars[def.Name]; ok {
+		return copySchema(s)
+	}
+
+	switch def.Name {
+	case "Int":
+		min, max := float64(math.MinInt32), float64(math.MaxInt32)
+		return &Schema{Type: "integer", Minimum: &min, Maximum: &max}
+	case "Float":
+		return &Schema{Type: "number"}
+	case "String":
+		return &Schema{Type: "string"}
+	case "Boolean":
+		return &Schema{Type: "boolean"}
+	case "ID":
+		return &Schema{Type: []string{"integer", "string"}}
+	default:
+		return &Schema{Description: def.Description}
+	}
+}
+
+// nullable returns s changed to also allow null.
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case []string:
+		s.Type = append(append([]string(nil), typ...), "null")
+		return s
+	}
+	if s.Ref == "" && s.AnyOf == nil && s.Enum == nil {
+		// a schema without a type, such as that of a custom scalar, already allows anything
+		return s
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+func copySchema(s *Schema) *Schema {
+	c := *s
+	return &c
+}
@@ -0,0 +1,118 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/jsonschema"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		users(filter: UserFilter, first: Int! = 10, after: ID): [User!]!
+	}
+	type User { id: ID! }
+
+	"Filters users."
+	input UserFilter {
+		"Exact name match."
+		name: String
+		roles: [Role!]
+		age: Int! @deprecated(reason: "use born")
+		born: Date
+		and: [UserFilter!]
+		limit: Float = 1.5
+	}
+	enum Role { ADMIN MEMBER }
+	scalar Date
+`})
+
+func generate(t *testing.T, typeName string, options ...jsonschema.Option) string {
+	t.Helper()
+	s, err := jsonschema.Generate(schema, typeName, options...)
+	require.NoError(t, err)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestGenerate(t *testing.T) {
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/UserFilter",
+		"$defs": {
+			"UserFilter": {
+				"type": "object",
+				"description": "Filters users.",
+				"properties": {
+					"name": {"type": ["string", "null"], "description": "Exact name match."},
+					"roles": {"type": ["array", "null"], "items": {"$ref": "#/$defs/Role"}},
+					"age": {"type": "integer", "minimum": -2147483648, "maximum": 2147483647, "deprecated": true},
+					"born": {},
+					"and": {"type": ["array", "null"], "items": {"$ref": "#/$defs/UserFilter"}},
+					"limit": {"type": ["number", "null"], "default": 1.5}
+				},
+				"required": ["age"],
+				"additionalProperties": false
+			},
+			"Role": {"type": "string", "enum": ["ADMIN", "MEMBER"]}
+		}
+	}`, generate(t, "UserFilter"))
+
+	t.Run("scalars", func(t *testing.T) {
+		require.JSONEq(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": ["integer", "string"]}`, generate(t, "ID"))
+
+		date := &jsonschema.Schema{Type: "string", Description: "ISO 8601 date"}
+		require.JSONEq(t, `{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "string", "description": "ISO 8601 date"}`,
+			generate(t, "Date", jsonschema.WithScalar("Date", date)))
+		require.Empty(t, date.Schema, "options must not be modified")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := jsonschema.Generate(schema, "Nope")
+		require.EqualError(t, err, "unknown type Nope")
+		_, err = jsonschema.Generate(schema, "User")
+		require.EqualError(t, err, "User is an OBJECT, not an input type")
+	})
+}
+
+func TestGenerateArguments(t *testing.T) {
+	s, err := jsonschema.GenerateArguments(schema, "Query", "users")
+	require.NoError(t, err)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/UserFilter"}, {"type": "null"}]},
+			"first": {"type": "integer", "minimum": -2147483648, "maximum": 2147483647, "default": 10},
+			"after": {"type": ["integer", "string", "null"]}
+		},
+		"additionalProperties": false,
+		"$defs": {
+			"UserFilter": {
+				"type": "object",
+				"description": "Filters users.",
+				"properties": {
+					"name": {"type": ["string", "null"], "description": "Exact name match."},
+					"roles": {"type": ["array", "null"], "items": {"$ref": "#/$defs/Role"}},
+					"age": {"type": "integer", "minimum": -2147483648, "maximum": 2147483647, "deprecated": true},
+					"born": {},
+					"and": {"type": ["array", "null"], "items": {"$ref": "#/$defs/UserFilter"}},
+					"limit": {"type": ["number", "null"], "default": 1.5}
+				},
+				"required": ["age"],
+				"additionalProperties": false
+			},
+			"Role": {"type": "string", "enum": ["ADMIN", "MEMBER"]}
+		}
+	}`, string(b))
+
+	_, err = jsonschema.GenerateArguments(schema, "Query", "nope")
+	require.EqualError(t, err, "type Query has no field nope")
+}
@@ -1,7 +1,7 @@
 package lexer
 
 import (
-	"bytes"
+	"sort"
 	"unicode/utf8"
 
 	"github.com/vektah/gqlparser/v2/ast"
@@ -23,6 +23,9 @@ type Lexer struct {
 	line int
 	// An offset into the string in rune
 	lineStartRunes int
+	// scratch space for unescaping string values, reused between tokens so only the final
+	// string is allocated
+	buf []byte
 }
 
 func New(src *ast.Source) Lexer {
@@ -32,6 +35,58 @@ func New(src *ast.Source) Lexer {
 	}
 }
 
+// Tokens reads every token of src, comments included, up to EOF. If src can't be lexed the tokens
+// read before the error are returned with it.
+func Tokens(src *ast.Source) ([]Token, error) {
+	var tokens []Token
+	lex := New(src)
+	for {
+		tok, err := lex.ReadToken()
+		if err != nil {
+			return tokens, err
+		}
+		if tok.Kind == EOF {
+			return tokens, nil
+		}
+		tokens = append(tokens, tok)
+	}
+}
+
+// SourceTokens lexes the sources of positions on demand, for tools that need the tokens around the
+// nodes of a parsed document, such as to propose fixes. The tokens of the last source are kept,
+// so looking up several positions of a document lexes it once.
+type SourceTokens struct {
+	src    *ast.Source
+	tokens []Token
+}
+
+// At returns the tokens of the source pos is in, comments included, and the index of the token
+// starting at pos or -1. Sources that can't be lexed give the tokens read before the error.
+func (s *SourceTokens) At(pos *ast.Position) ([]Token, int) {
+	if pos == nil || pos.Src == nil {
+		return nil, -1
+	}
+	if pos.Src != s.src {
+		s.src = pos.Src
+		s.tokens, _ = Tokens(pos.Src)
+	}
+	i := sort.Search(len(s.tokens), func(i int) bool { return s.tokens[i].Pos.Start >= pos.Start })
+	if i == len(s.tokens) || s.tokens[i].Pos.Start != pos.Start {
+		return s.tokens, -1
+	}
+	return s.tokens, i
+}
+
+// Reset points the lexer at the start of a new source, keeping any scratch space allocated while
+// lexing previous ones.
+func (s *Lexer) Reset(src *ast.Source) {
+	*s = Lexer{
+		Source: src,
+		line:   1,
+		buf:    s.buf[:0],
+	}
+}
+
 // take one rune from input and advance end
 func (s *Lexer) peek() (rune, int) {
 	return utf8.DecodeRuneInString(s.Input[s.end:])
@@ -42,31 +97,62 @@ func (s *Lexer) makeToken(kind Type) (Token, error) {
 }
 
 func (s *Lexer) makeValueToken(kind Type, value string) (Token, error) {
+	line, column := s.location(s.startRunes)
 	return Token{
 		Kind:  kind,
 		Value: value,
 		Pos: ast.Position{
 			Start:  s.startRunes,
 			End:    s.endRunes,
-			Line:   s.line,
-			Column: s.startRunes - s.lineStartRunes + 1,
+			Line:   line,
+			Column: column,
 			Src:    s.Source,
 		},
 	}, nil
 }
 
 func (s *Lexer) makeError(format string, args ...interface{}) (Token, *gqlerror.Error) {
-	column := s.endRunes - s.lineStartRunes + 1
+	line, column := s.location(s.endRunes)
+	err := gqlerror.ErrorLocf(s.Source.Name, line, column, format, args...)
+	err.SetCode(gqlerror.CodeParseFailed)
 	return Token{
 		Kind: Invalid,
 		Pos: ast.Position{
 			Start:  s.startRunes,
 			End:    s.endRunes,
-			Line:   s.line,
+			Line:   line,
 			Column: column,
 			Src:    s.Source,
 		},
-	}, gqlerror.ErrorLocf(s.Source.Name, s.line, column, format, args...)
+	}, err
+}
+
+// AtLineStart reports whether only spaces and tabs precede the last token read on its line. Unlike
+// the column of the token, this doesn't depend on the LocationOffset of the source.
+func (s *Lexer) AtLineStart() bool {
+	for i := 1; i <= s.startRunes-s.lineStartRunes; i++ {
+		// anything but ASCII white space stops the loop, so runes and bytes can't get out of step
+		switch s.Input[s.start-i] {
+		case ' ', '\t':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// location returns the line and column of the rune at the given offset on the current line, as
+// seen from the file the source was taken from.
+func (s *Lexer) location(runes int) (int, int) {
+	line, column := s.line, runes-s.lineStartRunes+1
+	offset := s.Source.LocationOffset
+	if offset.Line > 1 {
+		line += offset.Line - 1
+	}
+	if s.line == 1 && offset.Column > 1 {
+		column += offset.Column - 1
+	}
+	return line, column
 }
 
 // ReadToken gets the next token from the source starting at the given position.
@@ -139,15 +225,24 @@ func (s *Lexer) ReadToken() (Token, error) {
 	s.end--
 	s.endRunes--
 
-	if r < 0x0020 && r != 0x0009 && r != 0x000a && r != 0x000d {
-		return s.makeError(`Cannot contain the invalid character "\u%04d"`, r)
+	var tok Token
+	var err *gqlerror.Error
+	switch {
+	case r < 0x0020 && r != 0x0009 && r != 0x000a && r != 0x000d:
+		tok, err = s.makeError(`Cannot contain the invalid character "\u%04d"`, r)
+	case r == '\'':
+		tok, err = s.makeError(`Unexpected single quote character ('), did you mean to use a double quote (")?`)
+	default:
+		tok, err = s.makeError(`Cannot parse the unexpected character "%s".`, string(r))
 	}
 
-	if r == '\'' {
-		return s.makeError(`Unexpected single quote character ('), did you mean to use a double quote (")?`)
-	}
+	// step over the offending character so that a caller reading on after the error doesn't get
+	// stuck on it forever
+	_, w := s.peek()
+	s.end += w
+	s.endRunes++
 
-	return s.makeError(`Cannot parse the unexpected character "%s".`, string(r))
+	return tok, err
 }
 
 // ws reads from body starting at startPosition until it finds a non-whitespace
@@ -299,8 +394,9 @@ func (s *Lexer) describeNext() string {
 func (s *Lexer) readString() (Token, error) {
 	inputLen := len(s.Input)
 
-	// this buffer is lazily created only if there are escape characters.
-	var buf *bytes.Buffer
+	// the scratch buffer is only used once we hit an escape character, until then the value is
+	// a substring of the input.
+	escaped := false
 
 	// skip the opening quote
 	s.start++
@@ -326,8 +422,8 @@ func (s *Lexer) readString() (Token, error) {
 			s.end += w
 			s.endRunes++
 
-			if buf != nil {
-				buf.WriteRune(char)
+			if escaped {
+				s.buf = utf8.AppendRune(s.buf, char)
 			}
 
 		case '"':
@@ -336,8 +432,8 @@ func (s *Lexer) readString() (Token, error) {
 			t.Pos.Start--
 			t.Pos.End++
 
-			if buf != nil {
-				t.Value = buf.String()
+			if escaped {
+				t.Value = string(s.buf)
 			}
 
 			// skip the close quote
@@ -353,8 +449,9 @@ func (s *Lexer) readString() (Token, error) {
 				return s.makeError(`Invalid character escape sequence.`)
 			}
 
-			if buf == nil {
-				buf = bytes.NewBufferString(s.Input[s.start:s.end])
+			if !escaped {
+				escaped = true
+				s.buf = append(s.buf[:0], s.Input[s.start:s.end]...)
 			}
 
 			escape := s.Input[s.end+1]
@@ -372,23 +469,23 @@ func (s *Lexer) readString() (Token, error) {
 					s.endRunes++
 					return s.makeError("Invalid character escape sequence: \\%s.", s.Input[s.end:s.end+5])
 				}
-				buf.WriteRune(r)
+				s.buf = utf8.AppendRune(s.buf, r)
 				s.end += 6
 				s.endRunes += 6
 			} else {
 				switch escape {
 				case '"', '/', '\\':
-					buf.WriteByte(escape)
+					s.buf = append(s.buf, escape)
 				case 'b':
-					buf.WriteByte('\b')
+					s.buf = append(s.buf, '\b')
 				case 'f':
-					buf.WriteByte('\f')
+					s.buf = append(s.buf, '\f')
 				case 'n':
-					buf.WriteByte('\n')
+					s.buf = append(s.buf, '\n')
 				case 'r':
-					buf.WriteByte('\r')
+					s.buf = append(s.buf, '\r')
 				case 't':
-					buf.WriteByte('\t')
+					s.buf = append(s.buf, '\t')
 				default:
 					s.end++
 					s.endRunes++
@@ -409,7 +506,7 @@ func (s *Lexer) readString() (Token, error) {
 func (s *Lexer) readBlockString() (Token, error) {
 	inputLen := len(s.Input)
 
-	var buf bytes.Buffer
+	s.buf = s.buf[:0]
 
 	// skip the opening quote
 	s.start += 3
@@ -422,7 +519,7 @@ func (s *Lexer) readBlockString() (Token, error) {
 
 		// Closing triple quote (""")
 		if r == '"' && s.end+3 <= inputLen && s.Input[s.end:s.end+3] == `"""` {
-			t, err := s.makeValueToken(BlockString, blockStringValue(buf.String()))
+			t, err := s.makeValueToken(BlockString, blockStringValue(string(s.buf)))
 
 			// the token should not include the quotes in its value, but should cover them in its position
 			t.Pos.Start -= 3
@@ -441,7 +538,7 @@ func (s *Lexer) readBlockString() (Token, error) {
 
 		switch {
 		case r == '\\' && s.end+4 <= inputLen && s.Input[s.end:s.end+4] == `\"""`:
-			buf.WriteString(`"""`)
+			s.buf = append(s.buf, `"""`...)
 			s.end += 4
 			s.endRunes += 4
 		case r == '\r':
@@ -450,7 +547,7 @@ func (s *Lexer) readBlockString() (Token, error) {
 				s.endRunes++
 			}
 
-			buf.WriteByte('\n')
+			s.buf = append(s.buf, '\n')
 			s.end++
 			s.endRunes++
 			s.line++
@@ -465,7 +562,7 @@ func (s *Lexer) readBlockString() (Token, error) {
 			}
 			s.end += w
 			s.endRunes++
-			buf.WriteRune(char)
+			s.buf = utf8.AppendRune(s.buf, char)
 			if r == '\n' {
 				s.line++
 				s.lineStartRunes = s.endRunes
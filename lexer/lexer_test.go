@@ -1,6 +1,8 @@
 package lexer
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -39,3 +41,89 @@ func TestLexer(t *testing.T) {
 		return ret
 	})
 }
+
+func TestLexerReusesScratchBuffer(t *testing.T) {
+	l := New(&ast.Source{Input: `"a\nlonger value" "b\t" """block""" "c\u00e9"`})
+
+	var values []string
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind == EOF {
+			break
+		}
+		values = append(values, tok.Value)
+	}
+
+	expected := []string{"a\nlonger value", "b\t", "block", "c\u00e9"}
+	if strings.Join(values, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, values)
+	}
+}
+
+func TestLexerLocationOffset(t *testing.T) {
+	l := New(&ast.Source{
+		Name:           "main.go",
+		Input:          "{ a\n  b }",
+		LocationOffset: ast.LocationOffset{Line: 14, Column: 20},
+	})
+
+	var positions []string
+	for {
+		tok, err := l.ReadToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind == EOF {
+			break
+		}
+		positions = append(positions, fmt.Sprintf("%s@%d:%d", tok.String(), tok.Pos.Line, tok.Pos.Column))
+	}
+
+	expected := []string{`{@14:20`, `Name "a"@14:22`, `Name "b"@15:3`, `}@15:5`}
+	if strings.Join(positions, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected %q, got %q", expected, positions)
+	}
+
+	l = New(&ast.Source{Input: "\n  ?", LocationOffset: ast.LocationOffset{Line: 3, Column: 5}})
+	_, err := l.ReadToken()
+	if err == nil || err.(*gqlerror.Error).Locations[0] != (gqlerror.Location{Line: 4, Column: 3}) {
+		t.Errorf("expected error at 4:3, got %v", err)
+	}
+}
+
+func TestSourceTokens(t *testing.T) {
+	src := &ast.Source{Input: "{ a # note\n  b }"}
+	var sources SourceTokens
+
+	tokens, i := sources.At(&ast.Position{Src: src, Start: 13})
+	if len(tokens) != 5 || i != 3 || tokens[i].Value != "b" {
+		t.Errorf("expected b at index 3 of 5 tokens, got %d of %d", i, len(tokens))
+	}
+	if _, i := sources.At(&ast.Position{Src: src, Start: 1}); i != -1 {
+		t.Errorf("expected no token at offset 1, got %d", i)
+	}
+	if tokens, i := sources.At(&ast.Position{Src: &ast.Source{Input: "{ a ?"}, Start: 2}); len(tokens) != 2 || i != 1 {
+		t.Errorf("expected the tokens before the error, got %d with a at %d", len(tokens), i)
+	}
+}
+
+func BenchmarkLexerEscapedStrings(b *testing.B) {
+	input := strings.Repeat(`f(a: "line\none", b: "tab\tseparated é", c: "plain") `, 100)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		l := New(&ast.Source{Input: input})
+		for {
+			tok, err := l.ReadToken()
+			if err != nil {
+				b.Fatal(err)
+			}
+			if tok.Kind == EOF {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package gqlparser
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+	rules "github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+// Limits gathers every protection against hostile queries in one place. Each limit is enforced as
+// early as possible: the size of the query before it is read, the parser limits while it is
+// parsed and the rest by validation rules. A zero value means unlimited.
+//
+// Every error caused by a limit has the LIMIT_EXCEEDED code, see gqlerror.ErrLimitExceeded.
+type Limits struct {
+	// MaxBytes is the size of the largest query accepted, checked before it is lexed.
+	MaxBytes int
+
+	// MaxTokens, MaxNodes, MaxValueDepth and MaxValueNodes are enforced while parsing, see
+	// parser.Limits.
+	MaxTokens     int
+	MaxNodes      int
+	MaxValueDepth int
+	MaxValueNodes int
+
+	// MaxDepth is how deeply fields may be nested. Fields are counted while parsing and counted
+	// again through fragment spreads by the MaxDepth rule.
+	MaxDepth int
+	// MaxAliases and MaxAliasesPerField configure the MaxAliases rule.
+	MaxAliases         int
+	MaxAliasesPerField int
+	// MaxDirectives and MaxDirectivesPerNode configure the MaxDirectives rule.
+	MaxDirectives        int
+	MaxDirectivesPerNode int
+	// MaxFragments, MaxFragmentExpansions and MaxFragmentDepth configure the MaxFragments rule.
+	MaxFragments          int
+	MaxFragmentExpansions int
+	MaxFragmentDepth      int
+	// MaxDuplicateFields configures the MaxDuplicateFields rule.
+	MaxDuplicateFields int
+	// MaxCost configures the MaxCost rule. Costs are estimated without variables, so list sizes
+	// given by variables fall back to the sizes assumed by the schema.
+	MaxCost int
+	// DisableIntrospection rejects queries selecting __schema or __type.
+	DisableIntrospection bool
+}
+
+// LimitsStrictPublicAPI suits APIs open to anyone, where every query may be hostile.
+var LimitsStrictPublicAPI = Limits{
+	MaxBytes:              32 * 1024,
+	MaxTokens:             5000,
+	MaxNodes:              2000,
+	MaxValueDepth:         5,
+	MaxValueNodes:         500,
+	MaxDepth:              10,
+	MaxAliases:            15,
+	MaxAliasesPerField:    10,
+	MaxDirectives:         50,
+	MaxDirectivesPerNode:  5,
+	MaxFragments:          50,
+	MaxFragmentExpansions: 200,
+	MaxFragmentDepth:      10,
+	MaxDuplicateFields:    5,
+	MaxCost:               10000,
+	DisableIntrospection:  true,
+}
+
+// LimitsInternal suits APIs only used by trusted clients, where limits guard against mistakes
+// rather than attacks.
+var LimitsInternal = Limits{
+	MaxBytes:              1024 * 1024,
+	MaxTokens:             50000,
+	MaxNodes:              20000,
+	MaxValueDepth:         20,
+	MaxValueNodes:         10000,
+	MaxDepth:              25,
+	MaxAliases:            100,
+	MaxDirectives:         500,
+	MaxDirectivesPerNode:  20,
+	MaxFragments:          500,
+	MaxFragmentExpansions: 5000,
+	MaxFragmentDepth:      25,
+	MaxDuplicateFields:    20,
+}
+
+// ParserOptions returns the parser options enforcing the parser limits.
+func (l Limits) ParserOptions() []parser.Option {
+	return []parser.Option{parser.WithLimits(parser.Limits{
+		MaxTokens:     l.MaxTokens,
+		MaxNodes:      l.MaxNodes,
+		MaxDepth:      l.MaxDepth,
+		MaxValueDepth: l.MaxValueDepth,
+		MaxValueNodes: l.MaxValueNodes,
+	})}
+}
+
+// Rules returns the validation rules enforcing the limits that need a schema or have to follow
+// fragment spreads, to be passed to validator.ValidateWithRules.
+func (l Limits) Rules() []validator.Rule {
+	var extra []validator.Rule
+	if l.MaxDepth > 0 {
+		extra = append(extra, rules.MaxDepth(l.MaxDepth))
+	}
+	if l.MaxAliases > 0 || l.MaxAliasesPerField > 0 {
+		extra = append(extra, rules.MaxAliases(rules.AliasLimits{
+			PerSelectionSet: l.MaxAliases,
+			PerField:        l.MaxAliasesPerField,
+		}))
+	}
+	if l.MaxDirectives > 0 || l.MaxDirectivesPerNode > 0 {
+		extra = append(extra, rules.MaxDirectives(rules.DirectiveLimits{
+			Total:   l.MaxDirectives,
+			PerNode: l.MaxDirectivesPerNode,
+		}))
+	}
+	if l.MaxFragments > 0 || l.MaxFragmentExpansions > 0 || l.MaxFragmentDepth > 0 {
+		extra = append(extra, rules.MaxFragments(rules.FragmentLimits{
+			Distinct:   l.MaxFragments,
+			Expansions: l.MaxFragmentExpansions,
+			Depth:      l.MaxFragmentDepth,
+		}))
+	}
+	if l.MaxDuplicateFields > 0 {
+		extra = append(extra, rules.MaxDuplicateFields(l.MaxDuplicateFields))
+	}
+	if l.MaxCost > 0 {
+		extra = append(extra, rules.MaxCost(l.MaxCost, nil))
+	}
+	if l.DisableIntrospection {
+		extra = append(extra, rules.NoIntrospection(gqlerror.SeverityError))
+	}
+	return extra
+}
+
+// LoadQueryWithLimits is LoadQuery enforcing limits.
+func LoadQueryWithLimits(schema *ast.Schema, str string, limits Limits) (*ast.QueryDocument, gqlerror.List) {
+	if limits.MaxBytes > 0 && len(str) > limits.MaxBytes {
+		err := gqlerror.Errorf("query is larger than the maximum of %d bytes", limits.MaxBytes)
+		err.SetCode(gqlerror.CodeLimitExceeded)
+		return nil, gqlerror.List{err}
+	}
+
+	query, err := parser.ParseQueryWithOptions(&ast.Source{Input: str}, limits.ParserOptions()...)
+	if err != nil {
+		return nil, gqlerror.List{gqlerror.WrapIfUnwrapped(err)}
+	}
+	errs := validator.ValidateWithRules(schema, query, limits.Rules()...).Errors
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return query, nil
+}
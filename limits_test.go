@@ -0,0 +1,68 @@
+package gqlparser_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestLoadQueryWithLimits(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { user: User }
+		type User { id: ID! friends: [User!]! }
+	`})
+
+	t.Run("accepts queries within the limits", func(t *testing.T) {
+		doc, errs := gqlparser.LoadQueryWithLimits(schema, `{ user { friends { id } } }`, gqlparser.LimitsStrictPublicAPI)
+		require.Empty(t, errs)
+		require.NotNil(t, doc)
+	})
+
+	t.Run("size", func(t *testing.T) {
+		_, errs := gqlparser.LoadQueryWithLimits(schema, `{ user { id } }`, gqlparser.Limits{MaxBytes: 10})
+		require.Len(t, errs, 1)
+		require.Equal(t, "query is larger than the maximum of 10 bytes", errs[0].Message)
+		require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+	})
+
+	t.Run("parser limits", func(t *testing.T) {
+		_, errs := gqlparser.LoadQueryWithLimits(schema, `{ user { id } }`, gqlparser.Limits{MaxTokens: 3})
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+	})
+
+	t.Run("depth counted through fragments", func(t *testing.T) {
+		query := `{ user { ...F } } fragment F on User { friends { id } }`
+		_, errs := gqlparser.LoadQueryWithLimits(schema, query, gqlparser.Limits{MaxDepth: 2})
+		require.Len(t, errs, 1)
+		require.Equal(t, "MaxDepth", errs[0].Rule)
+	})
+
+	t.Run("introspection", func(t *testing.T) {
+		query := `{ __schema { queryType { name } } }`
+		_, errs := gqlparser.LoadQueryWithLimits(schema, query, gqlparser.LimitsInternal)
+		require.Empty(t, errs)
+
+		_, errs = gqlparser.LoadQueryWithLimits(schema, query, gqlparser.LimitsStrictPublicAPI)
+		require.Len(t, errs, 1)
+		require.Equal(t, "NoIntrospection", errs[0].Rule)
+	})
+
+	t.Run("presets", func(t *testing.T) {
+		query := "{ " + strings.Repeat("user { id } ", 6) + "}"
+		_, errs := gqlparser.LoadQueryWithLimits(schema, query, gqlparser.LimitsStrictPublicAPI)
+		require.Len(t, errs, 1)
+		require.Equal(t, "MaxDuplicateFields", errs[0].Rule)
+
+		_, errs = gqlparser.LoadQueryWithLimits(schema, query, gqlparser.LimitsInternal)
+		require.Empty(t, errs)
+	})
+
+	t.Run("zero value is unlimited", func(t *testing.T) {
+		require.Empty(t, gqlparser.Limits{}.Rules())
+	})
+}
@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// insertTypename returns the edit adding __typename as the first selection of the field at pos.
+// It is separated from the next selection by the whitespace indenting that selection, so the
+// document keeps its layout, or by a space when there is none.
+func insertTypename(sources *lexer.SourceTokens, pos *ast.Position) (gqlerror.TextEdit, bool) {
+	tokens, i := sources.At(pos)
+	if i < 0 {
+		return gqlerror.TextEdit{}, false
+	}
+
+	// the selection set is the first brace outside of the arguments of the field and its directives
+	depth := 0
+	for ; i < len(tokens); i++ {
+		switch tokens[i].Kind {
+		case lexer.ParenL:
+			depth++
+		case lexer.ParenR:
+			depth--
+		}
+		if depth == 0 && tokens[i].Kind == lexer.BraceL {
+			break
+		}
+	}
+	// comments after the brace stay above the selections they describe
+	j := i + 1
+	for j < len(tokens) && tokens[j].Kind == lexer.Comment {
+		j++
+	}
+	if j >= len(tokens) {
+		return gqlerror.TextEdit{}, false
+	}
+
+	brace, next := tokens[i].Pos, tokens[j].Pos
+	gap := string([]rune(pos.Src.Input)[brace.End:next.Start])
+	separator := gap[strings.LastIndexFunc(gap, func(r rune) bool { return r != ' ' && r != '\t' && r != '\n' && r != '\r' })+1:]
+	if separator == "" {
+		separator = " "
+	}
+	return gqlerror.TextEdit{Start: next.Start, End: next.Start, NewText: "__typename" + separator}, true
+}
@@ -0,0 +1,151 @@
+// Package lint checks schema and query documents against style rules, such as naming
+// conventions and required descriptions, that the GraphQL spec leaves to each project.
+//
+// Problems are reported as gqlerror diagnostics, like the ones of the validator, with the name of
+// the rule and a code derived from it. Every rule reports warnings unless configured otherwise.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// ReportFunc reports a problem, described with the error options of the validator.
+type ReportFunc func(options ...validator.ErrorOption)
+
+// Rule is a named lint rule, checking schema documents, query documents or both.
+//
+// Query rules are given the schema the document was validated against, or nil when linting
+// without a schema. Rules needing the schema, or the definitions the validator sets on the nodes
+// of the document, do nothing without it.
+type Rule struct {
+	Name   string
+	Schema func(doc *ast.SchemaDocument, report ReportFunc)
+	Query  func(schema *ast.Schema, doc *ast.QueryDocument, report ReportFunc)
+}
+
+// Config selects the rules to run and the severity of what they report.
+//
+// Rules maps rule names to "error", "warning", "notice" or "off". Rules that aren't listed report
+// warnings.
+type Config struct {
+	Rules map[string]string `yaml:"rules" json:"rules"`
+}
+
+// LoadConfig reads a Config from a YAML or JSON file, such as:
+//
+//	rules:
+//	  RequireDescriptions: "off"
+//	  FieldNames: error
+func LoadConfig(filename string) (Config, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return Config{}, err
+	}
+	return ParseConfig(b)
+}
+
+// ParseConfig parses a Config from YAML or JSON. Unknown keys are an error.
+func ParseConfig(b []byte) (Config, error) {
+	var config Config
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// Linter runs the rules enabled by a Config.
+type Linter struct {
+	rules []configuredRule
+}
+
+type configuredRule struct {
+	Rule
+	severity gqlerror.Severity
+}
+
+// New returns a Linter running the built in rules, followed by extra, as configured by config.
+// Rule names and severities are checked, so a typo in a config file is an error rather than a
+// rule silently left running.
+func New(config Config, extra ...Rule) (*Linter, error) {
+	all := append(Rules(), extra...)
+	known := map[string]bool{}
+	for _, rule := range all {
+		known[rule.Name] = true
+	}
+	var names []string
+	for name := range config.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+
+	l := &Linter{}
+	for _, rule := range all {
+		severity := gqlerror.SeverityWarning
+		switch level := config.Rules[rule.Name]; level {
+		case "":
+		case "off":
+			continue
+		case "error":
+			severity = gqlerror.SeverityError
+		case "warning":
+			severity = gqlerror.SeverityWarning
+		case "notice":
+			severity = gqlerror.SeverityNotice
+		default:
+			return nil, fmt.Errorf("lint rule %s: unknown level %q, expected error, warning, notice or off", rule.Name, level)
+		}
+		l.rules = append(l.rules, configuredRule{Rule: rule, severity: severity})
+	}
+	return l, nil
+}
+
+// LintSchema runs the schema rules against doc. Definitions from built in sources, such as the
+// prelude, are not checked.
+func (l *Linter) LintSchema(doc *ast.SchemaDocument) gqlerror.Diagnostics {
+	var errs gqlerror.List
+	for _, rule := range l.rules {
+		if rule.Schema != nil {
+			rule.Schema(doc, l.reporter(rule, &errs))
+		}
+	}
+	return gqlerror.NewDiagnostics(errs)
+}
+
+// LintQuery runs the query rules against doc. schema can be nil, otherwise doc must have been
+// validated against it.
+func (l *Linter) LintQuery(schema *ast.Schema, doc *ast.QueryDocument) gqlerror.Diagnostics {
+	var errs gqlerror.List
+	for _, rule := range l.rules {
+		if rule.Query != nil {
+			rule.Query(schema, doc, l.reporter(rule, &errs))
+		}
+	}
+	return gqlerror.NewDiagnostics(errs)
+}
+
+func (l *Linter) reporter(rule configuredRule, errs *gqlerror.List) ReportFunc {
+	return func(options ...validator.ErrorOption) {
+		err := &gqlerror.Error{Rule: rule.Name}
+		for _, o := range options {
+			o(err)
+		}
+		if err.Code() == "" {
+			err.SetCode(gqlerror.RuleCode(rule.Name))
+		}
+		err.SetSeverity(rule.severity)
+		*errs = append(*errs, err)
+	}
+}
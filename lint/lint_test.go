@@ -0,0 +1,251 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lint"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func messages(errs gqlerror.List) []string {
+	var result []string
+	for _, err := range errs {
+		result = append(result, err.Rule+": "+err.Error())
+	}
+	return result
+}
+
+func parseSchema(t *testing.T, input string) *ast.SchemaDocument {
+	t.Helper()
+	doc, err := parser.ParseSchema(&ast.Source{Name: "schema.graphql", Input: input})
+	require.NoError(t, err)
+	return doc
+}
+
+func TestSchemaRules(t *testing.T) {
+	l, err := lint.New(lint.Config{})
+	require.NoError(t, err)
+
+	doc := parseSchema(t, `"The root."
+type Query {
+  "Users."
+  users(first_n: Int, order: Order = ASC): [user]
+  "A user."
+  user(id: ID!): user
+}
+
+type user {
+  name: String
+  id: ID!
+  Email_Address: String
+}
+
+"Orders."
+enum Order { ASC desc }
+
+"A filter."
+input Filter {
+  "Limit."
+  limit: Int = 10
+  "Offset."
+  offset: Int! = 0
+}
+
+extend type Query {
+  "Everything."
+  all: [user]
+}
+`)
+	d := l.LintSchema(doc)
+	require.Empty(t, d.Errors)
+	require.Empty(t, d.Notices)
+	require.Equal(t, []string{
+		"TypeNames: schema.graphql:9:6: The type name user should be PascalCase.",
+		"FieldNames: schema.graphql:4:9: The argument name Query.users(first_n) should be camelCase.",
+		"FieldNames: schema.graphql:12:3: The field name user.Email_Address should be camelCase.",
+		"EnumValueNames: schema.graphql:16:18: The enum value Order.desc should be UPPER_CASE.",
+		"RequireDescriptions: schema.graphql:9:6: The type user has no description.",
+		"RequireDescriptions: schema.graphql:10:3: The field user.name has no description.",
+		"RequireDescriptions: schema.graphql:11:3: The field user.id has no description.",
+		"RequireDescriptions: schema.graphql:12:3: The field user.Email_Address has no description.",
+		"NoNullableDefaults: schema.graphql:4:23: The argument Query.users(order) has a default value but is nullable, so null can still be passed. Make it non-null.",
+		"NoNullableDefaults: schema.graphql:20:4: The input field Filter.limit has a default value but is nullable, so null can still be passed. Make it non-null.",
+		"AlphabetizeFields: schema.graphql:5:4: The field Query.user should come before Query.users.",
+		"AlphabetizeFields: schema.graphql:11:3: The field user.id should come before user.name.",
+	}, messages(d.Warnings))
+
+	first := d.Warnings[0]
+	require.Equal(t, "TYPE_NAMES", first.Code())
+	require.Equal(t, []gqlerror.Location{{Line: 9, Column: 6}}, first.Locations)
+	require.Equal(t, []interface{}{doc.Definitions[1]}, first.Nodes())
+}
+
+func TestSchemaRulesSkipBuiltIns(t *testing.T) {
+	l, err := lint.New(lint.Config{})
+	require.NoError(t, err)
+
+	doc, err := parser.ParseSchema(validator.Prelude)
+	require.NoError(t, err)
+	require.Empty(t, l.LintSchema(doc).All())
+}
+
+func TestQueryRules(t *testing.T) {
+	l, err := lint.New(lint.Config{})
+	require.NoError(t, err)
+
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `query GetUsers($first_n: Int) { users(first_n: $first_n) { ...userFields } }
+{ users { id } }
+mutation delete_user { deleteUser }
+fragment userFields on User { id }
+`})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"OperationNames: query.graphql:2:1: Anonymous query operations should be named.",
+		"OperationNames: query.graphql:3:1: The operation name delete_user should be PascalCase.",
+		"FragmentNames: query.graphql:4:1: The fragment name userFields should be PascalCase.",
+		"VariableNames: query.graphql:1:16: The variable name $first_n should be camelCase.",
+	}, messages(l.LintQuery(nil, doc).Warnings))
+}
+
+func TestRequireTypename(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { node(id: ID): Node nodes: [Node] user: User search: [Result] }
+		interface Node { id: ID! }
+		type User implements Node { id: ID! friends: [Node] }
+		union Result = User
+	`})
+	l, err := lint.New(lint.Config{})
+	require.NoError(t, err)
+
+	src := &ast.Source{Name: "query.graphql", Input: `query Q {
+  node(id: "1") @include(if: true) {
+    id
+  }
+  nodes { __typename id }
+  user { friends { ... on User { id } } }
+  search { t: __typename }
+}
+`}
+	doc, err := parser.ParseQuery(src)
+	require.NoError(t, err)
+	require.Empty(t, validator.Validate(schema, doc))
+
+	d := l.LintQuery(schema, doc)
+	require.Equal(t, []string{
+		"RequireTypename: query.graphql:2:3: Selections of node, of the abstract type Node, should include __typename.",
+		"RequireTypename: query.graphql:6:10: Selections of friends, of the abstract type Node, should include __typename.",
+		"RequireTypename: query.graphql:7:3: Selections of search, of the abstract type Result, should include __typename.",
+	}, messages(d.Warnings))
+
+	var edits []gqlerror.TextEdit
+	for _, err := range d.Warnings {
+		require.Len(t, err.Fixes(), 1)
+		require.Equal(t, "Add __typename", err.Fixes()[0].Message)
+		edits = append(edits, err.Fixes()[0].Edits...)
+	}
+	fixed, err := gqlerror.ApplyFixes(src.Input, edits)
+	require.NoError(t, err)
+	require.Equal(t, `query Q {
+  node(id: "1") @include(if: true) {
+    __typename
+    id
+  }
+  nodes { __typename id }
+  user { friends { __typename ... on User { id } } }
+  search { __typename t: __typename }
+}
+`, fixed)
+
+	require.Empty(t, l.LintQuery(nil, doc).All())
+
+	t.Run("compact input", func(t *testing.T) {
+		src := &ast.Source{Name: "query.graphql", Input: "query Q{node(id:1){id}user{friends{ # the friends\n  id}}}"}
+		doc, err := parser.ParseQuery(src)
+		require.NoError(t, err)
+		require.Empty(t, validator.Validate(schema, doc))
+
+		var edits []gqlerror.TextEdit
+		for _, err := range l.LintQuery(schema, doc).Warnings {
+			edits = append(edits, err.Fixes()[0].Edits...)
+		}
+		fixed, err := gqlerror.ApplyFixes(src.Input, edits)
+		require.NoError(t, err)
+		require.Equal(t, "query Q{node(id:1){__typename id}user{friends{ # the friends\n  __typename\n  id}}}", fixed)
+	})
+}
+
+func TestConfig(t *testing.T) {
+	doc := parseSchema(t, `type Query { b: Int a: Int }`)
+
+	t.Run("severities", func(t *testing.T) {
+		l, err := lint.New(lint.Config{Rules: map[string]string{
+			"RequireDescriptions": "off",
+			"AlphabetizeFields":   "error",
+			"FieldNames":          "notice",
+		}})
+		require.NoError(t, err)
+
+		d := l.LintSchema(doc)
+		require.Equal(t, []string{
+			"AlphabetizeFields: schema.graphql:1:21: The field Query.a should come before Query.b.",
+		}, messages(d.Errors))
+		require.Empty(t, d.Warnings)
+		require.Equal(t, gqlerror.SeverityError, d.Errors[0].Severity())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "lint.yml")
+		require.NoError(t, os.WriteFile(name, []byte("rules:\n  RequireDescriptions: off\n  AlphabetizeFields: notice\n"), 0o644))
+		config, err := lint.LoadConfig(name)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"RequireDescriptions": "off", "AlphabetizeFields": "notice"}, config.Rules)
+
+		l, err := lint.New(config)
+		require.NoError(t, err)
+		d := l.LintSchema(doc)
+		require.Empty(t, d.Warnings)
+		require.Len(t, d.Notices, 1)
+
+		config, err = lint.ParseConfig([]byte(`{"rules": {"TypeNames": "error"}}`))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"TypeNames": "error"}, config.Rules)
+
+		_, err = lint.ParseConfig([]byte("rule:\n  TypeNames: error\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := lint.New(lint.Config{Rules: map[string]string{"TypeName": "off"}})
+		require.EqualError(t, err, `unknown lint rule "TypeName"`)
+
+		_, err = lint.New(lint.Config{Rules: map[string]string{"TypeNames": "fatal"}})
+		require.EqualError(t, err, `lint rule TypeNames: unknown level "fatal", expected error, warning, notice or off`)
+	})
+
+	t.Run("custom rules", func(t *testing.T) {
+		noQuery := lint.Rule{
+			Name: "NoBFields",
+			Schema: func(doc *ast.SchemaDocument, report lint.ReportFunc) {
+				for _, def := range doc.Definitions {
+					if field := def.Fields.ForName("b"); field != nil {
+						report(validator.Message("No b."), validator.At(field.Position), validator.Code("NO_B"))
+					}
+				}
+			},
+		}
+		l, err := lint.New(lint.Config{Rules: map[string]string{"NoBFields": "error"}}, noQuery)
+		require.NoError(t, err)
+		d := l.LintSchema(doc)
+		require.Equal(t, []string{"NoBFields: schema.graphql:1:14: No b."}, messages(d.Errors))
+		require.Equal(t, "NO_B", d.Errors[0].Code())
+	})
+}
@@ -0,0 +1,295 @@
+package lint
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lexer"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Rules returns the built in rules.
+func Rules() []Rule {
+	return []Rule{
+		{Name: "TypeNames", Schema: typeNames},
+		{Name: "FieldNames", Schema: fieldNames},
+		{Name: "EnumValueNames", Schema: enumValueNames},
+		{Name: "RequireDescriptions", Schema: requireDescriptions},
+		{Name: "NoNullableDefaults", Schema: noNullableDefaults},
+		{Name: "AlphabetizeFields", Schema: alphabetizeFields},
+		{Name: "OperationNames", Query: operationNames},
+		{Name: "FragmentNames", Query: fragmentNames},
+		{Name: "VariableNames", Query: variableNames},
+		{Name: "RequireTypename", Query: requireTypename},
+	}
+}
+
+// definitions returns the definitions and extensions of doc that don't come from built in
+// sources.
+func definitions(doc *ast.SchemaDocument) ast.DefinitionList {
+	var defs ast.DefinitionList
+	for _, list := range []ast.DefinitionList{doc.Definitions, doc.Extensions} {
+		for _, def := range list {
+			if !isBuiltIn(def.Position) {
+				defs = append(defs, def)
+			}
+		}
+	}
+	return defs
+}
+
+// typeNames requires type names in PascalCase.
+func typeNames(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range doc.Definitions {
+		if isBuiltIn(def.Position) || isPascalCase(def.Name) {
+			continue
+		}
+		report(
+			validator.Message("The type name %s should be PascalCase.", def.Name),
+			validator.At(def.Position),
+			validator.Nodes(def),
+		)
+	}
+}
+
+// fieldNames requires field, input field and argument names in camelCase.
+func fieldNames(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range definitions(doc) {
+		for _, field := range def.Fields {
+			if !isCamelCase(field.Name) {
+				report(
+					validator.Message("The field name %s.%s should be camelCase.", def.Name, field.Name),
+					validator.At(field.Position),
+					validator.Nodes(field),
+				)
+			}
+			for _, arg := range field.Arguments {
+				if !isCamelCase(arg.Name) {
+					report(
+						validator.Message("The argument name %s.%s(%s) should be camelCase.", def.Name, field.Name, arg.Name),
+						validator.At(arg.Position),
+						validator.Nodes(arg),
+					)
+				}
+			}
+		}
+	}
+}
+
+// enumValueNames requires enum values in UPPER_CASE.
+func enumValueNames(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range definitions(doc) {
+		for _, value := range def.EnumValues {
+			if !isUpperCase(value.Name) {
+				report(
+					validator.Message("The enum value %s.%s should be UPPER_CASE.", def.Name, value.Name),
+					validator.At(value.Position),
+					validator.Nodes(value),
+				)
+			}
+		}
+	}
+}
+
+// requireDescriptions requires a description on every type and field. Extensions can't have
+// descriptions, so only their fields are checked.
+func requireDescriptions(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range doc.Definitions {
+		if isBuiltIn(def.Position) {
+			continue
+		}
+		if strings.TrimSpace(def.Description) == "" {
+			report(
+				validator.Message("The type %s has no description.", def.Name),
+				validator.At(def.Position),
+				validator.Nodes(def),
+			)
+		}
+	}
+	for _, def := range definitions(doc) {
+		for _, field := range def.Fields {
+			if strings.TrimSpace(field.Description) == "" {
+				report(
+					validator.Message("The field %s.%s has no description.", def.Name, field.Name),
+					validator.At(field.Position),
+					validator.Nodes(field),
+				)
+			}
+		}
+	}
+}
+
+// noNullableDefaults reports nullable arguments and input fields with a default value. Clients can
+// still pass an explicit null, which replaces the default, so resolvers have to handle both.
+func noNullableDefaults(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range definitions(doc) {
+		for _, field := range def.Fields {
+			if def.Kind == ast.InputObject {
+				if field.DefaultValue != nil && !field.Type.NonNull {
+					report(
+						validator.Message("The input field %s.%s has a default value but is nullable, so null can still be passed. Make it non-null.", def.Name, field.Name),
+						validator.At(field.Position),
+						validator.Nodes(field),
+					)
+				}
+				continue
+			}
+			for _, arg := range field.Arguments {
+				if arg.DefaultValue != nil && !arg.Type.NonNull {
+					report(
+						validator.Message("The argument %s.%s(%s) has a default value but is nullable, so null can still be passed. Make it non-null.", def.Name, field.Name, arg.Name),
+						validator.At(arg.Position),
+						validator.Nodes(arg),
+					)
+				}
+			}
+		}
+	}
+}
+
+// alphabetizeFields requires the fields of each definition in alphabetical order, reporting the
+// first field out of order.
+func alphabetizeFields(doc *ast.SchemaDocument, report ReportFunc) {
+	for _, def := range definitions(doc) {
+		for i := 1; i < len(def.Fields); i++ {
+			prev, field := def.Fields[i-1], def.Fields[i]
+			if field.Name < prev.Name {
+				report(
+					validator.Message("The field %s.%s should come before %s.%s.", def.Name, field.Name, def.Name, prev.Name),
+					validator.At(field.Position),
+					validator.Nodes(field),
+				)
+				break
+			}
+		}
+	}
+}
+
+// operationNames requires every operation to be named in PascalCase, so it can be found in logs
+// and metrics.
+func operationNames(_ *ast.Schema, doc *ast.QueryDocument, report ReportFunc) {
+	for _, op := range doc.Operations {
+		switch {
+		case op.Name == "":
+			report(
+				validator.Message("Anonymous %s operations should be named.", op.Operation),
+				validator.At(op.Position),
+				validator.Nodes(op),
+			)
+		case !isPascalCase(op.Name):
+			report(
+				validator.Message("The operation name %s should be PascalCase.", op.Name),
+				validator.At(op.Position),
+				validator.Nodes(op),
+			)
+		}
+	}
+}
+
+// fragmentNames requires fragment names in PascalCase.
+func fragmentNames(_ *ast.Schema, doc *ast.QueryDocument, report ReportFunc) {
+	for _, frag := range doc.Fragments {
+		if !isPascalCase(frag.Name) {
+			report(
+				validator.Message("The fragment name %s should be PascalCase.", frag.Name),
+				validator.At(frag.Position),
+				validator.Nodes(frag),
+			)
+		}
+	}
+}
+
+// variableNames requires variable names in camelCase.
+func variableNames(_ *ast.Schema, doc *ast.QueryDocument, report ReportFunc) {
+	for _, op := range doc.Operations {
+		for _, def := range op.VariableDefinitions {
+			if !isCamelCase(def.Variable) {
+				report(
+					validator.Message("The variable name $%s should be camelCase.", def.Variable),
+					validator.At(def.Position),
+					validator.Nodes(def),
+				)
+			}
+		}
+	}
+}
+
+// requireTypename requires __typename in the selections of fields returning interfaces and unions,
+// so clients and caches can tell which type they got. It proposes adding it as a fix.
+func requireTypename(schema *ast.Schema, doc *ast.QueryDocument, report ReportFunc) {
+	if schema == nil {
+		return
+	}
+	var sources lexer.SourceTokens
+	var walk func(set ast.SelectionSet)
+	walk = func(set ast.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				walk(sel.SelectionSet)
+				if sel.Definition == nil || len(sel.SelectionSet) == 0 || hasTypename(sel.SelectionSet) {
+					continue
+				}
+				typ := schema.Types[sel.Definition.Type.Name()]
+				if typ == nil || !typ.IsAbstractType() {
+					continue
+				}
+				options := []validator.ErrorOption{
+					validator.Message("Selections of %s, of the abstract type %s, should include __typename.", sel.Alias, typ.Name),
+					validator.At(sel.Position),
+					validator.Nodes(sel),
+				}
+				if edit, ok := insertTypename(&sources, sel.Position); ok {
+					options = append(options, validator.Fix("Add __typename", edit))
+				}
+				report(options...)
+			case *ast.InlineFragment:
+				walk(sel.SelectionSet)
+			}
+		}
+	}
+	for _, op := range doc.Operations {
+		walk(op.SelectionSet)
+	}
+	for _, frag := range doc.Fragments {
+		walk(frag.SelectionSet)
+	}
+}
+
+func hasTypename(set ast.SelectionSet) bool {
+	for _, sel := range set {
+		if field, ok := sel.(*ast.Field); ok && field.Name == "__typename" && field.Alias == "__typename" {
+			return true
+		}
+	}
+	return false
+}
+
+func isBuiltIn(pos *ast.Position) bool {
+	return pos != nil && pos.Src != nil && pos.Src.BuiltIn
+}
+
+// isPascalCase reports whether name starts with an upper case letter and has no underscores.
+func isPascalCase(name string) bool {
+	return name != "" && unicode.IsUpper(rune(name[0])) && !strings.Contains(name, "_")
+}
+
+// isCamelCase reports whether name starts with a lower case letter and has no underscores.
+func isCamelCase(name string) bool {
+	return name != "" && unicode.IsLower(rune(name[0])) && !strings.Contains(name, "_")
+}
+
+// isUpperCase reports whether name is made of upper case letters, digits and underscores, starting
+// with a letter.
+func isUpperCase(name string) bool {
+	if name == "" || !unicode.IsUpper(rune(name[0])) {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,384 @@
+// Package lsp answers the questions a language server asks about a query document, such as what
+// is under the cursor, using a schema to resolve names.
+//
+// Positions are 1 based lines and columns counted in runes, like ast.Position. Language server
+// protocol positions are 0 based and count columns in UTF-16 code units, so clients need to
+// convert them first.
+package lsp
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// Kind is the kind of name found by Lookup.
+type Kind string
+
+const (
+	KindOperation  Kind = "operation"
+	KindField      Kind = "field"
+	KindArgument   Kind = "argument"
+	KindInputField Kind = "input field"
+	KindEnumValue  Kind = "enum value"
+	KindType       Kind = "type"
+	KindDirective  Kind = "directive"
+	KindFragment   Kind = "fragment"
+	KindVariable   Kind = "variable"
+)
+
+// Result describes the name under the cursor.
+type Result struct {
+	Kind Kind
+	Name string
+	// Node is the node of the document the name belongs to: an *ast.OperationDefinition,
+	// *ast.Field, *ast.Argument, *ast.ChildValue, *ast.Value, *ast.Directive,
+	// *ast.FragmentSpread, *ast.FragmentDefinition, *ast.InlineFragment or
+	// *ast.VariableDefinition.
+	Node interface{}
+	// Position is the position of the name, from its first character to one past its last.
+	Position ast.Position
+
+	// Definition is what the name refers to: an *ast.FieldDefinition, *ast.ArgumentDefinition,
+	// *ast.EnumValueDefinition, *ast.Definition or *ast.DirectiveDefinition of the schema, or an
+	// *ast.OperationDefinition, *ast.FragmentDefinition or *ast.VariableDefinition of the
+	// document. It is nil when the name doesn't exist.
+	Definition interface{}
+	// Description is the description of the definition, if it has one.
+	Description string
+	// DefinitionPosition is where the definition is, for going to it.
+	DefinitionPosition *ast.Position
+}
+
+// Lookup returns what is at line and column in doc, or nil if the cursor isn't on a name. The
+// source of doc is read again to find the names, so doc must have been parsed from a source.
+//
+// doc doesn't need to be valid. Names that don't exist in schema are still found, without a
+// definition.
+func Lookup(schema *ast.Schema, doc *ast.QueryDocument, line int, column int) *Result {
+	if doc == nil || doc.Position == nil || doc.Position.Src == nil {
+		return nil
+	}
+	w := newWalker(schema, doc, doc.Position.Src)
+	cursor := w.tokenAt(line, column)
+	if cursor < 0 {
+		return nil
+	}
+
+	var result *Result
+	w.visit = func(token int, kind Kind, node interface{}, definition interface{}) {
+		if token != cursor {
+			return
+		}
+		tok := w.tokens[token]
+		result = &Result{
+			Kind:     kind,
+			Name:     tok.Value,
+			Node:     node,
+			Position: tok.Pos,
+		}
+		result.Definition, result.Description, result.DefinitionPosition = describe(definition)
+		w.stop = true
+	}
+	w.walk()
+	return result
+}
+
+// walker walks a query document, calling visit with the token of every name in it along with
+// what the name refers to in the schema.
+type walker struct {
+	schema *ast.Schema
+	doc    *ast.QueryDocument
+	tokens []lexer.Token
+	// index holds the index in tokens of the token starting at each offset
+	index map[int]int
+	// operation is the operation being walked, to resolve variables
+	operation *ast.OperationDefinition
+
+	visit func(token int, kind Kind, node interface{}, definition interface{})
+	// stop is set by visit to end the walk early
+	stop bool
+}
+
+// newWalker lexes src, which doc was parsed from. schema can be nil, in which case no name has a
+// definition.
+func newWalker(schema *ast.Schema, doc *ast.QueryDocument, src *ast.Source) *walker {
+	if schema == nil {
+		schema = &ast.Schema{}
+	}
+	w := &walker{schema: schema, doc: doc, index: map[int]int{}}
+	lex := lexer.New(src)
+	for {
+		tok, err := lex.ReadToken()
+		if err != nil || tok.Kind == lexer.EOF {
+			break
+		}
+		w.index[tok.Pos.Start] = len(w.tokens)
+		w.tokens = append(w.tokens, tok)
+	}
+	return w
+}
+
+func (w *walker) walk() {
+	for _, op := range w.doc.Operations {
+		w.operation = op
+		w.found(w.after(op.Position, 1), op.Name, KindOperation, op, op)
+		w.variableDefinitions(op.VariableDefinitions)
+		w.directives(op.Directives)
+		w.selectionSet(rootType(w.schema, op.Operation), op.SelectionSet)
+		if w.stop {
+			return
+		}
+	}
+	w.operation = nil
+	for _, frag := range w.doc.Fragments {
+		// fragment Name on Type
+		w.found(w.after(frag.Position, 1), frag.Name, KindFragment, frag, frag)
+		typeCondition := w.schema.Types[frag.TypeCondition]
+		w.found(w.after(frag.Position, 3), frag.TypeCondition, KindType, frag, typeCondition)
+		w.variableDefinitions(frag.VariableDefinition)
+		w.directives(frag.Directives)
+		w.selectionSet(typeCondition, frag.SelectionSet)
+		if w.stop {
+			return
+		}
+	}
+}
+
+// tokenAt returns the index of the name token at line and column, or -1.
+func (w *walker) tokenAt(line int, column int) int {
+	for i, tok := range w.tokens {
+		if tok.Kind != lexer.Name || tok.Pos.Line != line {
+			continue
+		}
+		if column >= tok.Pos.Column && column < tok.Pos.Column+tok.Pos.End-tok.Pos.Start {
+			return i
+		}
+	}
+	return -1
+}
+
+// after returns the index of the token n tokens after the one at pos, skipping comments, or -1.
+func (w *walker) after(pos *ast.Position, n int) int {
+	if pos == nil {
+		return -1
+	}
+	i, ok := w.index[pos.Start]
+	if !ok {
+		return -1
+	}
+	for ; i < len(w.tokens); i++ {
+		if w.tokens[i].Kind == lexer.Comment {
+			continue
+		}
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return -1
+}
+
+// found visits token. Tokens are found by counting from the start of a node, so name is checked
+// to make sure the right one was found.
+func (w *walker) found(token int, name string, kind Kind, node interface{}, definition interface{}) {
+	if w.stop || token < 0 || w.tokens[token].Value != name {
+		return
+	}
+	w.visit(token, kind, node, definition)
+}
+
+// describe returns the definition, its description and its position, or nil if definition is a
+// nil pointer.
+func describe(definition interface{}) (interface{}, string, *ast.Position) {
+	switch def := definition.(type) {
+	case *ast.FieldDefinition:
+		if def != nil {
+			return def, def.Description, def.Position
+		}
+	case *ast.ArgumentDefinition:
+		if def != nil {
+			return def, def.Description, def.Position
+		}
+	case *ast.EnumValueDefinition:
+		if def != nil {
+			return def, def.Description, def.Position
+		}
+	case *ast.Definition:
+		if def != nil {
+			return def, def.Description, def.Position
+		}
+	case *ast.DirectiveDefinition:
+		if def != nil {
+			return def, def.Description, def.Position
+		}
+	case *ast.OperationDefinition:
+		if def != nil {
+			return def, "", def.Position
+		}
+	case *ast.FragmentDefinition:
+		if def != nil {
+			return def, "", def.Position
+		}
+	case *ast.VariableDefinition:
+		if def != nil {
+			return def, "", def.Position
+		}
+	}
+	return nil, "", nil
+}
+
+func (w *walker) variableDefinitions(defs ast.VariableDefinitionList) {
+	for _, def := range defs {
+		// the position of a variable definition is its $
+		w.found(w.after(def.Position, 1), def.Variable, KindVariable, def, def)
+		if def.Type != nil {
+			w.typ(def, def.Type)
+			w.value(def.DefaultValue, def.Type)
+		}
+		w.directives(def.Directives)
+	}
+}
+
+func (w *walker) typ(def *ast.VariableDefinition, t *ast.Type) {
+	if t.Elem != nil {
+		w.typ(def, t.Elem)
+		return
+	}
+	w.found(w.after(t.Position, 0), t.NamedType, KindType, def, w.schema.Types[t.NamedType])
+}
+
+func (w *walker) selectionSet(parent *ast.Definition, set ast.SelectionSet) {
+	for _, sel := range set {
+		if w.stop {
+			return
+		}
+		switch sel := sel.(type) {
+		case *ast.Field:
+			def := fieldDefinition(parent, sel.Name)
+			// alias: name, or only the name
+			w.found(w.after(sel.Position, 0), sel.Alias, KindField, sel, def)
+			if sel.Alias != sel.Name {
+				w.found(w.after(sel.Position, 2), sel.Name, KindField, sel, def)
+			}
+
+			var fieldType *ast.Definition
+			var args ast.ArgumentDefinitionList
+			if def != nil {
+				fieldType = w.schema.Types[def.Type.Name()]
+				args = def.Arguments
+			}
+			w.arguments(args, sel.Arguments)
+			w.directives(sel.Directives)
+			w.selectionSet(fieldType, sel.SelectionSet)
+		case *ast.FragmentSpread:
+			w.found(w.after(sel.Position, 0), sel.Name, KindFragment, sel, w.doc.Fragments.ForName(sel.Name))
+			w.directives(sel.Directives)
+		case *ast.InlineFragment:
+			typeCondition := parent
+			if sel.TypeCondition != "" {
+				// the position of an inline fragment with a type condition is its "on"
+				typeCondition = w.schema.Types[sel.TypeCondition]
+				w.found(w.after(sel.Position, 1), sel.TypeCondition, KindType, sel, typeCondition)
+			}
+			w.directives(sel.Directives)
+			w.selectionSet(typeCondition, sel.SelectionSet)
+		}
+	}
+}
+
+func (w *walker) arguments(defs ast.ArgumentDefinitionList, args ast.ArgumentList) {
+	for _, arg := range args {
+		def := defs.ForName(arg.Name)
+		w.found(w.after(arg.Position, 0), arg.Name, KindArgument, arg, def)
+		if def != nil {
+			w.value(arg.Value, def.Type)
+		} else {
+			w.value(arg.Value, nil)
+		}
+	}
+}
+
+func (w *walker) directives(directives ast.DirectiveList) {
+	for _, dir := range directives {
+		def := w.schema.Directives[dir.Name]
+		w.found(w.after(dir.Position, 0), dir.Name, KindDirective, dir, def)
+		var args ast.ArgumentDefinitionList
+		if def != nil {
+			args = def.Arguments
+		}
+		w.arguments(args, dir.Arguments)
+	}
+}
+
+// value searches v, resolving names against the expected type, which can be nil if unknown.
+func (w *walker) value(v *ast.Value, expected *ast.Type) {
+	if v == nil {
+		return
+	}
+	var def *ast.Definition
+	if expected != nil && expected.Elem == nil {
+		def = w.schema.Types[expected.NamedType]
+	}
+
+	switch v.Kind {
+	case ast.Variable:
+		// the position of a variable is its $
+		var variable *ast.VariableDefinition
+		if w.operation != nil {
+			variable = w.operation.VariableDefinitions.ForName(v.Raw)
+		}
+		w.found(w.after(v.Position, 1), v.Raw, KindVariable, v, variable)
+	case ast.EnumValue:
+		var value *ast.EnumValueDefinition
+		if def != nil {
+			value = def.EnumValues.ForName(v.Raw)
+		}
+		w.found(w.after(v.Position, 0), v.Raw, KindEnumValue, v, value)
+	case ast.ListValue:
+		var elem *ast.Type
+		if expected != nil {
+			elem = expected.Elem
+		}
+		for _, child := range v.Children {
+			w.value(child.Value, elem)
+		}
+	case ast.ObjectValue:
+		for _, child := range v.Children {
+			var field *ast.FieldDefinition
+			if def != nil {
+				field = def.Fields.ForName(child.Name)
+			}
+			w.found(w.after(child.Position, 0), child.Name, KindInputField, child, field)
+			if field != nil {
+				w.value(child.Value, field.Type)
+			} else {
+				w.value(child.Value, nil)
+			}
+		}
+	}
+}
+
+func fieldDefinition(parent *ast.Definition, name string) *ast.FieldDefinition {
+	if name == "__typename" {
+		return &ast.FieldDefinition{
+			Name:        "__typename",
+			Description: "The name of the current Object type at runtime.",
+			Type:        ast.NonNullNamedType("String", nil),
+		}
+	}
+	if parent == nil {
+		return nil
+	}
+	return parent.Fields.ForName(name)
+}
+
+func rootType(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	default:
+		return schema.Query
+	}
+}
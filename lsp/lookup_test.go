@@ -0,0 +1,195 @@
+package lsp_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lsp"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `type Query {
+	"Finds a user."
+	user(
+		"The id of the user."
+		id: ID!
+		filter: Filter
+	): User
+}
+
+"Filters users."
+input Filter { role: Role }
+
+enum Role {
+	ADMIN
+	"A regular user."
+	MEMBER @deprecated
+}
+
+type User {
+	id: ID!
+	name: String
+}
+
+"Caches the field."
+directive @cached(ttl: Int) on FIELD
+`})
+
+const query = `query Q($id: ID!, $role: Role = ADMIN) {
+  user(id: $id, filter: {role: MEMBER}) @cached(ttl: 10) {
+    full: name
+    ...UserFields
+    ... on User { id __typename }
+    unknown
+  }
+}
+
+fragment UserFields on User { id }
+`
+
+// at returns the line and column of the nth occurrence of marker in query, plus offset.
+func at(t *testing.T, marker string, n int, offset int) (int, int) {
+	t.Helper()
+	index := -1
+	for i := 0; i <= n; i++ {
+		next := strings.Index(query[index+1:], marker)
+		require.NotEqual(t, -1, next, "marker %s not found", marker)
+		index += next + 1
+	}
+	index += offset
+	line := strings.Count(query[:index], "\n") + 1
+	return line, index - strings.LastIndex(query[:index], "\n")
+}
+
+func TestLookup(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+	require.NoError(t, err)
+
+	lookup := func(t *testing.T, marker string, n int) *lsp.Result {
+		t.Helper()
+		line, column := at(t, marker, n, 1)
+		return lsp.Lookup(schema, doc, line, column)
+	}
+
+	t.Run("field", func(t *testing.T) {
+		r := lookup(t, "user", 0)
+		require.NotNil(t, r)
+		require.Equal(t, lsp.KindField, r.Kind)
+		require.Equal(t, "user", r.Name)
+		require.Same(t, doc.Operations[0].SelectionSet[0], r.Node)
+		require.Same(t, schema.Query.Fields.ForName("user"), r.Definition)
+		require.Equal(t, "Finds a user.", r.Description)
+		require.Equal(t, "schema.graphql", r.DefinitionPosition.Src.Name)
+		// definitions start at their description
+		require.Equal(t, 2, r.DefinitionPosition.Line)
+		require.Equal(t, 2, r.Position.Line)
+		require.Equal(t, 3, r.Position.Column)
+		require.Equal(t, 4, r.Position.End-r.Position.Start)
+	})
+
+	t.Run("aliased field", func(t *testing.T) {
+		for _, marker := range []string{"full", "name"} {
+			r := lookup(t, marker, 0)
+			require.NotNil(t, r, marker)
+			require.Equal(t, lsp.KindField, r.Kind)
+			require.Same(t, schema.Types["User"].Fields.ForName("name"), r.Definition)
+		}
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		r := lookup(t, "id:", 1)
+		require.Equal(t, lsp.KindArgument, r.Kind)
+		require.Equal(t, "The id of the user.", r.Description)
+	})
+
+	t.Run("input field and enum value", func(t *testing.T) {
+		r := lookup(t, "role:", 1)
+		require.Equal(t, lsp.KindInputField, r.Kind)
+		require.Same(t, schema.Types["Filter"].Fields.ForName("role"), r.Definition)
+
+		r = lookup(t, "MEMBER", 0)
+		require.Equal(t, lsp.KindEnumValue, r.Kind)
+		require.Equal(t, "A regular user.", r.Description)
+
+		r = lookup(t, "ADMIN", 0)
+		require.Equal(t, lsp.KindEnumValue, r.Kind)
+		require.Same(t, schema.Types["Role"].EnumValues.ForName("ADMIN"), r.Definition)
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		def := doc.Operations[0].VariableDefinitions[0]
+
+		r := lookup(t, "$id", 1)
+		require.Equal(t, lsp.KindVariable, r.Kind)
+		require.Equal(t, "id", r.Name)
+		require.Same(t, def, r.Definition)
+		require.Same(t, def.Position, r.DefinitionPosition)
+
+		r = lookup(t, "$id", 0)
+		require.Equal(t, lsp.KindVariable, r.Kind)
+		require.Same(t, def, r.Node)
+	})
+
+	t.Run("types", func(t *testing.T) {
+		r := lookup(t, "Role =", 0)
+		require.Equal(t, lsp.KindType, r.Kind)
+		require.Same(t, schema.Types["Role"], r.Definition)
+
+		r = lookup(t, "User {", 0)
+		require.Equal(t, lsp.KindType, r.Kind)
+		require.IsType(t, &ast.InlineFragment{}, r.Node)
+
+		r = lookup(t, "User {", 1)
+		require.Equal(t, lsp.KindType, r.Kind)
+		require.Same(t, doc.Fragments[0], r.Node)
+		require.Same(t, schema.Types["User"], r.Definition)
+	})
+
+	t.Run("directive", func(t *testing.T) {
+		r := lookup(t, "cached", 0)
+		require.Equal(t, lsp.KindDirective, r.Kind)
+		require.Equal(t, "Caches the field.", r.Description)
+
+		r = lookup(t, "ttl", 0)
+		require.Equal(t, lsp.KindArgument, r.Kind)
+		require.Same(t, schema.Directives["cached"].Arguments.ForName("ttl"), r.Definition)
+	})
+
+	t.Run("fragments and operations", func(t *testing.T) {
+		r := lookup(t, "UserFields", 0)
+		require.Equal(t, lsp.KindFragment, r.Kind)
+		require.IsType(t, &ast.FragmentSpread{}, r.Node)
+		require.Same(t, doc.Fragments[0], r.Definition)
+		require.Equal(t, 10, r.DefinitionPosition.Line)
+
+		r = lookup(t, "UserFields", 1)
+		require.Equal(t, lsp.KindFragment, r.Kind)
+		require.Same(t, doc.Fragments[0], r.Node)
+
+		line, column := at(t, "Q(", 0, 0)
+		r = lsp.Lookup(schema, doc, line, column)
+		require.Equal(t, lsp.KindOperation, r.Kind)
+		require.Same(t, doc.Operations[0], r.Definition)
+	})
+
+	t.Run("meta and unknown fields", func(t *testing.T) {
+		r := lookup(t, "__typename", 0)
+		require.Equal(t, lsp.KindField, r.Kind)
+		require.NotEmpty(t, r.Description)
+
+		r = lookup(t, "unknown", 0)
+		require.Equal(t, lsp.KindField, r.Kind)
+		require.Nil(t, r.Definition)
+		require.Nil(t, r.DefinitionPosition)
+	})
+
+	t.Run("not on a name", func(t *testing.T) {
+		require.Nil(t, lsp.Lookup(schema, doc, 1, 1+len("query Q(")))
+		require.Nil(t, lsp.Lookup(schema, doc, 100, 1))
+		require.Nil(t, lsp.Lookup(schema, &ast.QueryDocument{}, 1, 1))
+	})
+}
@@ -0,0 +1,166 @@
+package lsp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Symbol is a named definition found in a schema or query document.
+type Symbol struct {
+	Kind Kind
+	Name string
+	// Container is the name of the type holding a field, input field or enum value, and empty for
+	// other symbols.
+	Container string
+	// Position is where the symbol is defined.
+	Position *ast.Position
+}
+
+// Source returns the name of the source the symbol was defined in.
+func (s Symbol) Source() string {
+	if s.Position == nil || s.Position.Src == nil {
+		return ""
+	}
+	return s.Position.Src.Name
+}
+
+// Index holds the symbols of a workspace, for listing the symbols of a file and searching the
+// symbols of every file.
+//
+// Files are added as they are parsed and replaced when they change. An Index is not safe for
+// concurrent use.
+type Index struct {
+	// bySource holds the symbols of each source in the order they were defined
+	bySource map[string][]Symbol
+	// byName holds every symbol, sorted by name. It is rebuilt on the next search after a change.
+	byName []Symbol
+	dirty  bool
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{bySource: map[string][]Symbol{}}
+}
+
+// AddSchemaDocument indexes the types, fields, enum values and directives defined or extended in
+// doc, replacing the symbols previously indexed for its source.
+func (idx *Index) AddSchemaDocument(doc *ast.SchemaDocument) {
+	var symbols []Symbol
+	for _, list := range []ast.DefinitionList{doc.Definitions, doc.Extensions} {
+		for _, def := range list {
+			symbols = append(symbols, Symbol{Kind: KindType, Name: def.Name, Position: def.Position})
+			for _, field := range def.Fields {
+				kind := KindField
+				if def.Kind == ast.InputObject {
+					kind = KindInputField
+				}
+				symbols = append(symbols, Symbol{Kind: kind, Name: field.Name, Container: def.Name, Position: field.Position})
+			}
+			for _, value := range def.EnumValues {
+				symbols = append(symbols, Symbol{Kind: KindEnumValue, Name: value.Name, Container: def.Name, Position: value.Position})
+			}
+		}
+	}
+	for _, dir := range doc.Directives {
+		symbols = append(symbols, Symbol{Kind: KindDirective, Name: dir.Name, Position: dir.Position})
+	}
+	idx.set(doc.Position, symbols)
+}
+
+// AddQueryDocument indexes the named operations and the fragments of doc, replacing the symbols
+// previously indexed for its source.
+func (idx *Index) AddQueryDocument(doc *ast.QueryDocument) {
+	var symbols []Symbol
+	for _, op := range doc.Operations {
+		if op.Name != "" {
+			symbols = append(symbols, Symbol{Kind: KindOperation, Name: op.Name, Position: op.Position})
+		}
+	}
+	for _, frag := range doc.Fragments {
+		symbols = append(symbols, Symbol{Kind: KindFragment, Name: frag.Name, Position: frag.Position})
+	}
+	idx.set(doc.Position, symbols)
+}
+
+func (idx *Index) set(pos *ast.Position, symbols []Symbol) {
+	name := ""
+	if pos != nil && pos.Src != nil {
+		name = pos.Src.Name
+	}
+	if symbols == nil {
+		delete(idx.bySource, name)
+	} else {
+		idx.bySource[name] = symbols
+	}
+	idx.dirty = true
+}
+
+// Remove removes the symbols of the named source, when a file is deleted.
+func (idx *Index) Remove(source string) {
+	if _, ok := idx.bySource[source]; ok {
+		delete(idx.bySource, source)
+		idx.dirty = true
+	}
+}
+
+// Document returns the symbols of the named source, in the order they are defined.
+func (idx *Index) Document(source string) []Symbol {
+	return idx.bySource[source]
+}
+
+// Lookup returns the symbols with the given name.
+func (idx *Index) Lookup(name string) []Symbol {
+	symbols := idx.sorted()
+	i := sort.Search(len(symbols), func(i int) bool { return symbols[i].Name >= name })
+	j := i
+	for j < len(symbols) && symbols[j].Name == name {
+		j++
+	}
+	return symbols[i:j:j]
+}
+
+// Prefix returns the symbols whose name starts with prefix, sorted by name. The match is case
+// sensitive.
+func (idx *Index) Prefix(prefix string) []Symbol {
+	symbols := idx.sorted()
+	i := sort.Search(len(symbols), func(i int) bool { return symbols[i].Name >= prefix })
+	j := i
+	for j < len(symbols) && strings.HasPrefix(symbols[j].Name, prefix) {
+		j++
+	}
+	return symbols[i:j:j]
+}
+
+// sorted returns every symbol sorted by name, then by source and position so that results are
+// stable.
+func (idx *Index) sorted() []Symbol {
+	if !idx.dirty {
+		return idx.byName
+	}
+	// results of earlier searches share the old slice, so a new one is built
+	idx.byName = nil
+	for _, symbols := range idx.bySource {
+		idx.byName = append(idx.byName, symbols...)
+	}
+	sort.Slice(idx.byName, func(i, j int) bool {
+		a, b := idx.byName[i], idx.byName[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Source() != b.Source() {
+			return a.Source() < b.Source()
+		}
+		return start(a.Position) < start(b.Position)
+	})
+	idx.dirty = false
+	return idx.byName
+}
+
+func start(pos *ast.Position) int {
+	if pos == nil {
+		return -1
+	}
+	return pos.Start
+}
@@ -0,0 +1,115 @@
+package lsp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lsp"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func names(symbols []lsp.Symbol) []string {
+	var result []string
+	for _, s := range symbols {
+		name := s.Name
+		if s.Container != "" {
+			name = s.Container + "." + name
+		}
+		result = append(result, string(s.Kind)+" "+name+" "+s.Source())
+	}
+	return result
+}
+
+func TestIndex(t *testing.T) {
+	schemaDoc, err := parser.ParseSchema(&ast.Source{Name: "schema.graphql", Input: `
+		type Query { user: User users: [User] }
+		"A user."
+		type User { id: ID! name: String }
+		input UserFilter { name: String }
+		enum Role { ADMIN USER }
+		extend type User { role: Role }
+		directive @cached on FIELD
+	`})
+	require.NoError(t, err)
+	queryDoc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `
+		query Users { users { ...UserFields } }
+		{ user { id } }
+		fragment UserFields on User { id name }
+	`})
+	require.NoError(t, err)
+
+	idx := lsp.NewIndex()
+	idx.AddSchemaDocument(schemaDoc)
+	idx.AddQueryDocument(queryDoc)
+
+	t.Run("document", func(t *testing.T) {
+		require.Equal(t, []string{
+			"type Query schema.graphql",
+			"field Query.user schema.graphql",
+			"field Query.users schema.graphql",
+			"type User schema.graphql",
+			"field User.id schema.graphql",
+			"field User.name schema.graphql",
+			"type UserFilter schema.graphql",
+			"input field UserFilter.name schema.graphql",
+			"type Role schema.graphql",
+			"enum value Role.ADMIN schema.graphql",
+			"enum value Role.USER schema.graphql",
+			"type User schema.graphql",
+			"field User.role schema.graphql",
+			"directive cached schema.graphql",
+		}, names(idx.Document("schema.graphql")))
+
+		require.Equal(t, []string{
+			"operation Users query.graphql",
+			"fragment UserFields query.graphql",
+		}, names(idx.Document("query.graphql")))
+
+		user := idx.Document("schema.graphql")[3]
+		require.Equal(t, 4, user.Position.Line)
+		require.Nil(t, idx.Document("missing.graphql"))
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		require.Equal(t, []string{
+			"field User.name schema.graphql",
+			"input field UserFilter.name schema.graphql",
+		}, names(idx.Lookup("name")))
+		require.Equal(t, []string{
+			"type User schema.graphql",
+			"type User schema.graphql",
+		}, names(idx.Lookup("User")))
+		require.Empty(t, idx.Lookup("Use"))
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		require.Equal(t, []string{
+			"type User schema.graphql",
+			"type User schema.graphql",
+			"fragment UserFields query.graphql",
+			"type UserFilter schema.graphql",
+			"operation Users query.graphql",
+		}, names(idx.Prefix("User")))
+		require.Len(t, idx.Prefix(""), 16)
+		require.Empty(t, idx.Prefix("zzz"))
+	})
+
+	t.Run("update and remove", func(t *testing.T) {
+		users := idx.Lookup("Users")
+
+		queryDoc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: `query AllUsers { users { id } }`})
+		require.NoError(t, err)
+		idx.AddQueryDocument(queryDoc)
+		require.Empty(t, idx.Lookup("Users"))
+		require.Equal(t, []string{"operation AllUsers query.graphql"}, names(idx.Lookup("AllUsers")))
+		// earlier results are not changed by updates
+		require.Equal(t, []string{"operation Users query.graphql"}, names(users))
+
+		idx.Remove("query.graphql")
+		require.Empty(t, idx.Lookup("AllUsers"))
+		require.Nil(t, idx.Document("query.graphql"))
+		require.Len(t, idx.Prefix(""), 14)
+	})
+}
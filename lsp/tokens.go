@@ -0,0 +1,172 @@
+package lsp
+
+import (
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lexer"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// TokenType classifies a semantic token. The values are the standard token types of the language
+// server protocol.
+type TokenType string
+
+const (
+	TokenKeyword   TokenType = "keyword"
+	TokenTypeName  TokenType = "type"
+	TokenField     TokenType = "property"
+	TokenArgument  TokenType = "parameter"
+	TokenVariable  TokenType = "variable"
+	TokenEnumValue TokenType = "enumMember"
+	TokenDirective TokenType = "decorator"
+	TokenOperation TokenType = "function"
+	TokenFragment  TokenType = "macro"
+	TokenString    TokenType = "string"
+	TokenNumber    TokenType = "number"
+	TokenComment   TokenType = "comment"
+)
+
+var tokenTypes = map[Kind]TokenType{
+	KindOperation:  TokenOperation,
+	KindField:      TokenField,
+	KindArgument:   TokenArgument,
+	KindInputField: TokenField,
+	KindEnumValue:  TokenEnumValue,
+	KindType:       TokenTypeName,
+	KindDirective:  TokenDirective,
+	KindFragment:   TokenFragment,
+	KindVariable:   TokenVariable,
+}
+
+var keywords = map[string]bool{
+	"query":        true,
+	"mutation":     true,
+	"subscription": true,
+	"fragment":     true,
+	"on":           true,
+	"true":         true,
+	"false":        true,
+	"null":         true,
+}
+
+// SemanticToken is a classified range of a document.
+type SemanticToken struct {
+	Type TokenType
+	// Position is the range of the token. Variables and directives include their $ or @. Block
+	// strings can span several lines, which clients that don't support multiline tokens need to
+	// split.
+	Position ast.Position
+
+	// Declaration is set on the names of operations and fragment definitions, and on variable
+	// definitions.
+	Declaration bool
+	// Deprecated is set on fields, arguments, input fields and enum values marked @deprecated.
+	Deprecated bool
+	// Unknown is set on names that don't exist. Names resolved against the schema are only
+	// checked when a schema is given, fragments and variables are always checked.
+	Unknown bool
+}
+
+// SemanticTokens classifies the tokens of the query document in src, for semantic highlighting.
+// The tokens are returned in order, and punctuation is left out.
+//
+// schema can be nil. Documents with syntax errors are classified as far as they can be parsed.
+func SemanticTokens(schema *ast.Schema, src *ast.Source) []SemanticToken {
+	doc, _ := parser.ParseQueryWithOptions(src, parser.WithErrorRecovery(0))
+	if doc == nil {
+		doc = &ast.QueryDocument{}
+	}
+	w := newWalker(schema, doc, src)
+
+	names := make([]*SemanticToken, len(w.tokens))
+	w.visit = func(token int, kind Kind, node interface{}, definition interface{}) {
+		if names[token] != nil {
+			return
+		}
+		def, _, _ := describe(definition)
+		t := &SemanticToken{
+			Type:       tokenTypes[kind],
+			Position:   w.tokens[token].Pos,
+			Deprecated: isDeprecated(def),
+		}
+		switch node.(type) {
+		case *ast.OperationDefinition, *ast.FragmentDefinition, *ast.VariableDefinition:
+			t.Declaration = kind != KindType
+		}
+		if def == nil && kind != KindOperation {
+			t.Unknown = schema != nil || kind == KindFragment || kind == KindVariable
+		}
+		names[token] = t
+	}
+	w.walk()
+
+	var lines []int
+	var result []SemanticToken
+	for i, tok := range w.tokens {
+		switch tok.Kind {
+		case lexer.Comment:
+			result = append(result, SemanticToken{Type: TokenComment, Position: tok.Pos})
+		case lexer.String, lexer.BlockString:
+			if lines == nil {
+				lines = lineStarts(src.Input)
+			}
+			result = append(result, SemanticToken{Type: TokenString, Position: stringPosition(src, lines, tok.Pos)})
+		case lexer.Int, lexer.Float:
+			result = append(result, SemanticToken{Type: TokenNumber, Position: tok.Pos})
+		case lexer.Name:
+			if t := names[i]; t != nil {
+				// variables and directives start at their $ or @
+				if i > 0 && (t.Type == TokenVariable || t.Type == TokenDirective) {
+					prev := w.tokens[i-1]
+					if (prev.Kind == lexer.Dollar || prev.Kind == lexer.At) && prev.Pos.End == tok.Pos.Start {
+						t.Position.Start, t.Position.Line, t.Position.Column = prev.Pos.Start, prev.Pos.Line, prev.Pos.Column
+					}
+				}
+				result = append(result, *t)
+			} else if keywords[tok.Value] {
+				result = append(result, SemanticToken{Type: TokenKeyword, Position: tok.Pos})
+			}
+		}
+	}
+	return result
+}
+
+// lineStarts returns the rune offset of the start of each line of input.
+func lineStarts(input string) []int {
+	lines := []int{0}
+	runes := []rune(input)
+	for i, r := range runes {
+		if r == '\n' || (r == '\r' && (i+1 == len(runes) || runes[i+1] != '\n')) {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// stringPosition returns pos with the line and column of its start. The lexer gives strings the
+// column after their opening quote, and block strings the line they end on.
+func stringPosition(src *ast.Source, lines []int, pos ast.Position) ast.Position {
+	line := sort.Search(len(lines), func(i int) bool { return lines[i] > pos.Start })
+	pos.Line, pos.Column = line, pos.Start-lines[line-1]+1
+	if line == 1 && src.LocationOffset.Column > 1 {
+		pos.Column += src.LocationOffset.Column - 1
+	}
+	if src.LocationOffset.Line > 1 {
+		pos.Line += src.LocationOffset.Line - 1
+	}
+	return pos
+}
+
+func isDeprecated(definition interface{}) bool {
+	var directives ast.DirectiveList
+	switch def := definition.(type) {
+	case *ast.FieldDefinition:
+		directives = def.Directives
+	case *ast.ArgumentDefinition:
+		directives = def.Directives
+	case *ast.EnumValueDefinition:
+		directives = def.Directives
+	}
+	return directives.ForName("deprecated") != nil
+}
@@ -0,0 +1,105 @@
+package lsp_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lsp"
+)
+
+// describeTokens prints each token with its text, type and modifiers.
+func describeTokens(src *ast.Source, tokens []lsp.SemanticToken) []string {
+	runes := []rune(src.Input)
+	var result []string
+	for _, t := range tokens {
+		s := fmt.Sprintf("%d:%d %s %s", t.Position.Line, t.Position.Column, string(runes[t.Position.Start:t.Position.End]), t.Type)
+		if t.Declaration {
+			s += " declaration"
+		}
+		if t.Deprecated {
+			s += " deprecated"
+		}
+		if t.Unknown {
+			s += " unknown"
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestSemanticTokens(t *testing.T) {
+	src := &ast.Source{Name: "query.graphql", Input: `# find a user
+query Q($id: ID!, $role: Role = ADMIN) {
+  user(id: $id, filter: {role: MEMBER, name: "bob"}) @cached(ttl: 10) {
+    full: name
+    ...UserFields
+    ... on Nope { query }
+    missing @include(if: true)
+  }
+}
+
+fragment UserFields on User { id }
+`}
+
+	require.Equal(t, []string{
+		"1:1 # find a user comment",
+		"2:1 query keyword",
+		"2:7 Q function declaration",
+		"2:9 $id variable declaration",
+		"2:14 ID type",
+		"2:19 $role variable declaration",
+		"2:26 Role type",
+		"2:33 ADMIN enumMember",
+		"3:3 user property",
+		"3:8 id parameter",
+		"3:12 $id variable",
+		"3:17 filter parameter",
+		"3:26 role property",
+		"3:32 MEMBER enumMember deprecated",
+		"3:40 name property unknown",
+		"3:46 \"bob\" string",
+		"3:54 @cached decorator",
+		"3:62 ttl parameter",
+		"3:67 10 number",
+		"4:5 full property",
+		"4:11 name property",
+		"5:8 UserFields macro",
+		"6:9 on keyword",
+		"6:12 Nope type unknown",
+		"6:19 query property unknown",
+		"7:5 missing property unknown",
+		"7:13 @include decorator",
+		"7:22 if parameter",
+		"7:26 true keyword",
+		"11:1 fragment keyword",
+		"11:10 UserFields macro declaration",
+		"11:21 on keyword",
+		"11:24 User type",
+		"11:31 id property",
+	}, describeTokens(src, lsp.SemanticTokens(schema, src)))
+}
+
+func TestSemanticTokensWithoutSchema(t *testing.T) {
+	src := &ast.Source{Input: `query ($v: Int) { a(b: $v, c: $w) { ...F } }
+query Broken """
+  block
+"""`}
+
+	require.Equal(t, []string{
+		"1:1 query keyword",
+		"1:8 $v variable declaration",
+		"1:12 Int type",
+		"1:19 a property",
+		"1:21 b parameter",
+		"1:24 $v variable",
+		"1:28 c parameter",
+		"1:31 $w variable unknown",
+		"1:40 F macro unknown",
+		"2:1 query keyword",
+		"2:7 Broken function declaration",
+		"2:14 \"\"\"\n  block\n\"\"\" string",
+	}, describeTokens(src, lsp.SemanticTokens(nil, src)))
+}
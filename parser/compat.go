@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// describeToken describes tok for use in a syntax error.
+func (p *parser) describeToken(tok lexer.Token) string {
+	if !p.jsMessages {
+		return tok.String()
+	}
+	if tok.Kind == lexer.EOF || isPunctuator(tok.Kind) {
+		return p.describeKind(tok.Kind)
+	}
+	// graphql-js prints values as they are, without escaping
+	return p.describeKind(tok.Kind) + ` "` + tok.Value + `"`
+}
+
+// describeFound describes the token that was found when another was expected. graphql-js
+// includes the value of the token, this parser only names its kind.
+func (p *parser) describeFound(tok lexer.Token) string {
+	if !p.jsMessages {
+		return tok.Kind.String()
+	}
+	return p.describeToken(tok)
+}
+
+// describeKind describes a kind of token for use in a syntax error.
+func (p *parser) describeKind(kind lexer.Type) string {
+	if p.jsMessages && isPunctuator(kind) {
+		return `"` + kind.String() + `"`
+	}
+	return kind.String()
+}
+
+func isPunctuator(kind lexer.Type) bool {
+	return kind >= lexer.Bang && kind <= lexer.Pipe
+}
+
+// jsSyntaxError rewrites err the way graphql-js reports syntax errors, as
+// "Syntax Error: <message>."
+func jsSyntaxError(err *gqlerror.Error) {
+	err.Message = "Syntax Error: " + err.Message
+	if n := len(err.Message); err.Message[n-1] != '.' && err.Message[n-1] != '?' {
+		err.Message += "."
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var fuzzSeeds = []string{
+	`{ a }`,
+	`query Q($a: [Int!]! = [1, 2]) @dir(x: {a: "b"}) { a(b: $a) { ...F ... on T { c } } } fragment F on T { d }`,
+	`type Query implements A & B @key(fields: "id") { a(b: [Int!] = [1]): String! @deprecated }`,
+	`"""desc""" scalar S extend schema { query: Q } directive @d(a: Int) repeatable on FIELD | QUERY`,
+	`union U = | A | B enum E { A B } input I { a: Int = 1 }`,
+	`{ a(b: "é\n") c: d(e: 1.5e10, f: -0, g: """block "" \""" """) }`,
+	`{ a(b: "\u`,
+	`{ a(b: "😀 \uDE00") }`,
+	`{ a(b: 123abc) }`,
+	`{ a(b: 1.) }`,
+	"{ a(b: \"\x00\") }",
+	"\xef\xbb\xbf{ a }",
+	`[[[[[[[[[[`,
+	`{{{{{{{{{{`,
+}
+
+func FuzzParseQuery(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		_, _ = ParseQuery(&ast.Source{Input: input})
+		_, _ = ParseQueryWithOptions(&ast.Source{Input: input}, WithErrorRecovery(0))
+	})
+}
+
+func FuzzParseSchema(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		_, _ = ParseSchema(&ast.Source{Input: input})
+		_, _ = ParseSchemaWithOptions(&ast.Source{Input: input}, WithErrorRecovery(0), WithSyncTokens(SyncTokens{TopLevelBrace: true}))
+	})
+}
+
+func TestParseHostileInput(t *testing.T) {
+	for name, input := range map[string]string{
+		"nested selection sets": strings.Repeat("{a", 100000),
+		"nested lists":          "{ a(b: " + strings.Repeat("[", 100000) + ") }",
+		"nested objects":        "{ a(b: " + strings.Repeat("{a:", 100000) + ") }",
+		"nested list types":     "query($a: " + strings.Repeat("[", 100000) + "Int) { a }",
+		"truncated escape":      `{ a(b: "\u12`,
+		"truncated block":       `{ a(b: """`,
+		"invalid utf8":          "{ a(b: \"\xff\xfe\") \xc0 }",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseQuery(&ast.Source{Input: input})
+			require.Error(t, err)
+			require.NotContains(t, err.Error(), "internal error")
+
+			_, err = ParseQueryWithOptions(&ast.Source{Input: input}, WithErrorRecovery(0))
+			require.Error(t, err)
+			require.NotContains(t, err.Error(), "internal error")
+		})
+	}
+
+	t.Run("absurd number", func(t *testing.T) {
+		raw := strings.Repeat("9", 100000) + "e" + strings.Repeat("9", 1000)
+		doc, err := ParseQuery(&ast.Source{Input: "{ a(b: " + raw + ") }"})
+		require.NoError(t, err)
+		require.Equal(t, raw, doc.Operations[0].SelectionSet[0].(*ast.Field).Arguments[0].Value.Raw)
+	})
+
+	t.Run("nesting limit", func(t *testing.T) {
+		_, err := ParseQuery(&ast.Source{Input: strings.Repeat("{a", maxNesting+1)})
+		require.ErrorIs(t, err, gqlerror.ErrLimitExceeded)
+		require.EqualError(t, err, "input:1:2001: exceeded maximum nesting depth of 1000")
+
+		_, err = ParseQuery(&ast.Source{Input: strings.Repeat("{a", maxNesting-1) + "{a" + strings.Repeat("}", maxNesting)})
+		require.NoError(t, err)
+	})
+
+	t.Run("panics are returned as errors", func(t *testing.T) {
+		_, err := ParseQuery(nil)
+		require.ErrorIs(t, err, gqlerror.ErrInternal)
+		require.NotErrorIs(t, err, gqlerror.ErrSyntax)
+
+		_, err = ParseSchema(nil)
+		require.ErrorIs(t, err, gqlerror.ErrInternal)
+	})
+}
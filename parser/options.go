@@ -0,0 +1,185 @@
+package parser
+
+import (
+	//nolint:revive
+	. "github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// DefaultMaxErrors is the number of syntax errors collected by WithErrorRecovery before the parser
+// gives up, when no explicit cap is given.
+const DefaultMaxErrors = 50
+
+// Option configures optional parser behaviour.
+type Option func(p *parser)
+
+// WithTokenLimit stops parsing with an error once more than maxTokenLimit tokens have been read.
+// 0 means unlimited.
+func WithTokenLimit(maxTokenLimit int) Option {
+	return func(p *parser) {
+		p.maxTokenLimit = maxTokenLimit
+	}
+}
+
+// WithMaxDepth stops parsing with an error once a field is nested more than maxDepth fields deep,
+// root fields being at depth 1. 0 means unlimited.
+//
+// The depth is counted within each operation and fragment definition as it is parsed, fragment
+// spreads are not followed. Use the MaxDepth validation rule to count through fragments.
+func WithMaxDepth(maxDepth int) Option {
+	return func(p *parser) {
+		p.maxDepth = maxDepth
+	}
+}
+
+// Limits bounds the work done parsing a single document, so that hostile input is rejected before
+// any validation starts. A zero value means unlimited. Exceeding a limit stops parsing, even with
+// WithErrorRecovery, with an error that has the LIMIT_EXCEEDED code.
+type Limits struct {
+	// MaxTokens is the number of tokens read before parsing stops, see WithTokenLimit.
+	MaxTokens int
+	// MaxNodes is the number of nodes parsed before parsing stops. Nodes are definitions,
+	// variable definitions, selections, arguments, directives and values, including every item
+	// of a list or object value.
+	MaxNodes int
+	// MaxDepth is how deeply fields may be nested, see WithMaxDepth.
+	MaxDepth int
+	// MaxValueDepth is how deeply list and object values may be nested in arguments and default
+	// values, `[[1]]` being 2 deep.
+	MaxValueDepth int
+	// MaxValueNodes is the number of values a single argument or default value may hold, counting
+	// the value itself and every list item and object field within it.
+	MaxValueNodes int
+}
+
+// WithLimits applies all of limits at once, replacing any limit set by an earlier option.
+func WithLimits(limits Limits) Option {
+	return func(p *parser) {
+		p.maxTokenLimit = limits.MaxTokens
+		p.maxNodes = limits.MaxNodes
+		p.maxDepth = limits.MaxDepth
+		p.maxValueDepth = limits.MaxValueDepth
+		p.maxValueNodes = limits.MaxValueNodes
+	}
+}
+
+// WithErrorRecovery makes the parser skip ahead to the start of the next definition after a
+// syntax error instead of stopping, so that a single parse reports every broken definition.
+//
+// Parsing stops once maxErrors errors have been collected, a value <= 0 uses DefaultMaxErrors.
+// With recovery enabled the returned error is always a gqlerror.List, and the returned document
+// holds whatever could be parsed.
+func WithErrorRecovery(maxErrors int) Option {
+	return func(p *parser) {
+		if maxErrors <= 0 {
+			maxErrors = DefaultMaxErrors
+		}
+		p.recovery = true
+		p.maxErrors = maxErrors
+	}
+}
+
+// SyncTokens configures where a parser using WithErrorRecovery resumes after a syntax error.
+type SyncTokens struct {
+	// Keywords that start a new definition. A keyword directly followed by a colon is a field or
+	// argument name and never starts a definition. nil keeps the default keywords for the kind of
+	// document being parsed, an empty slice disables syncing on keywords.
+	Keywords []string
+	// TopLevelBrace resumes parsing after the } closing a top level definition.
+	TopLevelBrace bool
+	// LineStart only syncs on keywords at the start of a line, with nothing but indentation before
+	// them, for documents that are known to be formatted that way.
+	LineStart bool
+}
+
+// WithSyncTokens changes where the parser resumes after a syntax error when WithErrorRecovery is
+// used. By default parsing resumes at the next definition keyword, or in query documents at the
+// next top level {, which starts an anonymous query.
+func WithSyncTokens(tokens SyncTokens) Option {
+	return func(p *parser) {
+		if tokens.Keywords != nil {
+			p.syncKeywords = make(map[string]bool, len(tokens.Keywords))
+			for _, keyword := range tokens.Keywords {
+				p.syncKeywords[keyword] = true
+			}
+		}
+		p.syncBrace = tokens.TopLevelBrace
+		p.syncLineStart = tokens.LineStart
+	}
+}
+
+// WithGraphQLJSMessages words syntax errors the way graphql-js does, eg
+// `Syntax Error: Expected Name, found "}".` rather than `Expected Name, found }`, for users
+// migrating test suites or clients that compare error messages.
+//
+// Validation messages already follow graphql-js and are not affected.
+func WithGraphQLJSMessages() Option {
+	return func(p *parser) {
+		p.jsMessages = true
+	}
+}
+
+// ParseQueryWithOptions parses a query document, see Option for the available behaviours.
+func ParseQueryWithOptions(source *Source, options ...Option) (doc *QueryDocument, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, panicError(source, r)
+		}
+	}()
+
+	p := parser{
+		lexer:         lexer.New(source),
+		syncKeywords:  querySyncKeywords,
+		syncShorthand: true,
+	}
+	for _, o := range options {
+		o(&p)
+	}
+	return p.parseQueryDocument(), p.result()
+}
+
+// ParseSchemaWithOptions parses a schema document, see Option for the available behaviours.
+func ParseSchemaWithOptions(source *Source, options ...Option) (doc *SchemaDocument, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, panicError(source, r)
+		}
+	}()
+
+	p := parser{
+		lexer:        lexer.New(source),
+		syncKeywords: schemaSyncKeywords,
+	}
+	for _, o := range options {
+		o(&p)
+	}
+
+	doc, err = p.parseSchemaDocument(), p.result()
+	if err != nil && !p.recovery {
+		return nil, err
+	}
+
+	if doc != nil {
+		for _, def := range doc.Definitions {
+			def.BuiltIn = source.BuiltIn
+		}
+		for _, def := range doc.Extensions {
+			def.BuiltIn = source.BuiltIn
+		}
+	}
+
+	return doc, err
+}
+
+// panicError converts a panic while parsing into an error. The parser is not expected to panic on
+// any input, this is a last line of defence so that a bug can't take down a server. The error has
+// CodeInternal, so it isn't mistaken for a syntax error in the input.
+func panicError(source *Source, r interface{}) error {
+	err := gqlerror.Errorf("internal error while parsing: %v", r)
+	if source != nil {
+		err.SetFile(source.Name)
+	}
+	err.SetCode(gqlerror.CodeInternal)
+	return err
+}
@@ -0,0 +1,262 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorRecovery(t *testing.T) {
+	t.Run("collects errors from every broken query definition", func(t *testing.T) {
+		doc, err := ParseQueryWithOptions(&ast.Source{Name: "spec", Input: `
+			query A { a(x: ) }
+			query B { b }
+			fragment C on T { c: }
+			query D { d }
+		`}, WithErrorRecovery(0))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		require.Equal(t, `Unexpected )`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 19}}, errs[0].Locations)
+		require.Equal(t, `Expected Name, found }`, errs[1].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 25}}, errs[1].Locations)
+
+		require.NotNil(t, doc.Operations.ForName("B"))
+		require.NotNil(t, doc.Operations.ForName("D"))
+	})
+
+	t.Run("collects errors from every broken schema definition", func(t *testing.T) {
+		doc, err := ParseSchemaWithOptions(&ast.Source{Name: "spec", Input: `
+			type A { a: }
+			type B { type: String }
+			enum C { }
+			input D { d: Int }
+			'bad'
+		`}, WithErrorRecovery(0))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 3)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 16}}, errs[0].Locations)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 13}}, errs[1].Locations)
+		require.Equal(t, []gqlerror.Location{{Line: 6, Column: 4}}, errs[2].Locations)
+
+		require.NotNil(t, doc.Definitions.ForName("B").Fields.ForName("type"))
+		require.NotNil(t, doc.Definitions.ForName("D"))
+	})
+
+	t.Run("resumes at anonymous queries", func(t *testing.T) {
+		doc, err := ParseQueryWithOptions(&ast.Source{Input: `query A { a( } { b }`}, WithErrorRecovery(0))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Equal(t, []gqlerror.Location{{Line: 1, Column: 14}}, errs[0].Locations)
+
+		// the broken operation is kept as far as it could be parsed
+		require.Len(t, doc.Operations, 2)
+		require.Equal(t, "A", doc.Operations[0].Name)
+		require.Equal(t, "", doc.Operations[1].Name)
+		require.Equal(t, "b", doc.Operations[1].SelectionSet[0].(*ast.Field).Name)
+	})
+
+	t.Run("stops at the error cap", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `
+			query A { a( }
+			query B { b( }
+			query C { c( }
+		`}, WithErrorRecovery(2))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+	})
+
+	t.Run("does not recover from the token limit", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `query A { a( } query B { b c d e f g }`}, WithErrorRecovery(0), WithTokenLimit(10))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		require.Equal(t, "exceeded token limit of 10", errs[1].Message)
+	})
+
+	t.Run("returns no error for valid documents", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `{ a }`}, WithErrorRecovery(0))
+		require.NoError(t, err)
+	})
+}
+
+func TestSyncTokens(t *testing.T) {
+	t.Run("top level braces", func(t *testing.T) {
+		doc, err := ParseQueryWithOptions(&ast.Source{Input: `
+			{ a( }
+			{ b { c } }
+			{ d { e( } f }
+			{ g }
+		`}, WithErrorRecovery(0), WithSyncTokens(SyncTokens{TopLevelBrace: true}))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 2)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 9}}, errs[0].Locations)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 13}}, errs[1].Locations)
+
+		// broken operations are kept as far as they could be parsed
+		require.Len(t, doc.Operations, 4)
+		require.Equal(t, "b", doc.Operations[1].SelectionSet[0].(*ast.Field).Name)
+		require.Equal(t, "g", doc.Operations[3].SelectionSet[0].(*ast.Field).Name)
+	})
+
+	t.Run("custom keywords", func(t *testing.T) {
+		doc, err := ParseQueryWithOptions(&ast.Source{Input: `
+			query A { a( }
+			fragment B on T { b }
+			query C { c }
+		`}, WithErrorRecovery(0), WithSyncTokens(SyncTokens{Keywords: []string{"query"}}))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Len(t, doc.Fragments, 0)
+		require.NotNil(t, doc.Operations.ForName("C"))
+	})
+
+	t.Run("keywords at the start of a line", func(t *testing.T) {
+		doc, err := ParseSchemaWithOptions(&ast.Source{Input: "type A { a: } type B { b: Int }\ntype C { c: Int }"},
+			WithErrorRecovery(0), WithSyncTokens(SyncTokens{LineStart: true}))
+
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Nil(t, doc.Definitions.ForName("B"))
+		require.NotNil(t, doc.Definitions.ForName("C"))
+
+		doc, err = ParseSchemaWithOptions(&ast.Source{
+			Input:          "\ttype A { a: } type B { b: Int }\n\ttype C { c: Int }",
+			LocationOffset: ast.LocationOffset{Line: 10, Column: 20},
+		}, WithErrorRecovery(0), WithSyncTokens(SyncTokens{LineStart: true}))
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Nil(t, doc.Definitions.ForName("B"))
+		require.NotNil(t, doc.Definitions.ForName("C"))
+	})
+}
+
+func TestGraphQLJSMessages(t *testing.T) {
+	for input, message := range map[string]string{
+		`{`: `Syntax Error: Expected Name, found <EOF>.`,
+		`{ ...MissingOn } fragment MissingOn Type`: `Syntax Error: Expected "on", found Name "Type".`,
+		`{ field: {} }`:                  `Syntax Error: Expected Name, found "{".`,
+		`notAnOperation Foo { field }`:   `Syntax Error: Unexpected Name "notAnOperation".`,
+		`...`:                            `Syntax Error: Unexpected "...".`,
+		`{ ""`:                           `Syntax Error: Expected Name, found String "".`,
+		`query Foo($a: Int) { a(b: $) }`: `Syntax Error: Expected Name, found ")".`,
+		`{ a(b: "abc) }`:                 `Syntax Error: Unterminated string.`,
+		`{ a }}`:                         `Syntax Error: Unexpected "}".`,
+		`{ a {} }`:                       `Syntax Error: Expected Name, found "}".`,
+	} {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: input}, WithGraphQLJSMessages())
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, err, &gqlErr, input)
+		require.Equal(t, message, gqlErr.Message, input)
+	}
+
+	t.Run("token limit", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `{ a b c }`}, WithGraphQLJSMessages(), WithTokenLimit(3))
+		require.EqualError(t, err, "input:1:5: Syntax Error: Document contains more than 3 tokens. Parsing aborted.")
+	})
+
+	t.Run("messages are unchanged by default", func(t *testing.T) {
+		_, err := ParseQuery(&ast.Source{Input: `{ field: {} }`})
+		require.EqualError(t, err, "input:1:10: Expected Name, found {")
+	})
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	query := `
+		query Q { a { b { c } } }
+		fragment F on T { a { b } }
+	`
+
+	_, err := ParseQueryWithOptions(&ast.Source{Name: "spec", Input: query}, WithMaxDepth(3))
+	require.NoError(t, err)
+
+	_, err = ParseQueryWithOptions(&ast.Source{Name: "spec", Input: query}, WithMaxDepth(2))
+	require.EqualError(t, err, "spec:2:21: exceeded maximum query depth of 2")
+	require.ErrorIs(t, err, gqlerror.ErrLimitExceeded)
+
+	t.Run("inline fragments don't count", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `{ ... { ... on T { a { b } } } }`}, WithMaxDepth(2))
+		require.NoError(t, err)
+	})
+
+	t.Run("stops error recovery", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `{ a { b } } query B { c( } `}, WithMaxDepth(1), WithErrorRecovery(0))
+		var errs gqlerror.List
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs, 1)
+		require.Equal(t, "exceeded maximum query depth of 1", errs[0].Message)
+	})
+}
+
+func TestWithLimits(t *testing.T) {
+	query := `{ a(x: [1, 2]) b }`
+
+	_, err := ParseQueryWithOptions(&ast.Source{Name: "spec", Input: query}, WithLimits(Limits{MaxNodes: 7}))
+	require.NoError(t, err)
+
+	_, err = ParseQueryWithOptions(&ast.Source{Name: "spec", Input: query}, WithLimits(Limits{MaxNodes: 6}))
+	require.EqualError(t, err, "spec:1:16: exceeded node limit of 6")
+	require.ErrorIs(t, err, gqlerror.ErrLimitExceeded)
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	require.Equal(t, []gqlerror.Location{{Line: 1, Column: 16}}, gqlErr.Locations)
+
+	t.Run("tokens", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: query}, WithLimits(Limits{MaxTokens: 5}))
+		require.EqualError(t, err, "input:1:6: exceeded token limit of 5")
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: `{ a { b } }`}, WithLimits(Limits{MaxDepth: 1}))
+		require.EqualError(t, err, "input:1:7: exceeded maximum query depth of 1")
+	})
+
+	t.Run("value depth", func(t *testing.T) {
+		query := `query Q($v: In = {a: [1]}) { a(x: [[1], {b: 2}]) }`
+		_, err := ParseQueryWithOptions(&ast.Source{Input: query}, WithLimits(Limits{MaxValueDepth: 2}))
+		require.NoError(t, err)
+
+		_, err = ParseQueryWithOptions(&ast.Source{Input: `{ a(x: [[[1]]]) }`}, WithLimits(Limits{MaxValueDepth: 2}))
+		require.EqualError(t, err, "input:1:10: exceeded maximum value depth of 2")
+		require.ErrorIs(t, err, gqlerror.ErrLimitExceeded)
+
+		_, err = ParseSchemaWithOptions(&ast.Source{Input: `type Query { a(x: [[Int]] = [[1]]): Int }`}, WithLimits(Limits{MaxValueDepth: 1}))
+		require.EqualError(t, err, "input:1:30: exceeded maximum value depth of 1")
+	})
+
+	t.Run("value nodes", func(t *testing.T) {
+		query := `{ a(x: [1, 2], y: {b: [3]}) }`
+		_, err := ParseQueryWithOptions(&ast.Source{Input: query}, WithLimits(Limits{MaxValueNodes: 3}))
+		require.NoError(t, err)
+
+		_, err = ParseQueryWithOptions(&ast.Source{Input: `{ a(x: [1, 2, 3]) }`}, WithLimits(Limits{MaxValueNodes: 3}))
+		require.EqualError(t, err, "input:1:15: exceeded maximum of 3 nodes in a value")
+	})
+
+	t.Run("schema nodes", func(t *testing.T) {
+		_, err := ParseSchemaWithOptions(&ast.Source{Input: `type Query { a: Int b(x: Int): Int }`}, WithLimits(Limits{MaxNodes: 3}))
+		require.EqualError(t, err, "input:1:23: exceeded node limit of 3")
+	})
+
+	t.Run("replaces earlier options", func(t *testing.T) {
+		_, err := ParseQueryWithOptions(&ast.Source{Input: query}, WithTokenLimit(5), WithLimits(Limits{}))
+		require.NoError(t, err)
+	})
+}
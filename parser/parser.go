@@ -24,6 +24,57 @@ type parser struct {
 
 	tokenCount    int
 	maxTokenLimit int
+	nodeCount     int
+	maxNodes      int
+
+	recovery      bool
+	maxErrors     int
+	errs          gqlerror.List
+	syncKeywords  map[string]bool
+	syncBrace     bool
+	syncLineStart bool
+	// syncShorthand syncs on a top level {, which starts an anonymous query
+	syncShorthand bool
+	jsMessages    bool
+
+	// depth is the number of unclosed braces read from the lexer so far
+	depth int
+	// nesting is the number of brackets the parser is currently inside of
+	nesting int
+	// fieldDepth is the number of fields whose selection set the parser is currently inside of
+	fieldDepth int
+	maxDepth   int
+	// valueDepth is the number of list and object values the parser is currently inside of, and
+	// valueNodes the number of values parsed so far within the outermost one
+	valueDepth    int
+	maxValueDepth int
+	valueNodes    int
+	maxValueNodes int
+}
+
+// maxNesting bounds how deeply brackets can be nested, so that hostile documents are rejected
+// with an error instead of exhausting the stack.
+const maxNesting = 1000
+
+// querySyncKeywords are the keywords that start a definition in a query document.
+var querySyncKeywords = map[string]bool{
+	"query":        true,
+	"mutation":     true,
+	"subscription": true,
+	"fragment":     true,
+}
+
+// schemaSyncKeywords are the keywords that start a definition in a schema document.
+var schemaSyncKeywords = map[string]bool{
+	"schema":    true,
+	"scalar":    true,
+	"type":      true,
+	"interface": true,
+	"union":     true,
+	"enum":      true,
+	"input":     true,
+	"directive": true,
+	"extend":    true,
 }
 
 func (p *parser) SetMaxTokenLimit(maxToken int) {
@@ -82,7 +133,7 @@ func (p *parser) peek() lexer.Token {
 	}
 
 	if !p.peeked {
-		p.peekToken, p.peekError = p.lexer.ReadToken()
+		p.peekToken, p.peekError = p.readToken()
 		p.peeked = true
 		if p.peekToken.Kind == lexer.Comment {
 			p.consumeCommentGroup()
@@ -96,7 +147,139 @@ func (p *parser) error(tok lexer.Token, format string, args ...interface{}) {
 	if p.err != nil {
 		return
 	}
-	p.err = gqlerror.ErrorLocf(tok.Pos.Src.Name, tok.Pos.Line, tok.Pos.Column, format, args...)
+	// graphql-js reports the lexer error for an invalid token rather than the token itself
+	if p.jsMessages && tok.Kind == lexer.Invalid && p.peeked && p.peekError != nil {
+		p.err = p.peekError
+		return
+	}
+	err := gqlerror.ErrorLocf(tok.Pos.Src.Name, tok.Pos.Line, tok.Pos.Column, format, args...)
+	err.SetCode(gqlerror.CodeParseFailed)
+	if p.jsMessages {
+		jsSyntaxError(err)
+	}
+	p.err = err
+}
+
+// tokenLimitExceeded reports whether more tokens have been read than the parser allows.
+func (p *parser) tokenLimitExceeded() bool {
+	return p.maxTokenLimit != 0 && p.tokenCount > p.maxTokenLimit
+}
+
+func (p *parser) tokenLimitError() *gqlerror.Error {
+	err := gqlerror.ErrorLocf(p.lexer.Name, p.prev.Pos.Line, p.prev.Pos.Column, "exceeded token limit of %d", p.maxTokenLimit)
+	if p.jsMessages {
+		err.Message = fmt.Sprintf("Syntax Error: Document contains more than %d tokens. Parsing aborted.", p.maxTokenLimit)
+	}
+	err.SetCode(gqlerror.CodeLimitExceeded)
+	return err
+}
+
+// recover records the pending error and discards tokens up to the start of the next definition
+// so parsing can carry on. It returns false when parsing should stop instead, because recovery is
+// disabled, the error cap has been reached or the error was a limit rather than a syntax error.
+func (p *parser) recover() bool {
+	if !p.recovery {
+		return false
+	}
+	err := gqlerror.WrapIfUnwrapped(p.err)
+	p.errs = append(p.errs, err)
+	if len(p.errs) >= p.maxErrors || err.Code() == gqlerror.CodeLimitExceeded {
+		return false
+	}
+	p.err = nil
+	p.comment = nil
+
+	for {
+		var tok lexer.Token
+		var err error
+		if p.peeked {
+			tok, err = p.peekToken, p.peekError
+			p.peeked = false
+		} else {
+			p.tokenCount++
+			if p.tokenLimitExceeded() {
+				err := p.tokenLimitError()
+				p.err = err
+				p.errs = append(p.errs, err)
+				return false
+			}
+			tok, err = p.readToken()
+		}
+
+		// errors from the lexer while skipping are almost always fallout from the original error,
+		// reporting them would only add noise.
+		if err != nil || tok.Kind == lexer.Comment {
+			continue
+		}
+
+		if tok.Kind == lexer.EOF || p.isSyncToken(tok) {
+			p.peekToken, p.peekError = tok, nil
+			p.peeked = true
+			return true
+		}
+
+		// the brace closing a top level definition is consumed, parsing resumes with whatever
+		// follows it.
+		if p.syncBrace && tok.Kind == lexer.BraceR && p.depth == 0 {
+			return true
+		}
+	}
+}
+
+// readToken reads the next token from the lexer, keeping track of brace depth.
+func (p *parser) readToken() (lexer.Token, error) {
+	tok, err := p.lexer.ReadToken()
+	if err != nil && p.jsMessages {
+		if gqlErr, ok := err.(*gqlerror.Error); ok {
+			jsSyntaxError(gqlErr)
+		}
+	}
+	switch tok.Kind {
+	case lexer.BraceL:
+		p.depth++
+	case lexer.BraceR:
+		if p.depth > 0 {
+			p.depth--
+		}
+	}
+	return tok, err
+}
+
+// isSyncToken reports whether tok looks like the start of a new definition. Definition keywords
+// are also valid field and argument names, so a keyword directly followed by a colon is not
+// treated as a definition.
+func (p *parser) isSyncToken(tok lexer.Token) bool {
+	if p.syncShorthand && tok.Kind == lexer.BraceL && p.depth == 1 {
+		// the brace has been counted already, so it is top level when the depth is one
+		return !p.syncLineStart || p.lexer.AtLineStart()
+	}
+	if tok.Kind != lexer.Name || !p.syncKeywords[tok.Value] {
+		return false
+	}
+	// tok is always the last token read, the lexer knows what precedes it on its line
+	if p.syncLineStart && !p.lexer.AtLineStart() {
+		return false
+	}
+
+	// the lexer is a value type, so a copy can be used to look ahead without consuming anything.
+	lookahead := p.lexer
+	next, _ := lookahead.ReadToken()
+	for next.Kind == lexer.Comment {
+		next, _ = lookahead.ReadToken()
+	}
+	return next.Kind != lexer.Colon
+}
+
+// result returns the error to report once parsing has finished. With error recovery enabled this
+// is every error collected, otherwise the first error encountered.
+func (p *parser) result() error {
+	if !p.recovery {
+		return p.err
+	}
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
 }
 
 func (p *parser) next() lexer.Token {
@@ -105,8 +288,8 @@ func (p *parser) next() lexer.Token {
 	}
 	// Increment the token count before reading the next token
 	p.tokenCount++
-	if p.maxTokenLimit != 0 && p.tokenCount > p.maxTokenLimit {
-		p.err = fmt.Errorf("exceeded token limit of %d", p.maxTokenLimit)
+	if p.tokenLimitExceeded() {
+		p.err = p.tokenLimitError()
 		return p.prev
 	}
 	if p.peeked {
@@ -114,7 +297,7 @@ func (p *parser) next() lexer.Token {
 		p.comment = nil
 		p.prev, p.err = p.peekToken, p.peekError
 	} else {
-		p.prev, p.err = p.lexer.ReadToken()
+		p.prev, p.err = p.readToken()
 		if p.prev.Kind == lexer.Comment {
 			p.consumeCommentGroup()
 		}
@@ -129,7 +312,7 @@ func (p *parser) expectKeyword(value string) (lexer.Token, *ast.CommentGroup) {
 		return p.next(), comment
 	}
 
-	p.error(tok, "Expected %s, found %s", strconv.Quote(value), tok.String())
+	p.error(tok, "Expected %s, found %s", strconv.Quote(value), p.describeToken(tok))
 	return tok, comment
 }
 
@@ -140,7 +323,7 @@ func (p *parser) expect(kind lexer.Type) (lexer.Token, *ast.CommentGroup) {
 		return p.next(), comment
 	}
 
-	p.error(tok, "Expected %s, found %s", kind, tok.Kind.String())
+	p.error(tok, "Expected %s, found %s", p.describeKind(kind), p.describeFound(tok))
 	return tok, comment
 }
 
@@ -163,7 +346,57 @@ func (p *parser) unexpectedError() {
 }
 
 func (p *parser) unexpectedToken(tok lexer.Token) {
-	p.error(tok, "Unexpected %s", tok.String())
+	p.error(tok, "Unexpected %s", p.describeToken(tok))
+}
+
+// unsupportedError reports the next token as unexpected, with CodeUnsupportedFeature as it starts
+// a valid definition that doesn't belong in the kind of document being parsed.
+func (p *parser) unsupportedError() {
+	if p.err != nil {
+		return
+	}
+	p.unexpectedError()
+	if err, ok := p.err.(*gqlerror.Error); ok {
+		err.SetCode(gqlerror.CodeUnsupportedFeature)
+	}
+}
+
+// limitError stops parsing with an error reporting that a limit has been exceeded.
+func (p *parser) limitError(pos *ast.Position, format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	err := gqlerror.ErrorPosf(pos, format, args...)
+	err.SetCode(gqlerror.CodeLimitExceeded)
+	p.err = err
+}
+
+// countNode is called before parsing a node, it stops parsing with an error once more nodes have
+// been parsed than the parser allows.
+func (p *parser) countNode() {
+	p.nodeCount++
+	if p.maxNodes > 0 && p.nodeCount > p.maxNodes {
+		p.limitError(p.peekPos(), "exceeded node limit of %d", p.maxNodes)
+	}
+}
+
+// enter is called after reading an opening bracket, it reports an error and returns false when
+// brackets are nested too deeply. leave must be called once the bracket is closed either way.
+func (p *parser) enter() bool {
+	p.nesting++
+	if p.nesting > maxNesting {
+		err := gqlerror.ErrorLocf(p.lexer.Name, p.prev.Pos.Line, p.prev.Pos.Column, "exceeded maximum nesting depth of %d", maxNesting)
+		err.SetCode(gqlerror.CodeLimitExceeded)
+		if p.err == nil {
+			p.err = err
+		}
+		return false
+	}
+	return true
+}
+
+func (p *parser) leave() {
+	p.nesting--
 }
 
 func (p *parser) many(start lexer.Type, end lexer.Type, cb func()) {
@@ -171,6 +404,10 @@ func (p *parser) many(start lexer.Type, end lexer.Type, cb func()) {
 	if !hasDef {
 		return
 	}
+	defer p.leave()
+	if !p.enter() {
+		return
+	}
 
 	for p.peek().Kind != end && p.err == nil {
 		cb()
@@ -183,6 +420,10 @@ func (p *parser) some(start lexer.Type, end lexer.Type, cb func()) *ast.CommentG
 	if !hasDef {
 		return nil
 	}
+	defer p.leave()
+	if !p.enter() {
+		return nil
+	}
 
 	called := false
 	for p.peek().Kind != end && p.err == nil {
@@ -191,7 +432,12 @@ func (p *parser) some(start lexer.Type, end lexer.Type, cb func()) *ast.CommentG
 	}
 
 	if !called {
-		p.error(p.peek(), "expected at least one definition, found %s", p.peek().Kind.String())
+		if p.jsMessages {
+			// graphql-js parses the first item unconditionally, which always starts with a name
+			p.error(p.peek(), "Expected Name, found %s", p.describeFound(p.peek()))
+		} else {
+			p.error(p.peek(), "expected at least one definition, found %s", p.peek().Kind.String())
+		}
 		return nil
 	}
 
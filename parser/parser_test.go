@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/lexer"
 )
 
@@ -67,7 +68,7 @@ func TestParserUtils(t *testing.T) {
 					p.error(p.peek(), "boom")
 				}
 			})
-			require.EqualError(t, p.err, "input.graphql:1: boom")
+			require.EqualError(t, p.err, "input.graphql:1:6: boom")
 			require.Equal(t, []string{"a", "b"}, arr)
 		})
 	})
@@ -94,7 +95,7 @@ func TestParserUtils(t *testing.T) {
 			p.some(lexer.BracketL, lexer.BracketR, func() {
 				arr = append(arr, p.next().Value)
 			})
-			require.EqualError(t, p.err, "input.graphql:1: expected at least one definition, found ]")
+			require.EqualError(t, p.err, "input.graphql:1:2: expected at least one definition, found ]")
 			require.Equal(t, []string(nil), arr)
 			require.NotEqual(t, lexer.EOF, p.peek().Kind)
 		})
@@ -119,7 +120,7 @@ func TestParserUtils(t *testing.T) {
 					p.error(p.peek(), "boom")
 				}
 			})
-			require.EqualError(t, p.err, "input.graphql:1: boom")
+			require.EqualError(t, p.err, "input.graphql:1:6: boom")
 			require.Equal(t, []string{"a", "b"}, arr)
 		})
 	})
@@ -131,7 +132,7 @@ func TestParserUtils(t *testing.T) {
 		p.error(p.peek(), "test error")
 		p.error(p.peek(), "secondary error")
 
-		require.EqualError(t, p.err, "input.graphql:1: test error")
+		require.EqualError(t, p.err, "input.graphql:1:5: test error")
 
 		require.Equal(t, "foo", p.peek().Value)
 		require.Equal(t, "foo", p.next().Value)
@@ -141,27 +142,42 @@ func TestParserUtils(t *testing.T) {
 	t.Run("unexpected error", func(t *testing.T) {
 		p := newParser("1 3")
 		p.unexpectedError()
-		require.EqualError(t, p.err, "input.graphql:1: Unexpected Int \"1\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Unexpected Int \"1\"")
 	})
 
 	t.Run("unexpected error", func(t *testing.T) {
 		p := newParser("1 3")
 		p.unexpectedToken(p.next())
-		require.EqualError(t, p.err, "input.graphql:1: Unexpected Int \"1\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Unexpected Int \"1\"")
 	})
 
 	t.Run("expect error", func(t *testing.T) {
 		p := newParser("foo bar")
 		p.expect(lexer.Float)
 
-		require.EqualError(t, p.err, "input.graphql:1: Expected Float, found Name")
+		require.EqualError(t, p.err, "input.graphql:1:1: Expected Float, found Name")
+	})
+
+	t.Run("token limit error", func(t *testing.T) {
+		p := newParser("foo bar baz")
+		p.SetMaxTokenLimit(2)
+		p.next()
+		p.next()
+		p.next()
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, p.err, &gqlErr)
+		require.EqualError(t, p.err, "input.graphql:1:5: exceeded token limit of 2")
+		require.Equal(t, []gqlerror.Location{{Line: 1, Column: 5}}, gqlErr.Locations)
+		require.ErrorIs(t, p.err, gqlerror.ErrLimitExceeded)
+		require.NotErrorIs(t, p.err, gqlerror.ErrSyntax)
 	})
 
 	t.Run("expectKeyword error", func(t *testing.T) {
 		p := newParser("foo bar")
 		p.expectKeyword("baz")
 
-		require.EqualError(t, p.err, "input.graphql:1: Expected \"baz\", found Name \"foo\"")
+		require.EqualError(t, p.err, "input.graphql:1:1: Expected \"baz\", found Name \"foo\"")
 	})
 }
 
@@ -171,3 +187,18 @@ func newParser(input string) parser {
 		maxTokenLimit: 15000, // 15000 is the default value
 	}
 }
+
+func TestUnsupportedDefinitions(t *testing.T) {
+	_, err := ParseQuery(&ast.Source{Name: "query.graphql", Input: "{ a }\ntype Query { a: Int }"})
+	require.EqualError(t, err, `query.graphql:2:1: Unexpected Name "type"`)
+	require.ErrorIs(t, err, gqlerror.ErrUnsupportedFeature)
+	require.NotErrorIs(t, err, gqlerror.ErrSyntax)
+
+	_, err = ParseSchema(&ast.Source{Name: "schema.graphql", Input: "type Query { a: Int }\nfragment F on Query { a }"})
+	require.EqualError(t, err, `schema.graphql:2:1: Unexpected Name "fragment"`)
+	require.ErrorIs(t, err, gqlerror.ErrUnsupportedFeature)
+
+	_, err = ParseQuery(&ast.Source{Name: "query.graphql", Input: "{ a }\nfoo"})
+	require.ErrorIs(t, err, gqlerror.ErrSyntax)
+	require.NotErrorIs(t, err, gqlerror.ErrUnsupportedFeature)
+}
@@ -7,26 +7,21 @@ import (
 )
 
 func ParseQuery(source *Source) (*QueryDocument, error) {
-	p := parser{
-		lexer:         lexer.New(source),
-		maxTokenLimit: 0, // 0 means unlimited
-	}
-	return p.parseQueryDocument(), p.err
+	return ParseQueryWithOptions(source)
 }
 
 func ParseQueryWithTokenLimit(source *Source, maxTokenLimit int) (*QueryDocument, error) {
-	p := parser{
-		lexer:         lexer.New(source),
-		maxTokenLimit: maxTokenLimit,
-	}
-	return p.parseQueryDocument(), p.err
+	return ParseQueryWithOptions(source, WithTokenLimit(maxTokenLimit))
 }
 
 func (p *parser) parseQueryDocument() *QueryDocument {
 	var doc QueryDocument
-	for p.peek().Kind != lexer.EOF {
+	for p.peek().Kind != lexer.EOF || p.err != nil {
 		if p.err != nil {
-			return &doc
+			if !p.recover() {
+				return &doc
+			}
+			continue
 		}
 		doc.Position = p.peekPos()
 		switch p.peek().Kind {
@@ -36,6 +31,8 @@ func (p *parser) parseQueryDocument() *QueryDocument {
 				doc.Operations = append(doc.Operations, p.parseOperationDefinition())
 			case "fragment":
 				doc.Fragments = append(doc.Fragments, p.parseFragmentDefinition())
+			case "schema", "scalar", "type", "interface", "union", "enum", "input", "directive", "extend":
+				p.unsupportedError()
 			default:
 				p.unexpectedError()
 			}
@@ -50,6 +47,8 @@ func (p *parser) parseQueryDocument() *QueryDocument {
 }
 
 func (p *parser) parseOperationDefinition() *OperationDefinition {
+	p.countNode()
+
 	if p.peek().Kind == lexer.BraceL {
 		return &OperationDefinition{
 			Position:     p.peekPos(),
@@ -99,6 +98,8 @@ func (p *parser) parseVariableDefinitions() VariableDefinitionList {
 }
 
 func (p *parser) parseVariableDefinition() *VariableDefinition {
+	p.countNode()
+
 	var def VariableDefinition
 	def.Position = p.peekPos()
 	def.Comment = p.comment
@@ -133,7 +134,7 @@ func (p *parser) parseOptionalSelectionSet() SelectionSet {
 
 func (p *parser) parseRequiredSelectionSet() SelectionSet {
 	if p.peek().Kind != lexer.BraceL {
-		p.error(p.peek(), "Expected %s, found %s", lexer.BraceL, p.peek().Kind.String())
+		p.error(p.peek(), "Expected %s, found %s", p.describeKind(lexer.BraceL), p.describeFound(p.peek()))
 		return nil
 	}
 
@@ -146,6 +147,8 @@ func (p *parser) parseRequiredSelectionSet() SelectionSet {
 }
 
 func (p *parser) parseSelection() Selection {
+	p.countNode()
+
 	if p.peek().Kind == lexer.Spread {
 		return p.parseFragment()
 	}
@@ -164,10 +167,17 @@ func (p *parser) parseField() *Field {
 		field.Name = field.Alias
 	}
 
+	if p.maxDepth > 0 && p.fieldDepth >= p.maxDepth {
+		p.limitError(field.Position, "exceeded maximum query depth of %d", p.maxDepth)
+		return &field
+	}
+
 	field.Arguments = p.parseArguments(false)
 	field.Directives = p.parseDirectives(false)
 	if p.peek().Kind == lexer.BraceL {
+		p.fieldDepth++
 		field.SelectionSet = p.parseOptionalSelectionSet()
+		p.fieldDepth--
 	}
 
 	return &field
@@ -183,6 +193,8 @@ func (p *parser) parseArguments(isConst bool) ArgumentList {
 }
 
 func (p *parser) parseArgument(isConst bool) *Argument {
+	p.countNode()
+
 	arg := Argument{}
 	arg.Position = p.peekPos()
 	arg.Comment = p.comment
@@ -220,6 +232,8 @@ func (p *parser) parseFragment() Selection {
 }
 
 func (p *parser) parseFragmentDefinition() *FragmentDefinition {
+	p.countNode()
+
 	var def FragmentDefinition
 	def.Position = p.peekPos()
 	def.Comment = p.comment
@@ -246,6 +260,17 @@ func (p *parser) parseFragmentName() string {
 }
 
 func (p *parser) parseValueLiteral(isConst bool) *Value {
+	p.countNode()
+
+	if p.valueDepth == 0 {
+		p.valueNodes = 0
+	}
+	p.valueNodes++
+	if p.maxValueNodes > 0 && p.valueNodes > p.maxValueNodes {
+		p.limitError(p.peekPos(), "exceeded maximum of %d nodes in a value", p.maxValueNodes)
+		return nil
+	}
+
 	token := p.peek()
 
 	var kind ValueKind
@@ -291,9 +316,11 @@ func (p *parser) parseList(isConst bool) *Value {
 	var values ChildValueList
 	pos := p.peekPos()
 	comment := p.comment
+	p.enterValue(pos)
 	p.many(lexer.BracketL, lexer.BracketR, func() {
 		values = append(values, &ChildValue{Value: p.parseValueLiteral(isConst)})
 	})
+	p.valueDepth--
 
 	return &Value{Children: values, Kind: ListValue, Position: pos, Comment: comment}
 }
@@ -302,13 +329,25 @@ func (p *parser) parseObject(isConst bool) *Value {
 	var fields ChildValueList
 	pos := p.peekPos()
 	comment := p.comment
+	p.enterValue(pos)
 	p.many(lexer.BraceL, lexer.BraceR, func() {
 		fields = append(fields, p.parseObjectField(isConst))
 	})
+	p.valueDepth--
 
 	return &Value{Children: fields, Kind: ObjectValue, Position: pos, Comment: comment}
 }
 
+// enterValue is called before parsing a list or object value at pos, it stops parsing with an
+// error when values are nested too deeply. valueDepth must be decremented once the value has been
+// parsed either way.
+func (p *parser) enterValue(pos *Position) {
+	p.valueDepth++
+	if p.maxValueDepth > 0 && p.valueDepth > p.maxValueDepth {
+		p.limitError(pos, "exceeded maximum value depth of %d", p.maxValueDepth)
+	}
+}
+
 func (p *parser) parseObjectField(isConst bool) *ChildValue {
 	field := ChildValue{}
 	field.Position = p.peekPos()
@@ -334,6 +373,8 @@ func (p *parser) parseDirectives(isConst bool) []*Directive {
 }
 
 func (p *parser) parseDirective(isConst bool) *Directive {
+	p.countNode()
+
 	p.expect(lexer.At)
 
 	return &Directive{
@@ -347,6 +388,11 @@ func (p *parser) parseTypeReference() *Type {
 	var typ Type
 
 	if p.skip(lexer.BracketL) {
+		defer p.leave()
+		if !p.enter() {
+			typ.Elem = &Type{}
+			return &typ
+		}
 		typ.Position = p.peekPos()
 		typ.Elem = p.parseTypeReference()
 		p.expect(lexer.BracketR)
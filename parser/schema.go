@@ -19,23 +19,7 @@ func ParseSchemas(inputs ...*Source) (*SchemaDocument, error) {
 }
 
 func ParseSchema(source *Source) (*SchemaDocument, error) {
-	p := parser{
-		lexer:         lexer.New(source),
-		maxTokenLimit: 0, // default value is unlimited
-	}
-	sd, err := p.parseSchemaDocument(), p.err
-	if err != nil {
-		return nil, err
-	}
-
-	for _, def := range sd.Definitions {
-		def.BuiltIn = source.BuiltIn
-	}
-	for _, def := range sd.Extensions {
-		def.BuiltIn = source.BuiltIn
-	}
-
-	return sd, nil
+	return ParseSchemaWithOptions(source)
 }
 
 func ParseSchemasWithLimit(maxTokenLimit int, inputs ...*Source) (*SchemaDocument, error) {
@@ -51,31 +35,18 @@ func ParseSchemasWithLimit(maxTokenLimit int, inputs ...*Source) (*SchemaDocumen
 }
 
 func ParseSchemaWithLimit(source *Source, maxTokenLimit int) (*SchemaDocument, error) {
-	p := parser{
-		lexer:         lexer.New(source),
-		maxTokenLimit: maxTokenLimit, // 0 is unlimited
-	}
-	sd, err := p.parseSchemaDocument(), p.err
-	if err != nil {
-		return nil, err
-	}
-
-	for _, def := range sd.Definitions {
-		def.BuiltIn = source.BuiltIn
-	}
-	for _, def := range sd.Extensions {
-		def.BuiltIn = source.BuiltIn
-	}
-
-	return sd, nil
+	return ParseSchemaWithOptions(source, WithTokenLimit(maxTokenLimit))
 }
 
 func (p *parser) parseSchemaDocument() *SchemaDocument {
 	var doc SchemaDocument
 	doc.Position = p.peekPos()
-	for p.peek().Kind != lexer.EOF {
+	for p.peek().Kind != lexer.EOF || p.err != nil {
 		if p.err != nil {
-			return nil
+			if !p.recover() {
+				break
+			}
+			continue
 		}
 
 		var description descriptionWithComment
@@ -85,7 +56,7 @@ func (p *parser) parseSchemaDocument() *SchemaDocument {
 
 		if p.peek().Kind != lexer.Name {
 			p.unexpectedError()
-			break
+			continue
 		}
 
 		switch p.peek().Value {
@@ -100,9 +71,10 @@ func (p *parser) parseSchemaDocument() *SchemaDocument {
 				p.unexpectedToken(p.prev)
 			}
 			p.parseTypeSystemExtension(&doc)
+		case "query", "mutation", "subscription", "fragment":
+			p.unsupportedError()
 		default:
 			p.unexpectedError()
-			return nil
 		}
 	}
 
@@ -126,6 +98,8 @@ func (p *parser) parseDescription() descriptionWithComment {
 }
 
 func (p *parser) parseTypeSystemDefinition(description descriptionWithComment) *Definition {
+	p.countNode()
+
 	tok := p.peek()
 	if tok.Kind != lexer.Name {
 		p.unexpectedError()
@@ -152,6 +126,8 @@ func (p *parser) parseTypeSystemDefinition(description descriptionWithComment) *
 }
 
 func (p *parser) parseSchemaDefinition(description descriptionWithComment) *SchemaDefinition {
+	p.countNode()
+
 	_, comment := p.expectKeyword("schema")
 
 	def := SchemaDefinition{}
@@ -231,6 +207,8 @@ func (p *parser) parseFieldsDefinition() (FieldList, *CommentGroup) {
 }
 
 func (p *parser) parseFieldDefinition() *FieldDefinition {
+	p.countNode()
+
 	var def FieldDefinition
 	def.Position = p.peekPos()
 
@@ -260,6 +238,8 @@ func (p *parser) parseArgumentDefs() ArgumentDefinitionList {
 }
 
 func (p *parser) parseArgumentDef() *ArgumentDefinition {
+	p.countNode()
+
 	var def ArgumentDefinition
 	def.Position = p.peekPos()
 
@@ -282,6 +262,8 @@ func (p *parser) parseArgumentDef() *ArgumentDefinition {
 }
 
 func (p *parser) parseInputValueDef() *FieldDefinition {
+	p.countNode()
+
 	var def FieldDefinition
 	def.Position = p.peekPos()
 
@@ -372,6 +354,8 @@ func (p *parser) parseEnumValuesDefinition() (EnumValueList, *CommentGroup) {
 }
 
 func (p *parser) parseEnumValueDefinition() *EnumValueDefinition {
+	p.countNode()
+
 	var def EnumValueDefinition
 	def.Position = p.peekPos()
 	desc := p.parseDescription()
@@ -413,6 +397,8 @@ func (p *parser) parseInputFieldsDefinition() (FieldList, *CommentGroup) {
 }
 
 func (p *parser) parseTypeSystemExtension(doc *SchemaDocument) {
+	p.countNode()
+
 	_, comment := p.expectKeyword("extend")
 
 	switch p.peek().Value {
@@ -549,6 +535,8 @@ func (p *parser) parseInputObjectTypeExtension(comment *CommentGroup) *Definitio
 }
 
 func (p *parser) parseDirectiveDefinition(description descriptionWithComment) *DirectiveDefinition {
+	p.countNode()
+
 	_, comment := p.expectKeyword("directive")
 	p.expect(lexer.At)
 
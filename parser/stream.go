@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+
+	//nolint:revive
+	. "github.com/vektah/gqlparser/v2/ast"
+)
+
+// Framing describes how documents are delimited within a stream read by a QueryStream.
+type Framing int
+
+const (
+	// NewlineDelimited streams hold one document per line. Blank lines are skipped.
+	NewlineDelimited Framing = iota
+	// LengthDelimited streams prefix every document with its length in bytes, encoded as a 4 byte
+	// big endian integer.
+	LengthDelimited
+)
+
+// ErrShortFrame is returned by QueryStream.Err when a length delimited stream ends part way through
+// a document.
+var ErrShortFrame = errors.New("unexpected EOF in length delimited document")
+
+// QueryStream parses a sequence of query documents from a reader, such as a query log.
+//
+// The read buffer and the parser state are reused between documents, so processing a stream
+// costs little more than the allocations needed for the resulting ASTs.
+//
+//	stream := parser.NewQueryStream(r, "queries.log", parser.NewlineDelimited)
+//	for stream.Next() {
+//		doc, err := stream.Document()
+//		...
+//	}
+//	if err := stream.Err(); err != nil {
+//		...
+//	}
+type QueryStream struct {
+	scanner *bufio.Scanner
+	name    string
+	framing Framing
+	p       parser
+
+	index int
+	doc   *QueryDocument
+	err   error
+}
+
+// NewQueryStream creates a QueryStream reading documents from r. name is used to build the
+// source name of every document, which is name followed by the documents index in the stream,
+// eg "queries.log[12]".
+func NewQueryStream(r io.Reader, name string, framing Framing) *QueryStream {
+	s := &QueryStream{
+		scanner: bufio.NewScanner(r),
+		name:    name,
+		framing: framing,
+		index:   -1,
+	}
+	if framing == LengthDelimited {
+		s.scanner.Split(scanLengthDelimited)
+	}
+	return s
+}
+
+// Buffer sets the initial buffer and the maximum document size of the stream, see
+// bufio.Scanner.Buffer. It must be called before the first call to Next.
+func (s *QueryStream) Buffer(buf []byte, max int) {
+	s.scanner.Buffer(buf, max)
+}
+
+// Next parses the next document in the stream. It returns false once the stream is exhausted or
+// a read error occurs, which is then available from Err.
+func (s *QueryStream) Next() bool {
+	for s.scanner.Scan() {
+		s.index++
+		input := s.scanner.Bytes()
+		if s.framing == NewlineDelimited && isBlank(input) {
+			continue
+		}
+
+		src := &Source{
+			Name:  s.name + "[" + strconv.Itoa(s.index) + "]",
+			Input: string(input),
+		}
+		s.doc, s.err = s.parse(src)
+		return true
+	}
+
+	s.doc, s.err = nil, nil
+	return false
+}
+
+func (s *QueryStream) parse(src *Source) (doc *QueryDocument, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, panicError(src, r)
+		}
+	}()
+
+	s.p = parser{lexer: s.p.lexer}
+	s.p.lexer.Reset(src)
+	return s.p.parseQueryDocument(), s.p.err
+}
+
+// Document returns the document parsed by the last call to Next, along with any syntax error it
+// contained.
+func (s *QueryStream) Document() (*QueryDocument, error) {
+	return s.doc, s.err
+}
+
+// Index returns the position of the current document in the stream, starting at 0. Skipped
+// blank lines are counted so the index is the line number minus one in newline delimited streams.
+func (s *QueryStream) Index() int {
+	return s.index
+}
+
+// Err returns the first error encountered while reading from the stream. Syntax errors in
+// individual documents are reported by Document instead.
+func (s *QueryStream) Err() error {
+	return s.scanner.Err()
+}
+
+func isBlank(b []byte) bool {
+	for _, c := range b {
+		if c != ' ' && c != '\t' && c != '\r' && c != ',' {
+			return false
+		}
+	}
+	return true
+}
+
+// scanLengthDelimited is a bufio.SplitFunc for LengthDelimited streams.
+func scanLengthDelimited(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if len(data) >= 4 {
+		n := int(binary.BigEndian.Uint32(data))
+		if len(data) >= 4+n {
+			return 4 + n, data[4 : 4+n], nil
+		}
+	}
+	if atEOF {
+		return 0, nil, ErrShortFrame
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestQueryStream(t *testing.T) {
+	t.Run("newline delimited", func(t *testing.T) {
+		input := "query A { a }\n\n{ b(arg: \"x\\ny\") }\nquery C {\n"
+		stream := NewQueryStream(strings.NewReader(input), "log", NewlineDelimited)
+
+		require.True(t, stream.Next())
+		doc, err := stream.Document()
+		require.NoError(t, err)
+		require.Equal(t, "A", doc.Operations[0].Name)
+		require.Equal(t, 0, stream.Index())
+
+		require.True(t, stream.Next())
+		doc, err = stream.Document()
+		require.NoError(t, err)
+		require.Equal(t, "x\ny", doc.Operations[0].SelectionSet[0].(*ast.Field).Arguments[0].Value.Raw)
+		require.Equal(t, 2, stream.Index())
+
+		require.True(t, stream.Next())
+		_, err = stream.Document()
+		require.EqualError(t, err, "log[3]:1:10: Expected Name, found <EOF>")
+
+		require.False(t, stream.Next())
+		require.NoError(t, stream.Err())
+	})
+
+	t.Run("length delimited", func(t *testing.T) {
+		var buf bytes.Buffer
+		for _, q := range []string{"{ a }", "query B\n{\n b\n}"} {
+			var size [4]byte
+			binary.BigEndian.PutUint32(size[:], uint32(len(q)))
+			buf.Write(size[:])
+			buf.WriteString(q)
+		}
+		buf.Write([]byte{0, 0, 0, 10, '{'})
+
+		stream := NewQueryStream(&buf, "log", LengthDelimited)
+
+		var names []string
+		for stream.Next() {
+			doc, err := stream.Document()
+			require.NoError(t, err)
+			names = append(names, doc.Operations[0].Name)
+		}
+		require.Equal(t, []string{"", "B"}, names)
+		require.ErrorIs(t, stream.Err(), ErrShortFrame)
+	})
+}
+
+func BenchmarkQueryStream(b *testing.B) {
+	line := `query Q($id: ID!) { user(id: $id) { name friends(first: 10, after: "cursor!") { edges { node { name } } } } }` + "\n"
+	input := strings.Repeat(line, 1000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		stream := NewQueryStream(strings.NewReader(input), "bench", NewlineDelimited)
+		for stream.Next() {
+			if _, err := stream.Document(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package persisted
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// LoadManifest parses the operation files in fsys matching one of patterns, validates them
+// against schema and returns their manifest. It is meant to run at build time, for example from
+// a program started by go:generate that writes the result with WriteGo, so invalid operations
+// fail the build.
+//
+// patterns use the syntax described on gqlparser.LoadSchemaFS, so "*.graphql" finds operation
+// files at any depth. Files are parsed with gqlparser.ParseQueryFS, so they can
+// #import fragments from other files. Files without operations are skipped, since they only hold
+// fragments for others to import. All validation errors are returned as a gqlerror.List.
+func LoadManifest(schema *ast.Schema, fsys fs.FS, patterns ...string) (*Manifest, error) {
+	seen := map[string]bool{}
+	var docs []*ast.QueryDocument
+	var errs gqlerror.List
+	for _, pattern := range patterns {
+		paths, err := gqlparser.GlobFS(fsys, pattern)
+		if err != nil {
+			return nil, gqlerror.Errorf("pattern %s: %s", strconv.Quote(pattern), err)
+		}
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			doc, err := gqlparser.ParseQueryFS(fsys, path)
+			if err != nil {
+				errs = append(errs, gqlerror.WrapIfUnwrapped(err))
+				continue
+			}
+			if len(doc.Operations) == 0 {
+				continue
+			}
+			errs = append(errs, validator.Validate(schema, doc)...)
+			docs = append(docs, doc)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return NewManifest(docs...)
+}
+
+// WriteGo writes a Go source file declaring the name, hash and body of every operation in the
+// manifest as constants, eg UserQueryName, UserQueryHash and UserQuery for the query User, or
+// RenameMutation for the mutation Rename. The operation type isn't repeated when the name already
+// ends with it, so the query UserQuery is also written as UserQuery.
+//
+// Names are exported by upper casing their first letter. Operations that would be written with
+// the same name are reported as an error.
+func (m *Manifest) WriteGo(w io.Writer, packageName string) error {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gqlparser persisted. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", packageName)
+
+	declared := map[string]string{}
+	for _, op := range m.Operations {
+		ident := goIdentifier(op.Name)
+		if suffix := goIdentifier(string(op.Type)); !strings.HasSuffix(ident, suffix) {
+			ident += suffix
+		}
+		if prev, ok := declared[ident]; ok {
+			return fmt.Errorf("operations %s and %s would both be written as %s", prev, op.Name, ident)
+		}
+		declared[ident] = op.Name
+
+		fmt.Fprintf(&buf, "\n// %s is the %s %s.\n", ident, op.Type, op.Name)
+		fmt.Fprintf(&buf, "const (\n")
+		fmt.Fprintf(&buf, "%sName = %s\n", ident, strconv.Quote(op.Name))
+		fmt.Fprintf(&buf, "%sHash = %s\n", ident, strconv.Quote(op.ID))
+		fmt.Fprintf(&buf, "%s = %s\n", ident, strconv.Quote(op.Body))
+		fmt.Fprintf(&buf, ")\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func goIdentifier(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
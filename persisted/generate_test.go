@@ -0,0 +1,80 @@
+package persisted_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/persisted"
+	"github.com/vektah/gqlparser/v2/transform"
+)
+
+var generateSchema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	type Query { user(id: ID!): User }
+	type Mutation { rename(name: String!): User }
+	type User { id: ID! name: String }
+`})
+
+func TestLoadManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"ops/user.graphql":      {Data: []byte("#import \"./fragments.graphql\"\nquery UserQuery($id: ID!) { user(id: $id) { ...UserFields } }")},
+		"ops/rename.graphql":    {Data: []byte(`mutation rename { rename(name: "\"x\"") { id } }`)},
+		"ops/fragments.graphql": {Data: []byte("fragment UserFields on User { id name }")},
+	}
+
+	m, err := persisted.LoadManifest(generateSchema, fsys, "ops/*.graphql")
+	require.NoError(t, err)
+	require.Len(t, m.Operations, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.WriteGo(&buf, "ops"))
+	require.Equal(t, `// Code generated by gqlparser persisted. DO NOT EDIT.
+
+package ops
+
+// UserQuery is the query UserQuery.
+const (
+	UserQueryName = "UserQuery"
+	UserQueryHash = "`+transform.HashQuery(m.Operations[0].Body)+`"
+	UserQuery     = "query UserQuery($id:ID!){user(id:$id){...UserFields}}fragment UserFields on User{id name}"
+)
+
+// RenameMutation is the mutation rename.
+const (
+	RenameMutationName = "rename"
+	RenameMutationHash = "`+transform.HashQuery(m.Operations[1].Body)+`"
+	RenameMutation     = "mutation rename{rename(name:\"\\\"x\\\"\"){id}}"
+)
+`, buf.String())
+
+	t.Run("patterns without a slash match at any depth", func(t *testing.T) {
+		m, err := persisted.LoadManifest(generateSchema, fsys, "*.graphql")
+		require.NoError(t, err)
+		require.Len(t, m.Operations, 2)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.graphql": {Data: []byte("query A { user(id: 1) { zzz } }")},
+			"b.graphql": {Data: []byte("query B { me }")},
+		}
+
+		_, err := persisted.LoadManifest(generateSchema, fsys, "*.graphql")
+		var list gqlerror.List
+		require.ErrorAs(t, err, &list)
+		require.Len(t, list, 2)
+		require.Equal(t, `a.graphql:1:25: Cannot query field "zzz" on type "User".`, list[0].Error())
+		require.Equal(t, `b.graphql:1:11: Cannot query field "me" on type "Query".`, list[1].Error())
+	})
+
+	t.Run("names written the same way", func(t *testing.T) {
+		m, err := persisted.NewManifest(parse(t, "", `query User { user(id: 1) { id } } query userQuery { user(id: 2) { id } }`))
+		require.NoError(t, err)
+		require.EqualError(t, m.WriteGo(&bytes.Buffer{}, "ops"), "operations User and userQuery would both be written as UserQuery")
+	})
+}
@@ -0,0 +1,120 @@
+// Package persisted builds persisted query manifests, the lists of operations a client is allowed
+// to send by id that gateways and servers load at startup.
+package persisted
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/transform"
+)
+
+// Operation is an entry of a manifest.
+type Operation struct {
+	// ID is the hex encoded sha256 of Body.
+	ID   string        `json:"id"`
+	Name string        `json:"name"`
+	Type ast.Operation `json:"type"`
+	// Body is the operation and the fragments it uses, in the canonical form printed by
+	// transform.Print.
+	Body string `json:"body"`
+}
+
+// Manifest is a list of persisted operations, sorted by name.
+type Manifest struct {
+	Operations []Operation
+}
+
+// NewManifest returns the manifest of every operation in docs.
+//
+// Operations must be named, and an operation name can only be used once unless every operation
+// using it is the same. Fragments are resolved within the document of each operation. docs
+// should have been validated.
+func NewManifest(docs ...*ast.QueryDocument) (*Manifest, error) {
+	// first holds the first operation seen with each name
+	first := map[string]*ast.OperationDefinition{}
+	ids := map[string]string{}
+	m := &Manifest{}
+	for _, doc := range docs {
+		for _, opDoc := range transform.SplitOperations(doc) {
+			op := opDoc.Operations[0]
+			if op.Name == "" {
+				return nil, gqlerror.ErrorPosf(op.Position, "persisted operations must be named")
+			}
+
+			body := transform.Print(transform.Canonicalize(opDoc, transform.CanonicalOptions{}))
+			operation := Operation{
+				ID:   transform.HashQuery(body),
+				Name: op.Name,
+				Type: op.Operation,
+				Body: body,
+			}
+			if operation.Type == "" {
+				operation.Type = ast.Query
+			}
+
+			if prev := first[op.Name]; prev != nil {
+				if ids[op.Name] != operation.ID {
+					return nil, gqlerror.ErrorPosf(op.Position, "operation %s is already defined differently at %s",
+						strconv.Quote(op.Name), describePosition(prev.Position))
+				}
+				continue
+			}
+			first[op.Name] = op
+			ids[op.Name] = operation.ID
+			m.Operations = append(m.Operations, operation)
+		}
+	}
+
+	sort.Slice(m.Operations, func(i, j int) bool {
+		return m.Operations[i].Name < m.Operations[j].Name
+	})
+	return m, nil
+}
+
+// MarshalApollo encodes the manifest in the persisted query manifest format of Apollo Router and
+// Apollo Client.
+func (m *Manifest) MarshalApollo() ([]byte, error) {
+	return json.MarshalIndent(struct {
+		Format     string      `json:"format"`
+		Version    int         `json:"version"`
+		Operations []Operation `json:"operations"`
+	}{
+		Format:     "apollo-persisted-query-manifest",
+		Version:    1,
+		Operations: m.operations(),
+	}, "", "  ")
+}
+
+// MarshalKeyValue encodes the manifest as a JSON object mapping every id to its body, the format
+// read by GraphQL Yoga, Hive and most other servers with a persisted operations store.
+func (m *Manifest) MarshalKeyValue() ([]byte, error) {
+	bodies := make(map[string]string, len(m.Operations))
+	for _, op := range m.Operations {
+		bodies[op.ID] = op.Body
+	}
+	// encoding/json sorts map keys, so the output is deterministic.
+	return json.MarshalIndent(bodies, "", "  ")
+}
+
+// operations never returns nil, so an empty manifest encodes an empty list.
+func (m *Manifest) operations() []Operation {
+	if m.Operations == nil {
+		return []Operation{}
+	}
+	return m.Operations
+}
+
+func describePosition(pos *ast.Position) string {
+	if pos == nil {
+		return "an unknown position"
+	}
+	line := strconv.Itoa(pos.Line)
+	if pos.Src != nil && pos.Src.Name != "" {
+		return pos.Src.Name + ":" + line
+	}
+	return "line " + line
+}
@@ -0,0 +1,128 @@
+package persisted_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/persisted"
+	"github.com/vektah/gqlparser/v2/transform"
+)
+
+func parse(t *testing.T, name string, query string) *ast.QueryDocument {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Name: name, Input: query})
+	require.NoError(t, err)
+	return doc
+}
+
+func TestNewManifest(t *testing.T) {
+	users := parse(t, "users.graphql", `
+		query Users { users { ...UserFields } }
+		mutation Rename($id: ID!, $name: String!) { rename(id: $id, name: $name) { ...UserFields } }
+		fragment UserFields on User { id name }
+	`)
+	feed := parse(t, "feed.graphql", `
+		subscription Feed { posts { title } }
+		# the same operation in another file is only listed once
+		query Users { users { ...UserFields } }
+		fragment UserFields on User { id, name: name }
+	`)
+
+	m, err := persisted.NewManifest(users, feed)
+	require.NoError(t, err)
+
+	renameBody := `mutation Rename($id:ID!$name:String!){rename(id:$id name:$name){...UserFields}}fragment UserFields on User{id name}`
+	feedBody := `subscription Feed{posts{title}}`
+	usersBody := `query Users{users{...UserFields}}fragment UserFields on User{id name}`
+	require.Equal(t, []persisted.Operation{
+		{ID: transform.HashQuery(feedBody), Name: "Feed", Type: ast.Subscription, Body: feedBody},
+		{ID: transform.HashQuery(renameBody), Name: "Rename", Type: ast.Mutation, Body: renameBody},
+		{ID: transform.HashQuery(usersBody), Name: "Users", Type: ast.Query, Body: usersBody},
+	}, m.Operations)
+
+	id, err := transform.Hash("Users", users)
+	require.NoError(t, err)
+	require.Equal(t, id, m.Operations[2].ID)
+
+	t.Run("apollo", func(t *testing.T) {
+		b, err := m.MarshalApollo()
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"format": "apollo-persisted-query-manifest",
+			"version": 1,
+			"operations": [
+				{"id": "`+m.Operations[0].ID+`", "name": "Feed", "type": "subscription", "body": "`+feedBody+`"},
+				{"id": "`+m.Operations[1].ID+`", "name": "Rename", "type": "mutation", "body": "`+renameBody+`"},
+				{"id": "`+m.Operations[2].ID+`", "name": "Users", "type": "query", "body": "`+usersBody+`"}
+			]
+		}`, string(b))
+
+		empty, err := (&persisted.Manifest{}).MarshalApollo()
+		require.NoError(t, err)
+		require.JSONEq(t, `{"format": "apollo-persisted-query-manifest", "version": 1, "operations": []}`, string(empty))
+	})
+
+	t.Run("key value", func(t *testing.T) {
+		b, err := m.MarshalKeyValue()
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"`+m.Operations[0].ID+`": "`+feedBody+`",
+			"`+m.Operations[1].ID+`": "`+renameBody+`",
+			"`+m.Operations[2].ID+`": "`+usersBody+`"
+		}`, string(b))
+	})
+
+	t.Run("output is deterministic", func(t *testing.T) {
+		other, err := persisted.NewManifest(feed, users)
+		require.NoError(t, err)
+		require.Equal(t, m, other)
+	})
+}
+
+func TestNewManifestErrors(t *testing.T) {
+	t.Run("anonymous operation", func(t *testing.T) {
+		_, err := persisted.NewManifest(parse(t, "a.graphql", "\n{ me { id } }"))
+		require.EqualError(t, err, "a.graphql:2:1: persisted operations must be named")
+	})
+
+	t.Run("conflicting names", func(t *testing.T) {
+		_, err := persisted.NewManifest(
+			parse(t, "a.graphql", "query Me { me { id } }"),
+			parse(t, "b.graphql", "\n\nquery Me { me { name } }"),
+		)
+		require.EqualError(t, err, `b.graphql:3:1: operation "Me" is already defined differently at a.graphql:1`)
+	})
+}
+
+func TestRelay(t *testing.T) {
+	m, err := persisted.NewManifest(parse(t, "user.graphql", `
+		query UserQuery($id: ID!) { node(id: $id) { id } }
+		mutation LikeMutation { like { count } }
+	`))
+	require.NoError(t, err)
+	like, user := m.Operations[0], m.Operations[1]
+
+	b, err := m.MarshalRelay()
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"`+like.ID+`": "mutation LikeMutation{like{count}}",
+		"`+user.ID+`": "query UserQuery($id:ID!){node(id:$id){id}}"
+	}`, string(b))
+
+	params := m.RelayParams()
+	require.Len(t, params, 2)
+	b, err = json.Marshal(params[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "`+user.ID+`",
+		"metadata": {},
+		"name": "UserQuery",
+		"operationKind": "query",
+		"text": null
+	}`, string(b))
+	require.Equal(t, ast.Mutation, params[0].OperationKind)
+}
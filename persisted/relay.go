@@ -0,0 +1,44 @@
+package persisted
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// RelayParams are the request parameters relay-compiler generates for a persisted operation, as
+// found under params in its artifacts. relay-runtime sends the id instead of the text.
+type RelayParams struct {
+	ID            string                 `json:"id"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	Name          string                 `json:"name"`
+	OperationKind ast.Operation          `json:"operationKind"`
+	// Text is always nil for persisted operations, it is only kept so the params encode with the
+	// same fields as the ones relay-compiler generates.
+	Text *string `json:"text"`
+}
+
+// RelayParams returns the Relay request parameters of every operation in the manifest, in the
+// same order.
+func (m *Manifest) RelayParams() []RelayParams {
+	params := make([]RelayParams, 0, len(m.Operations))
+	for _, op := range m.Operations {
+		params = append(params, RelayParams{
+			ID:            op.ID,
+			Metadata:      map[string]interface{}{},
+			Name:          op.Name,
+			OperationKind: op.Type,
+		})
+	}
+	return params
+}
+
+// MarshalRelay encodes the manifest as the persisted_queries.json file written by relay-compiler
+// when persisting to a local file, a JSON object mapping every id to its text.
+//
+// The ids are sha256 hashes of the canonical text of each operation, like the ids relay-compiler
+// computes when configured with "algorithm": "SHA256". Since relay-compiler prints operations
+// differently the ids don't match its own, so a Relay frontend should take its ids from
+// RelayParams rather than generating them.
+func (m *Manifest) MarshalRelay() ([]byte, error) {
+	// relay-compiler uses the same layout as the key value format
+	return m.MarshalKeyValue()
+}
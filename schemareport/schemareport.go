@@ -0,0 +1,71 @@
+// Package schemareport builds the reports schema registries expect when a service publishes its
+// schema: the schema as normalized SDL, its hash and metadata describing the service.
+package schemareport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"runtime"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+)
+
+// Metadata describes the service reporting a schema. Every field is optional.
+type Metadata struct {
+	// GraphRef is the graph and variant the schema belongs to, eg "my-graph@production".
+	GraphRef string `json:"graphRef,omitempty"`
+	// Subgraph is the name of the subgraph in a federated graph, and RoutingURL the address the
+	// router sends its requests to.
+	Subgraph   string `json:"subgraphName,omitempty"`
+	RoutingURL string `json:"routingUrl,omitempty"`
+
+	// BootID identifies this run of the service, and ServerID the host it runs on.
+	BootID   string `json:"bootId,omitempty"`
+	ServerID string `json:"serverId,omitempty"`
+	// UserVersion is the version of the service, eg a git commit.
+	UserVersion    string `json:"userVersion,omitempty"`
+	Platform       string `json:"platform,omitempty"`
+	LibraryVersion string `json:"libraryVersion,omitempty"`
+	// RuntimeVersion defaults to the version of Go the service was built with.
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+}
+
+// Report is a schema report. It encodes to JSON with encoding/json, using the field names of the
+// schema reporting protocol.
+type Report struct {
+	Metadata
+	// SchemaHash is the hex encoded sha256 of SDL.
+	SchemaHash string `json:"coreSchemaHash"`
+	SDL        string `json:"coreSchema"`
+}
+
+// New returns the report of schema.
+func New(schema *ast.Schema, metadata Metadata) *Report {
+	if metadata.RuntimeVersion == "" {
+		metadata.RuntimeVersion = runtime.Version()
+	}
+	sdl := NormalizedSDL(schema)
+	return &Report{
+		Metadata:   metadata,
+		SchemaHash: Hash(sdl),
+		SDL:        sdl,
+	}
+}
+
+// NormalizedSDL prints schema with its types and directives sorted by name, without built in
+// definitions or comments, so schemas that only differ in the order or the files they were
+// written in print the same way. Descriptions are kept.
+func NormalizedSDL(schema *ast.Schema) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent("  ")).FormatSchema(schema)
+	return buf.String()
+}
+
+// Hash returns the hex encoded sha256 of sdl, as registries compute it to tell whether a schema
+// changed.
+func Hash(sdl string) string {
+	sum := sha256.Sum256([]byte(sdl))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package schemareport_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/schemareport"
+)
+
+func TestNormalizedSDL(t *testing.T) {
+	a := gqlparser.MustLoadSchema(
+		&ast.Source{Name: "query.graphql", Input: `
+			# the entry point
+			type Query { user(id: ID!): User }
+		`},
+		&ast.Source{Name: "user.graphql", Input: `
+			directive @key(fields: String!) on OBJECT
+			"A person."
+			type User @key(fields: "id") { id: ID! name: String }
+		`},
+	)
+	b := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		"A person."
+		type User @key(fields: "id") {
+			id: ID!
+			name: String
+		}
+		type Query {
+			user(id: ID!): User
+		}
+		directive @key(fields: String!) on OBJECT
+	`})
+
+	sdl := schemareport.NormalizedSDL(a)
+	require.Equal(t, `directive @key(fields: String!) on OBJECT
+type Query {
+  user(id: ID!): User
+}
+"""
+A person.
+"""
+type User @key(fields: "id") {
+  id: ID!
+  name: String
+}
+`, sdl)
+	require.Equal(t, sdl, schemareport.NormalizedSDL(b))
+}
+
+func TestNew(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { hello: String }`})
+
+	report := schemareport.New(schema, schemareport.Metadata{
+		GraphRef:   "shop@production",
+		Subgraph:   "greetings",
+		RoutingURL: "http://greetings:4000/graphql",
+	})
+
+	sdl := "type Query {\n  hello: String\n}\n"
+	sum := sha256.Sum256([]byte(sdl))
+	hash := hex.EncodeToString(sum[:])
+	require.Equal(t, sdl, report.SDL)
+	require.Equal(t, hash, report.SchemaHash)
+	require.Equal(t, hash, schemareport.Hash(sdl))
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"graphRef": "shop@production",
+		"subgraphName": "greetings",
+		"routingUrl": "http://greetings:4000/graphql",
+		"runtimeVersion": "`+runtime.Version()+`",
+		"coreSchemaHash": "`+hash+`",
+		"coreSchema": "type Query {\n  hello: String\n}\n"
+	}`, string(b))
+
+	t.Run("runtime version can be set", func(t *testing.T) {
+		report := schemareport.New(schema, schemareport.Metadata{RuntimeVersion: "go1.0"})
+		require.Equal(t, "go1.0", report.RuntimeVersion)
+	})
+}
@@ -0,0 +1,262 @@
+// Package schemawatch keeps a schema loaded from files up to date while a server is running, so
+// long running gateways can pick up schema changes without restarting.
+//
+// A Loader polls the files of the schema, or reloads when told to with Invalidate. The new schema
+// is only swapped in once it has been loaded and validated in full, so requests always see a
+// complete schema, and a broken edit leaves the previous schema in place.
+//
+// Queries parsed and validated through the Loader are cached with the schema they were validated
+// against, and the cache is dropped along with the old schema when a new one is swapped in.
+package schemawatch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DefaultInterval is how often Watch checks the files for changes unless WithInterval is given.
+const DefaultInterval = 2 * time.Second
+
+// DefaultCacheSize is how many queries are cached per schema unless WithCacheSize is given.
+const DefaultCacheSize = 1000
+
+// Option configures a Loader.
+type Option func(l *Loader)
+
+// WithInterval sets how often Watch checks the files for changes. It must be positive.
+func WithInterval(interval time.Duration) Option {
+	return func(l *Loader) {
+		l.interval = interval
+	}
+}
+
+// WithCacheSize sets how many queries are cached for the current schema. The cache is emptied
+// when it is full. A size of 0 disables the cache.
+func WithCacheSize(size int) Option {
+	return func(l *Loader) {
+		l.cacheSize = size
+	}
+}
+
+// WithOnReload registers fn to be called with the new schema after it has been swapped in, to
+// invalidate caches kept outside of the Loader. It is called from the goroutine that reloaded
+// the schema.
+func WithOnReload(fn func(schema *ast.Schema)) Option {
+	return func(l *Loader) {
+		l.onReload = append(l.onReload, fn)
+	}
+}
+
+// WithOnError registers fn to be called when Watch fails to reload the schema. The previous
+// schema stays in use.
+func WithOnError(fn func(err error)) Option {
+	return func(l *Loader) {
+		l.onError = append(l.onError, fn)
+	}
+}
+
+// Loader loads a schema from the files of fsys matching patterns, and reloads it when they
+// change. Its methods are safe for concurrent use.
+type Loader struct {
+	fsys      fs.FS
+	patterns  []string
+	interval  time.Duration
+	cacheSize int
+	onReload  []func(schema *ast.Schema)
+	onError   []func(err error)
+
+	current atomic.Pointer[snapshot]
+
+	// mu serializes reloads
+	mu sync.Mutex
+	// fingerprint describes the files the current schema was loaded from
+	fingerprint string
+}
+
+// snapshot is a schema and the queries validated against it. They are swapped together so a
+// cached query is never returned for another schema.
+type snapshot struct {
+	schema *ast.Schema
+
+	mu      sync.Mutex
+	queries map[string]*query
+}
+
+type query struct {
+	doc  *ast.QueryDocument
+	errs gqlerror.List
+}
+
+// New loads the schema from the files of fsys matching patterns, which use the syntax described
+// on gqlparser.LoadSchemaFS.
+func New(fsys fs.FS, patterns []string, options ...Option) (*Loader, error) {
+	l := &Loader{
+		fsys:      fsys,
+		patterns:  patterns,
+		interval:  DefaultInterval,
+		cacheSize: DefaultCacheSize,
+	}
+	for _, option := range options {
+		option(l)
+	}
+	if l.interval <= 0 {
+		return nil, fmt.Errorf("schemawatch: interval must be positive, got %s", l.interval)
+	}
+	if _, err := l.reload(true); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Schema returns the current schema.
+func (l *Loader) Schema() *ast.Schema {
+	return l.current.Load().schema
+}
+
+// LoadQuery parses and validates str against the current schema, like gqlparser.LoadQuery. The
+// result is cached until the schema changes, and the schema it was validated against is returned
+// with it so that the query is executed against the same one.
+//
+// The returned document is shared by every caller loading the same query, so it must not be
+// modified.
+func (l *Loader) LoadQuery(str string) (*ast.Schema, *ast.QueryDocument, gqlerror.List) {
+	s := l.current.Load()
+	if l.cacheSize <= 0 {
+		doc, errs := gqlparser.LoadQuery(s.schema, str)
+		return s.schema, doc, errs
+	}
+
+	s.mu.Lock()
+	q, ok := s.queries[str]
+	s.mu.Unlock()
+	if ok {
+		return s.schema, q.doc, q.errs
+	}
+
+	q = &query{}
+	q.doc, q.errs = gqlparser.LoadQuery(s.schema, str)
+
+	s.mu.Lock()
+	if len(s.queries) >= l.cacheSize {
+		s.queries = map[string]*query{}
+	}
+	s.queries[str] = q
+	s.mu.Unlock()
+	return s.schema, q.doc, q.errs
+}
+
+// Reload reloads the schema if any of its files changed, were added or were removed since it was
+// last loaded, and reports whether a new schema was swapped in. Changes are found by comparing
+// the size and modification time of the files.
+//
+// If the new schema fails to load the error is returned and the previous schema stays in use.
+func (l *Loader) Reload() (bool, error) {
+	return l.reload(false)
+}
+
+// Invalidate reloads the schema even if its files look unchanged, for file systems that don't
+// report modification times or when a reload is triggered from outside, such as by a signal.
+func (l *Loader) Invalidate() error {
+	_, err := l.reload(true)
+	return err
+}
+
+// Watch calls Reload at the configured interval until ctx is done. Errors are passed to the
+// functions registered with WithOnError.
+func (l *Loader) Watch(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := l.Reload(); err != nil {
+				for _, fn := range l.onError {
+					fn(err)
+				}
+			}
+		}
+	}
+}
+
+func (l *Loader) reload(force bool) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the files are looked at before they are read, so a change made while loading is found by
+	// the next check.
+	fingerprint, err := l.fingerprintFiles()
+	if err != nil {
+		return false, err
+	}
+	if !force && fingerprint == l.fingerprint {
+		return false, nil
+	}
+
+	schema, err := l.load()
+	if err != nil {
+		return false, err
+	}
+	l.current.Store(&snapshot{schema: schema, queries: map[string]*query{}})
+	l.fingerprint = fingerprint
+	for _, fn := range l.onReload {
+		fn(schema)
+	}
+	return true, nil
+}
+
+func (l *Loader) load() (*ast.Schema, error) {
+	doc, err := gqlparser.LoadSources(l.fsys, l.patterns...)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := gqlparser.LoadSchemaDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// fingerprintFiles describes the path, size and modification time of every file matching the
+// patterns.
+func (l *Loader) fingerprintFiles() (string, error) {
+	var b strings.Builder
+	err := fs.WalkDir(l.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !l.matches(p) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s\x00%d\x00%d\n", p, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", gqlerror.Wrap(err)
+	}
+	return b.String(), nil
+}
+
+// matches reports whether p matches one of the patterns.
+func (l *Loader) matches(p string) bool {
+	for _, pattern := range l.patterns {
+		if gqlparser.MatchPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
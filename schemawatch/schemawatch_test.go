@@ -0,0 +1,188 @@
+package schemawatch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/schemawatch"
+)
+
+func file(data string, modTime time.Time) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(data), ModTime: modTime}
+}
+
+func TestLoader(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"schema/query.graphql": file(`type Query { user: User }`, start),
+		"schema/user.graphql":  file(`type User { id: ID! }`, start),
+		"readme.md":            file(`not a schema`, start),
+	}
+
+	var reloaded []*ast.Schema
+	l, err := schemawatch.New(fsys, []string{"*.graphql"}, schemawatch.WithOnReload(func(schema *ast.Schema) {
+		reloaded = append(reloaded, schema)
+	}))
+	require.NoError(t, err)
+	first := l.Schema()
+	require.NotNil(t, first.Types["User"])
+	require.Len(t, reloaded, 1)
+
+	t.Run("queries are cached per schema", func(t *testing.T) {
+		schema, doc, errs := l.LoadQuery(`{ user { id } }`)
+		require.Nil(t, errs)
+		require.Same(t, first, schema)
+
+		_, again, _ := l.LoadQuery(`{ user { id } }`)
+		require.Same(t, doc, again)
+
+		_, _, errs = l.LoadQuery(`{ user { name } }`)
+		require.Len(t, errs, 1)
+	})
+
+	t.Run("unchanged files are not reloaded", func(t *testing.T) {
+		changed, err := l.Reload()
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Same(t, first, l.Schema())
+
+		fsys["readme.md"] = file(`still not a schema`, start.Add(time.Minute))
+		changed, err = l.Reload()
+		require.NoError(t, err)
+		require.False(t, changed)
+	})
+
+	t.Run("changed files are reloaded", func(t *testing.T) {
+		_, before, _ := l.LoadQuery(`{ user { id } }`)
+
+		fsys["schema/user.graphql"] = file(`type User { id: ID! name: String }`, start.Add(time.Minute))
+		changed, err := l.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.NotSame(t, first, l.Schema())
+		require.NotNil(t, l.Schema().Types["User"].Fields.ForName("name"))
+		require.Len(t, reloaded, 2)
+		require.Same(t, l.Schema(), reloaded[1])
+
+		schema, after, errs := l.LoadQuery(`{ user { id } }`)
+		require.Nil(t, errs)
+		require.Same(t, l.Schema(), schema)
+		require.NotSame(t, before, after)
+
+		_, _, errs = l.LoadQuery(`{ user { name } }`)
+		require.Nil(t, errs)
+	})
+
+	t.Run("added files are reloaded", func(t *testing.T) {
+		fsys["schema/post.graphql"] = file(`type Post { id: ID! } extend type Query { posts: [Post] }`, start)
+		changed, err := l.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.NotNil(t, l.Schema().Types["Post"])
+	})
+
+	t.Run("broken edits keep the previous schema", func(t *testing.T) {
+		current := l.Schema()
+		fsys["schema/post.graphql"] = file(`type Post { id: Missing }`, start.Add(2*time.Minute))
+		changed, err := l.Reload()
+		require.EqualError(t, err, "schema/post.graphql:1:17: Undefined type Missing.")
+		require.False(t, changed)
+		require.Same(t, current, l.Schema())
+
+		// the broken files are tried again until they are fixed
+		_, err = l.Reload()
+		require.Error(t, err)
+
+		delete(fsys, "schema/post.graphql")
+		changed, err = l.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Nil(t, l.Schema().Types["Post"])
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		current := l.Schema()
+		// same size and modification time, so only found by invalidating
+		fsys["schema/user.graphql"] = file(`type User { id: ID! nick: String }`, start.Add(time.Minute))
+		changed, err := l.Reload()
+		require.NoError(t, err)
+		require.False(t, changed)
+
+		require.NoError(t, l.Invalidate())
+		require.NotSame(t, current, l.Schema())
+		require.NotNil(t, l.Schema().Types["User"].Fields.ForName("nick"))
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		l, err := schemawatch.New(fsys, []string{"*.graphql"}, schemawatch.WithCacheSize(0))
+		require.NoError(t, err)
+		_, a, _ := l.LoadQuery(`{ user { id } }`)
+		_, b, _ := l.LoadQuery(`{ user { id } }`)
+		require.NotSame(t, a, b)
+	})
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := schemawatch.New(fstest.MapFS{}, []string{"*.graphql"})
+	require.EqualError(t, err, `input: pattern "*.graphql" matches no files`)
+
+	_, err = schemawatch.New(fstest.MapFS{"schema.graphql": {Data: []byte(`type Query {`)}}, []string{"*.graphql"})
+	require.Error(t, err)
+
+	_, err = schemawatch.New(fstest.MapFS{"schema.graphql": {Data: []byte(`type Query { a: String }`)}}, []string{"*.graphql"}, schemawatch.WithInterval(0))
+	require.EqualError(t, err, "schemawatch: interval must be positive, got 0s")
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "schema.graphql")
+	require.NoError(t, os.WriteFile(name, []byte(`type Query { a: String }`), 0o644))
+
+	reloaded := make(chan *ast.Schema, 10)
+	errs := make(chan error, 10)
+	l, err := schemawatch.New(os.DirFS(dir), []string{"*.graphql"},
+		schemawatch.WithInterval(10*time.Millisecond),
+		schemawatch.WithOnReload(func(schema *ast.Schema) { reloaded <- schema }),
+		schemawatch.WithOnError(func(err error) { errs <- err }),
+	)
+	require.NoError(t, err)
+	<-reloaded
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		l.Watch(ctx)
+		close(done)
+	}()
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.WriteFile(name, []byte(`type Query { a: String b: Int }`), 0o644))
+	require.NoError(t, os.Chtimes(name, later, later))
+	select {
+	case schema := <-reloaded:
+		require.NotNil(t, schema.Query.Fields.ForName("b"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("schema was not reloaded")
+	}
+
+	later = later.Add(time.Hour)
+	require.NoError(t, os.WriteFile(name, []byte(`type Query {`), 0o644))
+	require.NoError(t, os.Chtimes(name, later, later))
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("error was not reported")
+	}
+	require.NotNil(t, l.Schema().Query.Fields.ForName("b"))
+
+	cancel()
+	<-done
+}
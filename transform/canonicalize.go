@@ -0,0 +1,219 @@
+package transform
+
+import (
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// CanonicalOptions selects the optional normalizations made by Canonicalize. The zero value only
+// makes changes that don't affect the response to the document.
+type CanonicalOptions struct {
+	// SortSelections sorts every selection set, fields first, then fragment spreads and inline
+	// fragments. The response holds the same data but its keys may come in a different order.
+	SortSelections bool
+	// RemoveAliases drops all field aliases. The result no longer produces the response the
+	// client asked for, and may not even be valid, so this is only useful for analytics.
+	RemoveAliases bool
+	// HideLiterals replaces literal numbers with 0, strings with "" and lists and objects with
+	// empty ones, so operations that only differ in their inputs are treated as the same
+	// operation. Booleans, enum values and null are kept.
+	HideLiterals bool
+}
+
+// Canonicalize returns a copy of doc in a deterministic form, so that documents that only differ
+// in formatting or in the order of things that have no meaning end up identical. Printed with
+// Print the result is suitable for deduplicating and grouping operations.
+//
+// Operations, fragments, variable definitions, arguments, directives and the fields of input
+// objects are sorted by name, aliases that are the same as the field name and all comments are
+// removed. options enables further normalizations.
+func Canonicalize(doc *ast.QueryDocument, options CanonicalOptions) *ast.QueryDocument {
+	c := canonicalizer{options: options}
+
+	result := &ast.QueryDocument{Position: doc.Position}
+	for _, op := range doc.Operations {
+		canonical := *op
+		canonical.Comment = nil
+		canonical.VariableDefinitions = c.variableDefinitions(op.VariableDefinitions)
+		canonical.Directives = c.directives(op.Directives)
+		canonical.SelectionSet = c.selectionSet(op.SelectionSet)
+		result.Operations = append(result.Operations, &canonical)
+	}
+	for _, fragment := range doc.Fragments {
+		canonical := *fragment
+		canonical.Comment = nil
+		canonical.VariableDefinition = c.variableDefinitions(fragment.VariableDefinition)
+		canonical.Directives = c.directives(fragment.Directives)
+		canonical.SelectionSet = c.selectionSet(fragment.SelectionSet)
+		result.Fragments = append(result.Fragments, &canonical)
+	}
+
+	sort.SliceStable(result.Operations, func(i, j int) bool {
+		return result.Operations[i].Name < result.Operations[j].Name
+	})
+	sort.SliceStable(result.Fragments, func(i, j int) bool {
+		return result.Fragments[i].Name < result.Fragments[j].Name
+	})
+	return result
+}
+
+type canonicalizer struct {
+	options CanonicalOptions
+}
+
+func (c *canonicalizer) selectionSet(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			field.Comment = nil
+			if c.options.RemoveAliases || field.Alias == field.Name {
+				field.Alias = ""
+			}
+			field.Arguments = c.arguments(sel.Arguments)
+			field.Directives = c.directives(sel.Directives)
+			field.SelectionSet = c.selectionSet(sel.SelectionSet)
+			result = append(result, &field)
+
+		case *ast.FragmentSpread:
+			spread := *sel
+			spread.Comment = nil
+			spread.Directives = c.directives(sel.Directives)
+			result = append(result, &spread)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			fragment.Comment = nil
+			fragment.Directives = c.directives(sel.Directives)
+			fragment.SelectionSet = c.selectionSet(sel.SelectionSet)
+			result = append(result, &fragment)
+		}
+	}
+
+	if c.options.SortSelections {
+		// selections are compared by kind and then by their printed form, which starts with the
+		// response key or the fragment name and makes selections that only differ in their
+		// arguments or sub selections sort the same way every time.
+		keys := make(map[ast.Selection]string, len(result))
+		for _, sel := range result {
+			var p printer
+			p.selection(sel)
+			keys[sel] = p.buf.String()
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			ki, kj := selectionKind(result[i]), selectionKind(result[j])
+			if ki != kj {
+				return ki < kj
+			}
+			return keys[result[i]] < keys[result[j]]
+		})
+	}
+	return result
+}
+
+func selectionKind(sel ast.Selection) int {
+	switch sel.(type) {
+	case *ast.Field:
+		return 0
+	case *ast.FragmentSpread:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (c *canonicalizer) variableDefinitions(defs ast.VariableDefinitionList) ast.VariableDefinitionList {
+	if defs == nil {
+		return nil
+	}
+
+	result := make(ast.VariableDefinitionList, len(defs))
+	for i, def := range defs {
+		canonical := *def
+		canonical.Comment = nil
+		canonical.DefaultValue = c.value(def.DefaultValue)
+		canonical.Directives = c.directives(def.Directives)
+		result[i] = &canonical
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Variable < result[j].Variable
+	})
+	return result
+}
+
+func (c *canonicalizer) directives(directives ast.DirectiveList) ast.DirectiveList {
+	if directives == nil {
+		return nil
+	}
+
+	result := make(ast.DirectiveList, len(directives))
+	for i, dir := range directives {
+		canonical := *dir
+		canonical.Arguments = c.arguments(dir.Arguments)
+		result[i] = &canonical
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (c *canonicalizer) arguments(args ast.ArgumentList) ast.ArgumentList {
+	if args == nil {
+		return nil
+	}
+
+	result := make(ast.ArgumentList, len(args))
+	for i, arg := range args {
+		canonical := *arg
+		canonical.Comment = nil
+		canonical.Value = c.value(arg.Value)
+		result[i] = &canonical
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func (c *canonicalizer) value(v *ast.Value) *ast.Value {
+	if v == nil {
+		return nil
+	}
+
+	canonical := *v
+	canonical.Comment = nil
+	if c.options.HideLiterals {
+		switch v.Kind {
+		case ast.IntValue, ast.FloatValue:
+			canonical.Kind, canonical.Raw = ast.IntValue, "0"
+		case ast.StringValue, ast.BlockValue:
+			canonical.Kind, canonical.Raw = ast.StringValue, ""
+		case ast.ListValue, ast.ObjectValue:
+			canonical.Children = ast.ChildValueList{}
+		}
+	}
+	if len(canonical.Children) == 0 {
+		return &canonical
+	}
+
+	canonical.Children = make(ast.ChildValueList, len(v.Children))
+	for i, child := range v.Children {
+		value := *child
+		value.Comment = nil
+		value.Value = c.value(child.Value)
+		canonical.Children[i] = &value
+	}
+	if v.Kind == ast.ObjectValue {
+		sort.SliceStable(canonical.Children, func(i, j int) bool {
+			return canonical.Children[i].Name < canonical.Children[j].Name
+		})
+	}
+	return &canonical
+}
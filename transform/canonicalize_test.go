@@ -0,0 +1,96 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestCanonicalize(t *testing.T) {
+	canonical := func(t *testing.T, query string, options CanonicalOptions) string {
+		t.Helper()
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		return Print(Canonicalize(doc, options))
+	}
+
+	t.Run("documents that only differ in formatting are identical", func(t *testing.T) {
+		a := canonical(t, `
+			# fetch users
+			query Users($role: Role, $first: Int = 10) @cached(ttl: 60, scope: PUBLIC) {
+				users(role: $role, first: $first, filter: {roles: [ADMIN], name: "bob"}) {
+					name: name
+					id
+				}
+			}
+			fragment B on User { id }
+			fragment A on User { name }
+		`, CanonicalOptions{})
+		b := canonical(t, `fragment A on User{name} fragment B on User{id}
+			query Users($first:Int=10,$role:Role)@cached(scope:PUBLIC,ttl:60){
+				users(filter:{name:"bob",roles:[ADMIN]},first:$first,role:$role){name id}
+			}`, CanonicalOptions{})
+
+		require.Equal(t, `query Users($first:Int=10$role:Role)@cached(scope:PUBLIC ttl:60){users(filter:{name:"bob"roles:[ADMIN]}first:$first role:$role){name id}}fragment A on User{name}fragment B on User{id}`, a)
+		require.Equal(t, a, b)
+	})
+
+	t.Run("sorts operations and directives by name", func(t *testing.T) {
+		require.Equal(t,
+			`{a}query A{b}query B@a@b{c}`,
+			canonical(t, `query B @b @a { c } query A { b } { a }`, CanonicalOptions{}),
+		)
+	})
+
+	t.Run("keeps selection order by default", func(t *testing.T) {
+		require.Equal(t, `{b a}`, canonical(t, `{ b a }`, CanonicalOptions{}))
+	})
+
+	t.Run("sort selections", func(t *testing.T) {
+		require.Equal(t,
+			`{a(x:1)a(x:2)b{c d}...F...on User{a b}}`,
+			canonical(t, `{ ... on User { b a } ...F b { d c } a(x: 2) a(x: 1) }`, CanonicalOptions{SortSelections: true}),
+		)
+	})
+
+	t.Run("remove aliases", func(t *testing.T) {
+		require.Equal(t,
+			`{user(id:1){name}user(id:2){name}}`,
+			canonical(t, `{ a: user(id: 1) { name } b: user(id: 2) { n: name } }`, CanonicalOptions{RemoveAliases: true}),
+		)
+	})
+
+	t.Run("hide literals", func(t *testing.T) {
+		require.Equal(t,
+			`query($a:Int=0){f(b:true c:0 d:""e:ENUM f:null g:[]h:{}i:$a)}`,
+			canonical(t, `query ($a: Int = 5) { f(i: $a, h: {x: 1}, g: [1, 2], f: null, e: ENUM, d: """block""", c: 1.5, b: true) }`, CanonicalOptions{HideLiterals: true}),
+		)
+	})
+
+	t.Run("does not modify the input", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: `query Q($b: Int, $a: Int) { f(b: $b, a: $a) { b a } }`})
+		require.NoError(t, err)
+		before := format(doc)
+		Canonicalize(doc, CanonicalOptions{SortSelections: true, RemoveAliases: true, HideLiterals: true})
+		require.Equal(t, before, format(doc))
+	})
+}
+
+func TestPrint(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query Q($ids: [ID!]! = [1 2], $s: String = "tab\tquote\"\u0001é") {
+			a: user(id: -1.5e3) @skip(if: false) { ...F ... on User { id } ... @include(if: true) { id } }
+		}
+		fragment F on User { name }
+	`})
+	require.NoError(t, err)
+	require.Equal(t,
+		`query Q($ids:[ID!]!=[1 2]$s:String="tab\tquote\"\u0001é"){a:user(id:-1.5e3)@skip(if:false){...F...on User{id}...@include(if:true){id}}}fragment F on User{name}`,
+		Print(doc),
+	)
+
+	_, err = parser.ParseQuery(&ast.Source{Input: Print(doc)})
+	require.NoError(t, err)
+}
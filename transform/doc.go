@@ -0,0 +1,6 @@
+// Package transform rewrites query documents, for example to make an operation self contained
+// before forwarding it to a server that doesn't support fragments.
+//
+// Transforms never modify the document they are given. Nodes that don't need to change are
+// shared between the input and the result, so the result should be treated as read only too.
+package transform
@@ -0,0 +1,32 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Hash returns the persisted query hash of the named operation in doc, the hex encoded sha256 of
+// the operation and the fragments it uses after Canonicalize, as printed by Print.
+//
+// The hash doesn't depend on formatting, on the order of arguments and definitions or on other
+// operations in doc, so it can be used as the id of an operation in a persisted query manifest.
+// Clients using automatic persisted queries send the hash of the exact query they send, see
+// HashQuery, so they must send the printed canonical form for the two to match.
+func Hash(operationName string, doc *ast.QueryDocument) (string, error) {
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return "", gqlerror.Errorf("operation %s not found", strconv.Quote(operationName))
+	}
+	return HashQuery(Print(Canonicalize(operationDocument(doc, op), CanonicalOptions{}))), nil
+}
+
+// HashQuery returns the hex encoded sha256 of query, as sent in the sha256Hash field of the
+// persistedQuery extension by Apollo compatible clients.
+func HashQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestHash(t *testing.T) {
+	hash := func(t *testing.T, operationName string, query string) string {
+		t.Helper()
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		h, err := Hash(operationName, doc)
+		require.NoError(t, err)
+		return h
+	}
+
+	h := hash(t, "", `{ user(id: 1) { name } }`)
+	require.Equal(t, HashQuery(`{user(id:1){name}}`), h)
+	require.Equal(t, "7797f5c7ef97d4b4a71783c41422c22280f452fd1ce8eaad8503491e94c22ac5", h)
+
+	t.Run("ignores formatting and other definitions", func(t *testing.T) {
+		a := hash(t, "Q", `
+			query Q($id: ID!, $first: Int) { user(id: $id) { ...F friends(first: $first) { id } } }
+			fragment F on User { name }
+		`)
+		b := hash(t, "Q", `
+			fragment Unused on User { id }
+			query Other { a }
+			fragment F on User {
+				# the name
+				name
+			}
+			query Q($first: Int, $id: ID!) { user(id: $id) { ...F, friends(first: $first) { id } } }
+		`)
+		require.Equal(t, a, b)
+	})
+
+	t.Run("changes with the operation", func(t *testing.T) {
+		require.NotEqual(t, hash(t, "", `{ a }`), hash(t, "", `{ b }`))
+		require.NotEqual(t, hash(t, "", `{ a: b }`), hash(t, "", `{ b }`))
+		require.NotEqual(t, hash(t, "", `{ a b }`), hash(t, "", `{ b a }`))
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: `query A { a } query B { b }`})
+		require.NoError(t, err)
+		_, err = Hash("", doc)
+		require.EqualError(t, err, `input: operation "" not found`)
+	})
+}
@@ -0,0 +1,122 @@
+package transform
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// InlineFragments returns a copy of doc with every fragment spread replaced by the selections of
+// the fragment, so that each operation is self contained and no fragment definitions are needed.
+//
+// When doc has been validated and the type condition of a fragment is the type it is spread
+// into, its selections are merged into the parent selection set. Otherwise the spread becomes an
+// inline fragment with the same type condition and directives.
+//
+// Spreads of unknown fragments, of fragments whose definition has directives, which can't be
+// carried over to an inline fragment, and spreads that would recurse forever are left in place,
+// and only the definitions of fragments that are still referenced are kept.
+func InlineFragments(doc *ast.QueryDocument) *ast.QueryDocument {
+	inl := inliner{
+		doc:  doc,
+		path: map[string]bool{},
+		kept: map[string]bool{},
+	}
+
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	for _, op := range doc.Operations {
+		inlined := *op
+		inlined.SelectionSet = inl.selectionSet(op.SelectionSet)
+		result.Operations = append(result.Operations, &inlined)
+	}
+	for _, fragment := range doc.Fragments {
+		if inl.kept[fragment.Name] {
+			result.Fragments = append(result.Fragments, fragment)
+		}
+	}
+	return result
+}
+
+type inliner struct {
+	doc *ast.QueryDocument
+	// path holds the fragments currently being inlined, to detect cycles
+	path map[string]bool
+	// kept holds the fragments that are still referenced from the result
+	kept map[string]bool
+}
+
+func (inl *inliner) selectionSet(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			field.SelectionSet = inl.selectionSet(sel.SelectionSet)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			fragment.SelectionSet = inl.selectionSet(sel.SelectionSet)
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			def := inl.doc.Fragments.ForName(sel.Name)
+			if def == nil || len(def.Directives) > 0 || inl.path[sel.Name] {
+				inl.keep(sel.Name)
+				result = append(result, sel)
+				continue
+			}
+
+			inl.path[sel.Name] = true
+			selections := inl.selectionSet(def.SelectionSet)
+			delete(inl.path, sel.Name)
+
+			if len(sel.Directives) == 0 && sel.ObjectDefinition != nil && sel.ObjectDefinition.Name == def.TypeCondition {
+				result = append(result, selections...)
+				continue
+			}
+
+			result = append(result, &ast.InlineFragment{
+				TypeCondition:    def.TypeCondition,
+				Directives:       sel.Directives,
+				SelectionSet:     selections,
+				ObjectDefinition: sel.ObjectDefinition,
+				Position:         sel.Position,
+				Comment:          sel.Comment,
+			})
+		}
+	}
+	return result
+}
+
+// keep marks a fragment, and every fragment it spreads, as still referenced.
+func (inl *inliner) keep(name string) {
+	if inl.kept[name] {
+		return
+	}
+	inl.kept[name] = true
+
+	def := inl.doc.Fragments.ForName(name)
+	if def == nil {
+		return
+	}
+	var visit func(set ast.SelectionSet)
+	visit = func(set ast.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				visit(sel.SelectionSet)
+			case *ast.InlineFragment:
+				visit(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				inl.keep(sel.Name)
+			}
+		}
+	}
+	visit(def.SelectionSet)
+}
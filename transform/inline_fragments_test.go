@@ -0,0 +1,139 @@
+package transform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+var testSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		node(id: ID!): Node
+		user(id: ID!): User
+		users(first: Int, after: String, role: Role, filter: UserFilter): [User!]!
+	}
+	interface Node {
+		id: ID!
+	}
+	type User implements Node {
+		id: ID!
+		name: String
+		email: String
+		password: String
+		friends(first: Int): [User!]!
+	}
+	type Post implements Node {
+		id: ID!
+		title: String
+		author: User
+	}
+	enum Role {
+		ADMIN
+		MEMBER
+	}
+	input UserFilter {
+		name: String
+		roles: [Role!]
+	}
+`})
+
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent("  ")).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+func TestInlineFragments(t *testing.T) {
+	t.Run("merges fragments on the parent type and inlines the rest", func(t *testing.T) {
+		doc := gqlparser.MustLoadQuery(testSchema, `
+			query Q($id: ID!) {
+				node(id: $id) {
+					...NodeFields
+					...UserFields @include(if: true)
+					...PostFields
+				}
+			}
+			fragment NodeFields on Node { id }
+			fragment UserFields on User { name friends(first: 1) { ...Friend } }
+			fragment PostFields on Post { title }
+			fragment Friend on User { id name }
+		`)
+		before := format(doc)
+
+		require.Equal(t, `query Q ($id: ID!) {
+  node(id: $id) {
+    id
+    ... on User @include(if: true) {
+      name
+      friends(first: 1) {
+        id
+        name
+      }
+    }
+    ... on Post {
+      title
+    }
+  }
+}
+`, format(InlineFragments(doc)))
+		require.Equal(t, before, format(doc), "the input document must not be modified")
+	})
+
+	t.Run("unvalidated documents only use inline fragments", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: `{ user(id: 1) { ...F } } fragment F on User { id }`})
+		require.NoError(t, err)
+
+		require.Equal(t, `query {
+  user(id: 1) {
+    ... on User {
+      id
+    }
+  }
+}
+`, format(InlineFragments(doc)))
+	})
+
+	t.Run("keeps unknown and cyclic fragments", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: `
+			{ user(id: 1) { ...Unknown ...A } }
+			fragment A on User { friends { ...B } }
+			fragment B on User { friends { ...A ...C } }
+			fragment C on User { id }
+			fragment Unused on User { id }
+		`})
+		require.NoError(t, err)
+
+		inlined := InlineFragments(doc)
+		require.Len(t, inlined.Fragments, 3)
+		require.Equal(t, "A", inlined.Fragments[0].Name)
+		require.Equal(t, "B", inlined.Fragments[1].Name)
+		require.Equal(t, "C", inlined.Fragments[2].Name)
+	})
+
+	t.Run("keeps fragments with directives on their definition", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: `
+			{ user(id: 1) { ...Tracked ...Plain } }
+			fragment Tracked on User @tracked { id }
+			fragment Plain on User { name }
+		`})
+		require.NoError(t, err)
+
+		require.Equal(t, `query {
+  user(id: 1) {
+    ... Tracked
+    ... on User {
+      name
+    }
+  }
+}
+fragment Tracked on User @tracked {
+  id
+}
+`, format(InlineFragments(doc)))
+	})
+}
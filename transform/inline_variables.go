@@ -0,0 +1,234 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// InlineVariables returns a copy of the named operation in doc with every variable replaced by
+// its value as a literal, and no variable definitions. Fragments used by the operation are
+// rewritten the same way, other operations and unused fragments are dropped.
+//
+// variables are coerced with validator.VariableValues first, so missing variables fall back to
+// their default values and invalid ones are reported the same way as they are at execution time.
+// doc must have been validated against schema.
+func InlineVariables(schema *ast.Schema, doc *ast.QueryDocument, operationName string, variables map[string]interface{}) (*ast.QueryDocument, error) {
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return nil, gqlerror.Errorf("operation %s not found", strconv.Quote(operationName))
+	}
+
+	values, err := validator.VariableValues(schema, op, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	literals := map[string]*ast.Value{}
+	for _, def := range op.VariableDefinitions {
+		value, ok := values[def.Variable]
+		if !ok {
+			// nullable variables without a value or default are never given, so the arguments and
+			// input fields using them are removed rather than set to null, which would replace
+			// their default values
+			continue
+		}
+		literal, err := toLiteral(schema, def.Type, value)
+		if err != nil {
+			return nil, gqlerror.WrapPath(ast.Path{ast.PathName("variable"), ast.PathName(def.Variable)}, err)
+		}
+		literals[def.Variable] = literal
+	}
+
+	inline := func(v *ast.Value) (*ast.Value, bool) {
+		if v.Kind != ast.Variable {
+			return nil, false
+		}
+		literal, ok := literals[v.Raw]
+		if !ok {
+			return nil, true
+		}
+		replacement := *literal
+		replacement.Position = v.Position
+		replacement.ExpectedType = v.ExpectedType
+		replacement.Definition = v.Definition
+		return &replacement, true
+	}
+
+	inlined := *op
+	inlined.VariableDefinitions = nil
+	inlined.Directives = rewriteDirectives(op.Directives, inline)
+	inlined.SelectionSet = rewriteSelectionSet(op.SelectionSet, inline)
+
+	result := &ast.QueryDocument{
+		Operations: ast.OperationList{&inlined},
+		Position:   doc.Position,
+		Comment:    doc.Comment,
+	}
+	used := usedFragments(doc, op.SelectionSet)
+	for _, fragment := range doc.Fragments {
+		if !used[fragment.Name] {
+			continue
+		}
+		rewritten := *fragment
+		rewritten.Directives = rewriteDirectives(fragment.Directives, inline)
+		rewritten.SelectionSet = rewriteSelectionSet(fragment.SelectionSet, inline)
+		result.Fragments = append(result.Fragments, &rewritten)
+	}
+	return result, nil
+}
+
+// toLiteral converts a coerced variable value of type typ into a literal value.
+func toLiteral(schema *ast.Schema, typ *ast.Type, value interface{}) (*ast.Value, error) {
+	rv := indirect(reflect.ValueOf(value))
+	if !rv.IsValid() || ((rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil()) {
+		return &ast.Value{Kind: ast.NullValue, Raw: "null"}, nil
+	}
+
+	if typ.Elem != nil {
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			// a single value is coerced to a list of one, the same applies to literals
+			return toLiteral(schema, typ.Elem, rv.Interface())
+		}
+		list := &ast.Value{Kind: ast.ListValue}
+		for i := 0; i < rv.Len(); i++ {
+			elem, err := toLiteral(schema, typ.Elem, rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list.Children = append(list.Children, &ast.ChildValue{Value: elem})
+		}
+		return list, nil
+	}
+
+	def := schema.Types[typ.NamedType]
+	if def == nil {
+		return nil, fmt.Errorf("unknown type %s", typ.NamedType)
+	}
+
+	switch def.Kind {
+	case ast.Enum:
+		name := fmt.Sprint(rv.Interface())
+		if enumValue := def.EnumValues.ForName(name); enumValue != nil {
+			return &ast.Value{Kind: ast.EnumValue, Raw: enumValue.Name}, nil
+		}
+		return nil, fmt.Errorf("%s is not a valid %s", name, def.Name)
+
+	case ast.InputObject:
+		if rv.Kind() != reflect.Map {
+			return nil, fmt.Errorf("must be a %s, not a %s", def.Name, rv.Kind())
+		}
+		// fields are written in the order the input object declares them
+		object := &ast.Value{Kind: ast.ObjectValue}
+		for _, field := range def.Fields {
+			fieldValue := rv.MapIndex(reflect.ValueOf(field.Name))
+			if !fieldValue.IsValid() {
+				continue
+			}
+			child, err := toLiteral(schema, field.Type, fieldValue.Interface())
+			if err != nil {
+				return nil, err
+			}
+			object.Children = append(object.Children, &ast.ChildValue{Name: field.Name, Value: child})
+		}
+		return object, nil
+	}
+
+	switch typ.NamedType {
+	case "Int":
+		return &ast.Value{Kind: ast.IntValue, Raw: formatNumber(rv, true)}, nil
+	case "Float":
+		return &ast.Value{Kind: ast.FloatValue, Raw: formatNumber(rv, false)}, nil
+	case "ID":
+		if rv.Kind() == reflect.String {
+			return &ast.Value{Kind: ast.StringValue, Raw: rv.String()}, nil
+		}
+		return &ast.Value{Kind: ast.IntValue, Raw: formatNumber(rv, true)}, nil
+	}
+	return goLiteral(rv), nil
+}
+
+// goLiteral converts a value of a custom scalar into a literal based on its Go type.
+func goLiteral(rv reflect.Value) *ast.Value {
+	rv = indirect(rv)
+	if !rv.IsValid() {
+		return &ast.Value{Kind: ast.NullValue, Raw: "null"}
+	}
+
+	if n, ok := rv.Interface().(json.Number); ok {
+		if _, err := n.Int64(); err == nil {
+			return &ast.Value{Kind: ast.IntValue, Raw: n.String()}
+		}
+		return &ast.Value{Kind: ast.FloatValue, Raw: n.String()}
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return &ast.Value{Kind: ast.BooleanValue, Raw: strconv.FormatBool(rv.Bool())}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &ast.Value{Kind: ast.IntValue, Raw: formatNumber(rv, true)}
+	case reflect.Float32, reflect.Float64:
+		return &ast.Value{Kind: ast.FloatValue, Raw: formatNumber(rv, false)}
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return &ast.Value{Kind: ast.NullValue, Raw: "null"}
+		}
+		list := &ast.Value{Kind: ast.ListValue}
+		for i := 0; i < rv.Len(); i++ {
+			list.Children = append(list.Children, &ast.ChildValue{Value: goLiteral(rv.Index(i))})
+		}
+		return list
+	case reflect.Map:
+		if rv.IsNil() {
+			return &ast.Value{Kind: ast.NullValue, Raw: "null"}
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		object := &ast.Value{Kind: ast.ObjectValue}
+		for _, key := range keys {
+			object.Children = append(object.Children, &ast.ChildValue{
+				Name:  fmt.Sprint(key.Interface()),
+				Value: goLiteral(rv.MapIndex(key)),
+			})
+		}
+		return object
+	}
+	return &ast.Value{Kind: ast.StringValue, Raw: fmt.Sprint(rv.Interface())}
+}
+
+// indirect follows pointers and interfaces, returning the zero Value for nil.
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// formatNumber formats an Int or Float value, which may have been given as a number of any kind
+// or as a string holding a number.
+func formatNumber(rv reflect.Value, integer bool) string {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		if integer {
+			return strconv.FormatInt(int64(rv.Float()), 10)
+		}
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
+	}
+	return fmt.Sprint(rv.Interface())
+}
@@ -0,0 +1,81 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestInlineVariables(t *testing.T) {
+	doc := gqlparser.MustLoadQuery(testSchema, `
+		query Users($first: Int = 10, $role: Role, $filter: UserFilter, $friends: Int!, $withEmail: Boolean!) {
+			users(first: $first, role: $role, filter: $filter) {
+				...UserFields
+			}
+		}
+		query Other { user(id: 1) { id } }
+		fragment UserFields on User {
+			name
+			email @include(if: $withEmail)
+			friends(first: $friends) { id }
+		}
+	`)
+	before := format(doc)
+
+	var variables map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"role": "ADMIN",
+		"filter": {"roles": "MEMBER", "name": "a \"quoted\" name"},
+		"friends": 5,
+		"withEmail": false
+	}`), &variables))
+
+	inlined, err := InlineVariables(testSchema, doc, "Users", variables)
+	require.NoError(t, err)
+	require.Equal(t, `query Users {
+  users(first: 10, role: ADMIN, filter: {name:"a \"quoted\" name",roles:[MEMBER]}) {
+    ... UserFields
+  }
+}
+fragment UserFields on User {
+  name
+  email @include(if: false)
+  friends(first: 5) {
+    id
+  }
+}
+`, format(inlined))
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+
+	t.Run("missing nullable variables are left out", func(t *testing.T) {
+		inlined, err := InlineVariables(testSchema, doc, "Users", map[string]interface{}{"friends": 1, "withEmail": true})
+		require.NoError(t, err)
+		require.Contains(t, format(inlined), "users(first: 10) {")
+
+		doc := gqlparser.MustLoadQuery(testSchema, `
+			query Filtered($name: String) {
+				users(filter: {name: $name, roles: [ADMIN]}) { id }
+			}
+		`)
+		inlined, err = InlineVariables(testSchema, doc, "Filtered", nil)
+		require.NoError(t, err)
+		require.Contains(t, format(inlined), "users(filter: {roles:[ADMIN]}) {")
+	})
+
+	t.Run("invalid variables", func(t *testing.T) {
+		_, err := InlineVariables(testSchema, doc, "Users", map[string]interface{}{"withEmail": true})
+		require.EqualError(t, err, "input: variable.friends must be defined")
+		require.ErrorIs(t, err, gqlerror.ErrValidation)
+
+		_, err = InlineVariables(testSchema, doc, "Users", map[string]interface{}{"friends": 1, "withEmail": true, "role": "admin"})
+		require.EqualError(t, err, "input: variable.role admin is not a valid Role")
+	})
+
+	t.Run("unknown operation", func(t *testing.T) {
+		_, err := InlineVariables(testSchema, doc, "Nope", nil)
+		require.EqualError(t, err, `input: operation "Nope" not found`)
+	})
+}
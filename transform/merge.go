@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// MergeSelections returns a copy of doc where no selection set asks for the same thing twice.
+//
+// Fields with the same response key, arguments and directives are merged into the first of them,
+// as are inline fragments with the same type condition and directives, and repeated fragment
+// spreads are dropped. The selections of everything merged are then merged recursively.
+// Arguments are compared regardless of their order.
+//
+// Inline fragments without directives are replaced by their selections when they have no type
+// condition, or, once doc has been validated, when the type condition is the type they are
+// spread into.
+func MergeSelections(doc *ast.QueryDocument) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	for _, op := range doc.Operations {
+		merged := *op
+		merged.SelectionSet = mergeSelectionSet(op.SelectionSet)
+		result.Operations = append(result.Operations, &merged)
+	}
+	for _, fragment := range doc.Fragments {
+		merged := *fragment
+		merged.SelectionSet = mergeSelectionSet(fragment.SelectionSet)
+		result.Fragments = append(result.Fragments, &merged)
+	}
+	return result
+}
+
+func mergeSelectionSet(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	index := map[string]int{}
+	for _, sel := range flattenSelections(nil, set) {
+		key := mergeKey(sel)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(result)
+		}
+
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if seen {
+				field := result[i].(*ast.Field)
+				field.SelectionSet = append(field.SelectionSet, sel.SelectionSet...)
+				continue
+			}
+			field := *sel
+			field.SelectionSet = append(ast.SelectionSet(nil), sel.SelectionSet...)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			if seen {
+				fragment := result[i].(*ast.InlineFragment)
+				fragment.SelectionSet = append(fragment.SelectionSet, sel.SelectionSet...)
+				continue
+			}
+			fragment := *sel
+			fragment.SelectionSet = append(ast.SelectionSet(nil), sel.SelectionSet...)
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			if !seen {
+				result = append(result, sel)
+			}
+		}
+	}
+
+	for _, sel := range result {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.SelectionSet != nil {
+				sel.SelectionSet = mergeSelectionSet(sel.SelectionSet)
+			}
+		case *ast.InlineFragment:
+			sel.SelectionSet = mergeSelectionSet(sel.SelectionSet)
+		}
+	}
+	return result
+}
+
+// flattenSelections appends set to result, replacing inline fragments that select nothing more
+// than their parent by their selections.
+func flattenSelections(result ast.SelectionSet, set ast.SelectionSet) ast.SelectionSet {
+	for _, sel := range set {
+		if fragment, ok := sel.(*ast.InlineFragment); ok && len(fragment.Directives) == 0 &&
+			(fragment.TypeCondition == "" || fragment.ObjectDefinition != nil && fragment.ObjectDefinition.Name == fragment.TypeCondition) {
+			result = flattenSelections(result, fragment.SelectionSet)
+			continue
+		}
+		result = append(result, sel)
+	}
+	return result
+}
+
+// mergeKey returns the printed form of sel without its selection set, with arguments in
+// canonical order. Selections with the same key can be merged.
+func mergeKey(sel ast.Selection) string {
+	var c canonicalizer
+	var p printer
+	switch sel := sel.(type) {
+	case *ast.Field:
+		p.selection(&ast.Field{
+			Alias:      sel.Alias,
+			Name:       sel.Name,
+			Arguments:  c.arguments(sel.Arguments),
+			Directives: c.directives(sel.Directives),
+		})
+	case *ast.InlineFragment:
+		p.selection(&ast.InlineFragment{
+			TypeCondition: sel.TypeCondition,
+			Directives:    c.directives(sel.Directives),
+		})
+	case *ast.FragmentSpread:
+		p.selection(&ast.FragmentSpread{
+			Name:       sel.Name,
+			Directives: c.directives(sel.Directives),
+		})
+	}
+	return p.buf.String()
+}
@@ -0,0 +1,70 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestMergeSelections(t *testing.T) {
+	merged := func(t *testing.T, query string) string {
+		t.Helper()
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		before := format(doc)
+		result := Print(MergeSelections(doc))
+		require.Equal(t, before, format(doc), "the input document must not be modified")
+		return result
+	}
+
+	t.Run("merges fields", func(t *testing.T) {
+		require.Equal(t,
+			`{user(id:1 first:2){id friends{id name}name}other:user(id:1){id}}`,
+			merged(t, `{
+				user(id: 1, first: 2) { id friends { id } }
+				other: user(id: 1) { id }
+				user(first: 2, id: 1) { name id friends { name } }
+			}`),
+		)
+	})
+
+	t.Run("keeps fields with different directives apart", func(t *testing.T) {
+		require.Equal(t,
+			`{a@include(if:$x){b c}a@include(if:$y){b}}`,
+			merged(t, `{ a @include(if: $x) { b } a @include(if: $y) { b } a @include(if: $x) { c b } }`),
+		)
+	})
+
+	t.Run("merges inline fragments and spreads", func(t *testing.T) {
+		require.Equal(t,
+			`{node{id...on User{name email}...on Post{title}...F...F@skip(if:true)}}`,
+			merged(t, `{ node {
+				id
+				... on User { name }
+				... on Post { title }
+				...F
+				... on User { email name }
+				...F
+				... { id }
+				...F @skip(if: true)
+			} }`),
+		)
+	})
+
+	t.Run("flattens fragments on the parent type", func(t *testing.T) {
+		doc := gqlparser.MustLoadQuery(testSchema, `{
+			user(id: 1) {
+				id
+				... on User { name ... on Node { id } }
+				... on User @include(if: true) { email }
+			}
+		}`)
+		require.Equal(t,
+			`{user(id:1){id name...on Node{id}...on User@include(if:true){email}}}`,
+			Print(MergeSelections(doc)),
+		)
+	})
+}
@@ -0,0 +1,221 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Print returns doc in its most compact form, without comments, commas or any whitespace other
+// than the single spaces needed between adjacent names and numbers, eg
+// `query Q($id:ID!){user(id:$id){name}}`. Operations are printed before fragments, both in the
+// order they appear in doc.
+//
+// An anonymous query without variables or directives is printed in its shorthand form.
+func Print(doc *ast.QueryDocument) string {
+	var p printer
+	for _, op := range doc.Operations {
+		p.operation(op)
+	}
+	for _, fragment := range doc.Fragments {
+		p.fragment(fragment)
+	}
+	return p.buf.String()
+}
+
+type printer struct {
+	buf strings.Builder
+	// word is set when the last token written was a name or a number, which must be separated
+	// from a following name or number.
+	word bool
+}
+
+func (p *printer) punct(s string) {
+	p.buf.WriteString(s)
+	p.word = false
+}
+
+func (p *printer) name(s string) {
+	if p.word {
+		p.buf.WriteByte(' ')
+	}
+	p.buf.WriteString(s)
+	p.word = true
+}
+
+func (p *printer) operation(op *ast.OperationDefinition) {
+	if op.Operation != ast.Query || op.Name != "" || len(op.VariableDefinitions) != 0 || len(op.Directives) != 0 {
+		p.name(string(op.Operation))
+		if op.Name != "" {
+			p.name(op.Name)
+		}
+		p.variableDefinitions(op.VariableDefinitions)
+		p.directives(op.Directives)
+	}
+	p.selectionSet(op.SelectionSet)
+}
+
+func (p *printer) fragment(fragment *ast.FragmentDefinition) {
+	p.name("fragment")
+	p.name(fragment.Name)
+	p.variableDefinitions(fragment.VariableDefinition)
+	p.name("on")
+	p.name(fragment.TypeCondition)
+	p.directives(fragment.Directives)
+	p.selectionSet(fragment.SelectionSet)
+}
+
+func (p *printer) variableDefinitions(defs ast.VariableDefinitionList) {
+	if len(defs) == 0 {
+		return
+	}
+	p.punct("(")
+	for _, def := range defs {
+		p.punct("$")
+		p.name(def.Variable)
+		p.punct(":")
+		p.typ(def.Type)
+		if def.DefaultValue != nil {
+			p.punct("=")
+			p.value(def.DefaultValue)
+		}
+		p.directives(def.Directives)
+	}
+	p.punct(")")
+}
+
+func (p *printer) typ(t *ast.Type) {
+	if t.Elem != nil {
+		p.punct("[")
+		p.typ(t.Elem)
+		p.punct("]")
+	} else {
+		p.name(t.NamedType)
+	}
+	if t.NonNull {
+		p.punct("!")
+	}
+}
+
+func (p *printer) directives(directives ast.DirectiveList) {
+	for _, dir := range directives {
+		p.punct("@")
+		p.name(dir.Name)
+		p.arguments(dir.Arguments)
+	}
+}
+
+func (p *printer) arguments(args ast.ArgumentList) {
+	if len(args) == 0 {
+		return
+	}
+	p.punct("(")
+	for _, arg := range args {
+		p.name(arg.Name)
+		p.punct(":")
+		p.value(arg.Value)
+	}
+	p.punct(")")
+}
+
+func (p *printer) selectionSet(set ast.SelectionSet) {
+	if len(set) == 0 {
+		return
+	}
+	p.punct("{")
+	for _, sel := range set {
+		p.selection(sel)
+	}
+	p.punct("}")
+}
+
+func (p *printer) selection(sel ast.Selection) {
+	switch sel := sel.(type) {
+	case *ast.Field:
+		if sel.Alias != "" && sel.Alias != sel.Name {
+			p.name(sel.Alias)
+			p.punct(":")
+		}
+		p.name(sel.Name)
+		p.arguments(sel.Arguments)
+		p.directives(sel.Directives)
+		p.selectionSet(sel.SelectionSet)
+
+	case *ast.FragmentSpread:
+		p.punct("...")
+		p.name(sel.Name)
+		p.directives(sel.Directives)
+
+	case *ast.InlineFragment:
+		p.punct("...")
+		if sel.TypeCondition != "" {
+			p.name("on")
+			p.name(sel.TypeCondition)
+		}
+		p.directives(sel.Directives)
+		p.selectionSet(sel.SelectionSet)
+
+	default:
+		panic(fmt.Errorf("unknown Selection type: %T", sel))
+	}
+}
+
+func (p *printer) value(v *ast.Value) {
+	switch v.Kind {
+	case ast.Variable:
+		p.punct("$")
+		p.name(v.Raw)
+	case ast.StringValue, ast.BlockValue:
+		p.punct(quote(v.Raw))
+	case ast.ListValue:
+		p.punct("[")
+		for _, child := range v.Children {
+			p.value(child.Value)
+		}
+		p.punct("]")
+	case ast.ObjectValue:
+		p.punct("{")
+		for _, child := range v.Children {
+			p.name(child.Name)
+			p.punct(":")
+			p.value(child.Value)
+		}
+		p.punct("}")
+	default:
+		p.name(v.Raw)
+	}
+}
+
+// quote returns s as a GraphQL string literal. Unlike strconv.Quote it only uses escape sequences
+// that GraphQL understands.
+func quote(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+				fmt.Fprintf(&buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
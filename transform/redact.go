@@ -0,0 +1,211 @@
+package transform
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// RedactOptions selects the selections removed by Redact. A selection is removed when it matches
+// any of them.
+type RedactOptions struct {
+	// Paths are dot separated lists of field names leading from the root of an operation to the
+	// field to remove, eg "user.password". Aliases are ignored so they can't be used to get
+	// around a path.
+	Paths []string
+	// Fields are field names, removed wherever they are selected, or field coordinates like
+	// "User.password" which only match the field on that type. Coordinates need doc to have
+	// been validated.
+	Fields []string
+	// Directive is the name of a marker directive, eg "internal". Fields whose definition carries
+	// it and selections that carry it themselves are removed.
+	Directive string
+}
+
+// Redacted describes a selection removed by Redact.
+type Redacted struct {
+	// Definition is the name of the operation or fragment the selection was removed from.
+	Definition string
+	// Path is the response path of the selection within Definition.
+	Path      ast.Path
+	Selection ast.Selection
+}
+
+// Redact returns a copy of doc without the selections matching options, along with a report of
+// everything that was removed, for gateways that must strip private fields before forwarding a
+// query.
+//
+// Fields, inline fragments and fragment spreads that end up with nothing left to select are
+// removed and reported too. Operations left with nothing to select are dropped, as they would be
+// invalid. Variables and fragments that are no longer used are kept, see RemoveUnused.
+//
+// Fragments may be spread at different paths, so when options holds paths the fragments in doc
+// are inlined first, see InlineFragments.
+func Redact(doc *ast.QueryDocument, options RedactOptions) (*ast.QueryDocument, []Redacted) {
+	if len(options.Paths) != 0 {
+		doc = InlineFragments(doc)
+	}
+
+	r := redactor{
+		doc:       doc,
+		directive: options.Directive,
+		paths:     map[string]bool{},
+		fields:    map[string]bool{},
+		fragments: map[string]*ast.FragmentDefinition{},
+		done:      map[string]bool{},
+	}
+	for _, path := range options.Paths {
+		r.paths[path] = true
+	}
+	for _, field := range options.Fields {
+		r.fields[field] = true
+	}
+
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	for _, op := range doc.Operations {
+		redacted := *op
+		redacted.SelectionSet = r.selectionSet(scope{definition: op.Name, rooted: true}, op.SelectionSet)
+		if len(redacted.SelectionSet) == 0 {
+			continue
+		}
+		result.Operations = append(result.Operations, &redacted)
+	}
+	for _, fragment := range doc.Fragments {
+		if redacted := r.fragment(fragment.Name); redacted != nil {
+			result.Fragments = append(result.Fragments, redacted)
+		}
+	}
+	return result, r.redacted
+}
+
+type redactor struct {
+	doc       *ast.QueryDocument
+	directive string
+	paths     map[string]bool
+	fields    map[string]bool
+
+	// fragments holds the redacted fragments, or nil for fragments with nothing left to select
+	fragments map[string]*ast.FragmentDefinition
+	// done holds the fragments that have been or are being redacted
+	done     map[string]bool
+	redacted []Redacted
+}
+
+// scope is the position of a selection set within the document.
+type scope struct {
+	definition string
+	path       ast.Path
+	// names holds the field names leading to the selection set when rooted, ie when it is part of
+	// an operation rather than of a fragment.
+	names  []string
+	rooted bool
+}
+
+func (s scope) field(field *ast.Field) scope {
+	key := field.Alias
+	if key == "" {
+		key = field.Name
+	}
+	return scope{
+		definition: s.definition,
+		path:       append(s.path[:len(s.path):len(s.path)], ast.PathName(key)),
+		names:      append(s.names[:len(s.names):len(s.names)], field.Name),
+		rooted:     s.rooted,
+	}
+}
+
+// fragment returns the redacted copy of the named fragment.
+func (r *redactor) fragment(name string) *ast.FragmentDefinition {
+	if r.done[name] {
+		return r.fragments[name]
+	}
+	r.done[name] = true
+
+	def := r.doc.Fragments.ForName(name)
+	if def == nil {
+		return nil
+	}
+	redacted := *def
+	// the fragment counts as not empty while it is being redacted, for spreads that recurse.
+	r.fragments[name] = &redacted
+	redacted.SelectionSet = r.selectionSet(scope{definition: name}, def.SelectionSet)
+	if len(redacted.SelectionSet) == 0 {
+		r.fragments[name] = nil
+		return nil
+	}
+	return &redacted
+}
+
+func (r *redactor) selectionSet(s scope, set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			fs := s.field(sel)
+			if r.matchField(fs, sel) {
+				r.report(fs, sel)
+				continue
+			}
+			field := *sel
+			field.SelectionSet = r.selectionSet(fs, sel.SelectionSet)
+			if len(sel.SelectionSet) != 0 && len(field.SelectionSet) == 0 {
+				r.report(fs, sel)
+				continue
+			}
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			if r.marked(sel.Directives) {
+				r.report(s, sel)
+				continue
+			}
+			fragment := *sel
+			fragment.SelectionSet = r.selectionSet(s, sel.SelectionSet)
+			if len(fragment.SelectionSet) == 0 {
+				r.report(s, sel)
+				continue
+			}
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			if r.marked(sel.Directives) || (r.doc.Fragments.ForName(sel.Name) != nil && r.fragment(sel.Name) == nil) {
+				r.report(s, sel)
+				continue
+			}
+			result = append(result, sel)
+		}
+	}
+	return result
+}
+
+func (r *redactor) matchField(s scope, field *ast.Field) bool {
+	if r.fields[field.Name] || r.marked(field.Directives) {
+		return true
+	}
+	if field.ObjectDefinition != nil && r.fields[field.ObjectDefinition.Name+"."+field.Name] {
+		return true
+	}
+	if field.Definition != nil && r.marked(field.Definition.Directives) {
+		return true
+	}
+	return s.rooted && r.paths[strings.Join(s.names, ".")]
+}
+
+func (r *redactor) marked(directives ast.DirectiveList) bool {
+	return r.directive != "" && directives.ForName(r.directive) != nil
+}
+
+func (r *redactor) report(s scope, sel ast.Selection) {
+	r.redacted = append(r.redacted, Redacted{
+		Definition: s.definition,
+		Path:       s.path,
+		Selection:  sel,
+	})
+}
@@ -0,0 +1,120 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+var redactSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		node(id: ID!): Node
+		user(id: ID!): User
+		users: [User!]!
+	}
+	interface Node {
+		id: ID!
+	}
+	type User implements Node {
+		id: ID!
+		name: String
+		email: String
+		password: String @internal
+		friends(first: Int): [User!]!
+	}
+	type Post implements Node {
+		id: ID!
+		title: String
+	}
+	directive @internal on FIELD_DEFINITION | FIELD | INLINE_FRAGMENT | FRAGMENT_SPREAD
+`})
+
+func TestRedact(t *testing.T) {
+	doc := gqlparser.MustLoadQuery(redactSchema, `
+		query Q {
+			me: user(id: 1) { id secret: password ...Private }
+			users { name email ... on User @internal { id } }
+			node(id: 1) { ... on User { password } ... on Post { title } }
+		}
+		query P { user(id: 2) { ...Public } }
+		fragment Private on User { password }
+		fragment Public on User { name friends(first: 1) { email } }
+	`)
+	before := format(doc)
+
+	t.Run("marker directive", func(t *testing.T) {
+		redacted, report := Redact(doc, RedactOptions{Directive: "internal"})
+		require.Equal(t,
+			`query Q{me:user(id:1){id}users{name email}node(id:1){...on Post{title}}}query P{user(id:2){...Public}}fragment Public on User{name friends(first:1){email}}`,
+			Print(redacted),
+		)
+		require.Equal(t, []string{
+			"Q me.secret",
+			"Private password",
+			"Q me ...Private",
+			"Q users ...on User@internal{id}",
+			"Q node.password",
+			"Q node ...on User{password}",
+		}, summarize(report))
+		require.Equal(t, before, format(doc), "the input document must not be modified")
+	})
+
+	t.Run("fields and coordinates", func(t *testing.T) {
+		redacted, report := Redact(doc, RedactOptions{Fields: []string{"email", "Post.title"}})
+		require.Equal(t,
+			`query Q{me:user(id:1){id secret:password...Private}users{name...on User@internal{id}}node(id:1){...on User{password}}}query P{user(id:2){...Public}}`+
+				`fragment Private on User{password}fragment Public on User{name}`,
+			Print(redacted),
+		)
+		require.Equal(t, []string{
+			"Q users.email",
+			"Q node.title",
+			"Q node ...on Post{title}",
+			"Public friends.email",
+			"Public friends",
+		}, summarize(report))
+	})
+
+	t.Run("paths follow fragments and ignore aliases", func(t *testing.T) {
+		redacted, report := Redact(doc, RedactOptions{Paths: []string{"user.password", "users.email"}})
+		require.Equal(t,
+			`query Q{me:user(id:1){id}users{name...on User@internal{id}}node(id:1){...on User{password}...on Post{title}}}`+
+				`query P{user(id:2){name friends(first:1){email}}}`,
+			Print(RemoveUnused(redacted)),
+		)
+		require.Equal(t, []string{
+			"Q me.secret",
+			"Q me.password",
+			"Q users.email",
+		}, summarize(report))
+	})
+
+	t.Run("operations with nothing left are dropped", func(t *testing.T) {
+		doc := gqlparser.MustLoadQuery(redactSchema, `
+			query Secret { user(id: 1) { password } }
+			query Name { user(id: 1) { name } }
+		`)
+		redacted, report := Redact(doc, RedactOptions{Fields: []string{"password"}})
+		require.Equal(t, `query Name{user(id:1){name}}`, Print(redacted))
+		require.Equal(t, []string{
+			"Secret user.password",
+			"Secret user",
+		}, summarize(report))
+	})
+}
+
+func summarize(report []Redacted) []string {
+	var result []string
+	for _, r := range report {
+		var p printer
+		if _, ok := r.Selection.(*ast.Field); ok {
+			result = append(result, r.Definition+" "+r.Path.String())
+			continue
+		}
+		p.selection(r.Selection)
+		result = append(result, r.Definition+" "+r.Path.String()+" "+p.buf.String())
+	}
+	return result
+}
@@ -0,0 +1,153 @@
+package transform
+
+import (
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// RenameOperation returns a copy of doc with the operation named from renamed to to. An empty
+// from renames the only operation in doc, an empty to makes the operation anonymous.
+func RenameOperation(doc *ast.QueryDocument, from string, to string) (*ast.QueryDocument, error) {
+	op := doc.Operations.ForName(from)
+	if op == nil {
+		return nil, gqlerror.Errorf("operation %s not found", strconv.Quote(from))
+	}
+	if to != "" && to != op.Name && doc.Operations.ForName(to) != nil {
+		return nil, gqlerror.Errorf("operation %s already exists", strconv.Quote(to))
+	}
+
+	result := *doc
+	result.Operations = make(ast.OperationList, len(doc.Operations))
+	for i, it := range doc.Operations {
+		if it == op {
+			renamed := *op
+			renamed.Name = to
+			it = &renamed
+		}
+		result.Operations[i] = it
+	}
+	return &result, nil
+}
+
+// PrefixAliases returns a copy of doc with prefix added to every alias, in operations and
+// fragments alike. Fields without an alias are left alone.
+func PrefixAliases(doc *ast.QueryDocument, prefix string) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	for _, op := range doc.Operations {
+		prefixed := *op
+		prefixed.SelectionSet = prefixAliases(op.SelectionSet, prefix)
+		result.Operations = append(result.Operations, &prefixed)
+	}
+	for _, fragment := range doc.Fragments {
+		prefixed := *fragment
+		prefixed.SelectionSet = prefixAliases(fragment.SelectionSet, prefix)
+		result.Fragments = append(result.Fragments, &prefixed)
+	}
+	return result
+}
+
+func prefixAliases(set ast.SelectionSet, prefix string) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			if field.Alias != "" && field.Alias != field.Name {
+				field.Alias = prefix + field.Alias
+			}
+			field.SelectionSet = prefixAliases(sel.SelectionSet, prefix)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			fragment.SelectionSet = prefixAliases(sel.SelectionSet, prefix)
+			result = append(result, &fragment)
+
+		default:
+			result = append(result, sel)
+		}
+	}
+	return result
+}
+
+// NamespaceFields returns a copy of doc where every root field of every operation is aliased to
+// namespace followed by its response key, eg `user` becomes `ns_user: user` for the namespace
+// "ns_". Gateways batching several operations into one use this to keep their responses apart.
+//
+// Fragments spread directly in a root selection set are replaced by inline fragments, so that
+// their fields can be renamed without affecting other spreads of the same fragment. Fragments
+// that are no longer spread anywhere are dropped.
+func NamespaceFields(doc *ast.QueryDocument, namespace string) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	used := map[string]bool{}
+	for _, op := range doc.Operations {
+		namespaced := *op
+		namespaced.SelectionSet = namespaceFields(doc, op.SelectionSet, namespace, map[string]bool{})
+		result.Operations = append(result.Operations, &namespaced)
+		for name := range usedFragments(doc, namespaced.SelectionSet) {
+			used[name] = true
+		}
+	}
+	for _, fragment := range doc.Fragments {
+		if used[fragment.Name] {
+			result.Fragments = append(result.Fragments, fragment)
+		}
+	}
+	return result
+}
+
+// namespaceFields renames the fields in the root selection set set. path holds the fragments
+// being inlined, to stop at cycles.
+func namespaceFields(doc *ast.QueryDocument, set ast.SelectionSet, namespace string, path map[string]bool) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			if field.Alias == "" {
+				field.Alias = field.Name
+			}
+			field.Alias = namespace + field.Alias
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			fragment.SelectionSet = namespaceFields(doc, sel.SelectionSet, namespace, path)
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			def := doc.Fragments.ForName(sel.Name)
+			if def == nil || path[sel.Name] {
+				result = append(result, sel)
+				continue
+			}
+			path[sel.Name] = true
+			result = append(result, &ast.InlineFragment{
+				TypeCondition:    def.TypeCondition,
+				Directives:       sel.Directives,
+				SelectionSet:     namespaceFields(doc, def.SelectionSet, namespace, path),
+				ObjectDefinition: sel.ObjectDefinition,
+				Position:         sel.Position,
+				Comment:          sel.Comment,
+			})
+			delete(path, sel.Name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestRenameOperation(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `query A { a } query B { b }`})
+	require.NoError(t, err)
+
+	renamed, err := RenameOperation(doc, "A", "C")
+	require.NoError(t, err)
+	require.Equal(t, `query C{a}query B{b}`, Print(renamed))
+	require.Equal(t, "A", doc.Operations[0].Name)
+
+	_, err = RenameOperation(doc, "A", "B")
+	require.EqualError(t, err, `input: operation "B" already exists`)
+
+	_, err = RenameOperation(doc, "X", "Y")
+	require.EqualError(t, err, `input: operation "X" not found`)
+
+	single, err := parser.ParseQuery(&ast.Source{Input: `{ a }`})
+	require.NoError(t, err)
+	renamed, err = RenameOperation(single, "", "Named")
+	require.NoError(t, err)
+	require.Equal(t, `query Named{a}`, Print(renamed))
+}
+
+func TestPrefixAliases(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		{ me: user(id: 1) { id n: name ... on User { f: friends { id } } ...F } }
+		fragment F on User { e: email name }
+	`})
+	require.NoError(t, err)
+	before := format(doc)
+
+	require.Equal(t,
+		`{p_me:user(id:1){id p_n:name...on User{p_f:friends{id}}...F}}fragment F on User{p_e:email name}`,
+		Print(PrefixAliases(doc, "p_")),
+	)
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+}
+
+func TestNamespaceFields(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query A { me: user(id: 1) { ...U } ...Root ... on Query { version } }
+		fragment Root on Query { users { ...U } }
+		fragment U on User { name }
+	`})
+	require.NoError(t, err)
+	before := format(doc)
+
+	require.Equal(t,
+		`query A{ns_me:user(id:1){...U}...on Query{ns_users:users{...U}}...on Query{ns_version:version}}fragment U on User{name}`,
+		Print(NamespaceFields(doc, "ns_")),
+	)
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+}
@@ -0,0 +1,156 @@
+package transform
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// valueFunc returns the replacement for a value, or false to keep it and rewrite its children. A
+// nil replacement removes the argument or object field holding the value, and makes list items
+// null, like a variable that wasn't given.
+type valueFunc func(v *ast.Value) (*ast.Value, bool)
+
+// rewriteSelectionSet copies set, rewriting every argument value in it with fn.
+func rewriteSelectionSet(set ast.SelectionSet, fn valueFunc) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			field.Arguments = rewriteArguments(sel.Arguments, fn)
+			field.Directives = rewriteDirectives(sel.Directives, fn)
+			field.SelectionSet = rewriteSelectionSet(sel.SelectionSet, fn)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			fragment.Directives = rewriteDirectives(sel.Directives, fn)
+			fragment.SelectionSet = rewriteSelectionSet(sel.SelectionSet, fn)
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			spread := *sel
+			spread.Directives = rewriteDirectives(sel.Directives, fn)
+			result = append(result, &spread)
+		}
+	}
+	return result
+}
+
+func rewriteDirectives(directives ast.DirectiveList, fn valueFunc) ast.DirectiveList {
+	if directives == nil {
+		return nil
+	}
+
+	result := make(ast.DirectiveList, len(directives))
+	for i, dir := range directives {
+		rewritten := *dir
+		rewritten.Arguments = rewriteArguments(dir.Arguments, fn)
+		result[i] = &rewritten
+	}
+	return result
+}
+
+func rewriteArguments(args ast.ArgumentList, fn valueFunc) ast.ArgumentList {
+	if args == nil {
+		return nil
+	}
+
+	result := make(ast.ArgumentList, 0, len(args))
+	for _, arg := range args {
+		rewritten := *arg
+		rewritten.Value = rewriteValue(arg.Value, fn)
+		if rewritten.Value == nil && arg.Value != nil {
+			continue
+		}
+		result = append(result, &rewritten)
+	}
+	return result
+}
+
+// rewriteValue applies fn to v and, unless fn replaced it, to each of its children. Values are
+// only copied when something inside them changed.
+func rewriteValue(v *ast.Value, fn valueFunc) *ast.Value {
+	if v == nil {
+		return nil
+	}
+	if replacement, ok := fn(v); ok {
+		return replacement
+	}
+	if v.Kind != ast.ListValue && v.Kind != ast.ObjectValue {
+		return v
+	}
+
+	var children ast.ChildValueList
+	for i, child := range v.Children {
+		value := rewriteValue(child.Value, fn)
+		if value == child.Value && children == nil {
+			continue
+		}
+		if children == nil {
+			children = make(ast.ChildValueList, i, len(v.Children))
+			copy(children, v.Children)
+		}
+		if value == nil {
+			if v.Kind == ast.ObjectValue {
+				continue
+			}
+			value = &ast.Value{Kind: ast.NullValue, Raw: "null", Position: child.Value.Position}
+		}
+		rewritten := *child
+		rewritten.Value = value
+		children = append(children, &rewritten)
+	}
+	if children == nil {
+		return v
+	}
+
+	rewritten := *v
+	rewritten.Children = children
+	return &rewritten
+}
+
+// usedFragments returns the names of all fragments reachable from set.
+func usedFragments(doc *ast.QueryDocument, set ast.SelectionSet) map[string]bool {
+	used := map[string]bool{}
+	var visit func(set ast.SelectionSet)
+	visit = func(set ast.SelectionSet) {
+		for _, sel := range set {
+			switch sel := sel.(type) {
+			case *ast.Field:
+				visit(sel.SelectionSet)
+			case *ast.InlineFragment:
+				visit(sel.SelectionSet)
+			case *ast.FragmentSpread:
+				if used[sel.Name] {
+					continue
+				}
+				used[sel.Name] = true
+				if def := doc.Fragments.ForName(sel.Name); def != nil {
+					visit(def.SelectionSet)
+				}
+			}
+		}
+	}
+	visit(set)
+	return used
+}
+
+// operationDocument returns a document holding op and the fragments it uses, in the order they
+// appear in doc.
+func operationDocument(doc *ast.QueryDocument, op *ast.OperationDefinition) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Operations: ast.OperationList{op},
+		Position:   doc.Position,
+	}
+	used := usedFragments(doc, op.SelectionSet)
+	for _, fragment := range doc.Fragments {
+		if used[fragment.Name] {
+			result.Fragments = append(result.Fragments, fragment)
+		}
+	}
+	return result
+}
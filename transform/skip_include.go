@@ -0,0 +1,150 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// EvaluateSkipInclude returns a copy of the named operation in doc as it will be executed with
+// the given variables: selections excluded by @skip or @include are removed and the directives
+// are dropped from the selections that remain. Fragments and variable definitions that are no
+// longer used are dropped along with other operations in doc.
+//
+// Variables missing from variables take their default values. A selection set left with nothing
+// to select is replaced by __typename, since selection sets can't be empty.
+func EvaluateSkipInclude(doc *ast.QueryDocument, operationName string, variables map[string]interface{}) (*ast.QueryDocument, error) {
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return nil, gqlerror.Errorf("operation %s not found", strconv.Quote(operationName))
+	}
+
+	vars := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		vars[name] = value
+	}
+	for _, def := range op.VariableDefinitions {
+		if _, ok := vars[def.Variable]; ok || def.DefaultValue == nil {
+			continue
+		}
+		value, err := def.DefaultValue.Value(nil)
+		if err != nil {
+			return nil, gqlerror.WrapPath(ast.Path{ast.PathName("variable"), ast.PathName(def.Variable)}, err)
+		}
+		vars[def.Variable] = value
+	}
+
+	e := evaluator{vars: vars}
+	evaluated := *op
+	evaluated.Directives = e.directives(op.Directives)
+	evaluated.SelectionSet = e.selectionSet(op.SelectionSet)
+
+	result := operationDocument(doc, op)
+	result.Operations = ast.OperationList{&evaluated}
+	for i, fragment := range result.Fragments {
+		rewritten := *fragment
+		rewritten.SelectionSet = e.selectionSet(fragment.SelectionSet)
+		result.Fragments[i] = &rewritten
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return RemoveUnused(result), nil
+}
+
+type evaluator struct {
+	vars map[string]interface{}
+	// err is the first invalid condition found
+	err error
+}
+
+func (e *evaluator) selectionSet(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set))
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			field := *sel
+			field.Directives = e.directives(sel.Directives)
+			field.SelectionSet = e.selectionSet(sel.SelectionSet)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			fragment := *sel
+			fragment.Directives = e.directives(sel.Directives)
+			fragment.SelectionSet = e.selectionSet(sel.SelectionSet)
+			result = append(result, &fragment)
+
+		case *ast.FragmentSpread:
+			if !e.included(sel.Directives) {
+				continue
+			}
+			spread := *sel
+			spread.Directives = e.directives(sel.Directives)
+			result = append(result, &spread)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, &ast.Field{Alias: "__typename", Name: "__typename"})
+	}
+	return result
+}
+
+// included evaluates the @skip and @include directives in directives.
+func (e *evaluator) included(directives ast.DirectiveList) bool {
+	for _, dir := range directives {
+		if dir.Name != "skip" && dir.Name != "include" {
+			continue
+		}
+		if e.condition(dir) == (dir.Name == "skip") {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *evaluator) condition(dir *ast.Directive) bool {
+	arg := dir.Arguments.ForName("if")
+	var value interface{}
+	if arg != nil {
+		var err error
+		value, err = arg.Value.Value(e.vars)
+		if err != nil && e.err == nil {
+			e.err = gqlerror.WrapIfUnwrapped(err)
+		}
+	}
+	b, ok := value.(bool)
+	if !ok && e.err == nil {
+		e.err = gqlerror.ErrorPosf(dir.Position, "argument \"if\" of @%s must be a Boolean, got %s", dir.Name, describe(value))
+	}
+	return b
+}
+
+// directives returns directives without @skip and @include.
+func (e *evaluator) directives(directives ast.DirectiveList) ast.DirectiveList {
+	var result ast.DirectiveList
+	for _, dir := range directives {
+		if dir.Name != "skip" && dir.Name != "include" {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+func describe(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,56 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func TestEvaluateSkipInclude(t *testing.T) {
+	doc := gqlparser.MustLoadQuery(testSchema, `
+		query Q($withEmail: Boolean!, $skipFriends: Boolean = true, $first: Int, $id: ID!) {
+			user(id: $id) {
+				name
+				email @include(if: $withEmail)
+				password @skip(if: false)
+				friends(first: $first) @skip(if: $skipFriends) { ...Friend }
+				... on User @include(if: $withEmail) @defer { id }
+				...Details @skip(if: $withEmail)
+			}
+			users { id @skip(if: true) }
+		}
+		query Other { users { id } }
+		fragment Friend on User { id }
+		fragment Details on User { email @include(if: $withEmail) }
+	`)
+	before := format(doc)
+
+	t.Run("excluded", func(t *testing.T) {
+		evaluated, err := EvaluateSkipInclude(doc, "Q", map[string]interface{}{"withEmail": false, "id": "1"})
+		require.NoError(t, err)
+		require.Equal(t,
+			`query Q($id:ID!){user(id:$id){name password...Details}users{__typename}}fragment Details on User{__typename}`,
+			Print(evaluated),
+		)
+	})
+
+	t.Run("included", func(t *testing.T) {
+		evaluated, err := EvaluateSkipInclude(doc, "Q", map[string]interface{}{"withEmail": true, "skipFriends": false, "id": "1"})
+		require.NoError(t, err)
+		require.Equal(t,
+			`query Q($first:Int$id:ID!){user(id:$id){name email password friends(first:$first){...Friend}...on User@defer{id}}users{__typename}}fragment Friend on User{id}`,
+			Print(evaluated),
+		)
+	})
+
+	t.Run("invalid conditions", func(t *testing.T) {
+		_, err := EvaluateSkipInclude(doc, "Q", map[string]interface{}{"id": "1"})
+		require.EqualError(t, err, `input:5:12: argument "if" of @include must be a Boolean, got null`)
+
+		_, err = EvaluateSkipInclude(doc, "Q", map[string]interface{}{"withEmail": "yes"})
+		require.EqualError(t, err, `input:5:12: argument "if" of @include must be a Boolean, got string`)
+	})
+
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+}
@@ -0,0 +1,16 @@
+package transform
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SplitOperations returns one document per operation in doc, in the same order, each holding only
+// that operation and the fragments it uses directly or through other fragments. Fragments are
+// shared between the results, not copied.
+func SplitOperations(doc *ast.QueryDocument) []*ast.QueryDocument {
+	docs := make([]*ast.QueryDocument, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		docs = append(docs, operationDocument(doc, op))
+	}
+	return docs
+}
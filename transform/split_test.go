@@ -0,0 +1,37 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestSplitOperations(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query A { user { ...UserFields } }
+		fragment Unused on User { id }
+		query B { users { ... on User { ...Friends } } }
+		fragment UserFields on User { name ...Friends }
+		fragment Friends on User { friends { ...UserFields } }
+		query C { me }
+	`})
+	require.NoError(t, err)
+
+	docs := SplitOperations(doc)
+	require.Len(t, docs, 3)
+
+	var printed []string
+	for _, d := range docs {
+		printed = append(printed, Print(d))
+	}
+	require.Equal(t, []string{
+		`query A{user{...UserFields}}fragment UserFields on User{name...Friends}fragment Friends on User{friends{...UserFields}}`,
+		`query B{users{...on User{...Friends}}}fragment UserFields on User{name...Friends}fragment Friends on User{friends{...UserFields}}`,
+		`query C{me}`,
+	}, printed)
+
+	require.Len(t, doc.Operations, 3)
+	require.Len(t, doc.Fragments, 3)
+}
@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// TypenameOptions configures AddTypename.
+type TypenameOptions struct {
+	// AbstractOnly only adds __typename to selection sets on interfaces and unions, where it is
+	// needed to tell which type was returned.
+	AbstractOnly bool
+}
+
+// AddTypename returns a copy of doc with a __typename field appended to every selection set that
+// doesn't already select it, as required by normalized client caches.
+//
+// The selection sets of operations are left alone, selecting more than one root field isn't
+// allowed in subscriptions and the type of the root is known anyway. So are the selection sets of
+// introspection fields. doc must have been validated against schema.
+func AddTypename(schema *ast.Schema, doc *ast.QueryDocument, options TypenameOptions) *ast.QueryDocument {
+	a := typenameAdder{schema: schema, options: options}
+
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+	for _, op := range doc.Operations {
+		added := *op
+		added.SelectionSet = a.selections(op.SelectionSet)
+		result.Operations = append(result.Operations, &added)
+	}
+	for _, fragment := range doc.Fragments {
+		added := *fragment
+		added.SelectionSet = a.selectionSet(fragment.SelectionSet, schema.Types[fragment.TypeCondition])
+		result.Fragments = append(result.Fragments, &added)
+	}
+	return result
+}
+
+type typenameAdder struct {
+	schema  *ast.Schema
+	options TypenameOptions
+}
+
+// selectionSet rewrites set, a selection set on def, and adds __typename to it.
+func (a *typenameAdder) selectionSet(set ast.SelectionSet, def *ast.Definition) ast.SelectionSet {
+	result := a.selections(set)
+	if len(set) == 0 || hasTypename(set) {
+		return result
+	}
+	if a.options.AbstractOnly && (def == nil || !def.IsAbstractType()) {
+		return result
+	}
+	return append(result, &ast.Field{
+		Name: "__typename",
+		Definition: &ast.FieldDefinition{
+			Name: "__typename",
+			Type: ast.NonNullNamedType("String", nil),
+		},
+		ObjectDefinition: def,
+	})
+}
+
+// selections rewrites the selection sets within set without adding __typename to set itself.
+func (a *typenameAdder) selections(set ast.SelectionSet) ast.SelectionSet {
+	if set == nil {
+		return nil
+	}
+
+	result := make(ast.SelectionSet, 0, len(set)+1)
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			field := *sel
+			if strings.HasPrefix(sel.Name, "__") {
+				result = append(result, sel)
+				continue
+			}
+			var def *ast.Definition
+			if sel.Definition != nil {
+				def = a.schema.Types[sel.Definition.Type.Name()]
+			}
+			field.SelectionSet = a.selectionSet(sel.SelectionSet, def)
+			result = append(result, &field)
+
+		case *ast.InlineFragment:
+			fragment := *sel
+			def := sel.ObjectDefinition
+			if sel.TypeCondition != "" {
+				def = a.schema.Types[sel.TypeCondition]
+			}
+			fragment.SelectionSet = a.selectionSet(sel.SelectionSet, def)
+			result = append(result, &fragment)
+
+		default:
+			result = append(result, sel)
+		}
+	}
+	return result
+}
+
+func hasTypename(set ast.SelectionSet) bool {
+	for _, sel := range set {
+		if field, ok := sel.(*ast.Field); ok && field.Name == "__typename" && (field.Alias == "" || field.Alias == field.Name) {
+			return true
+		}
+	}
+	return false
+}
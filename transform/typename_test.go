@@ -0,0 +1,40 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+)
+
+func TestAddTypename(t *testing.T) {
+	doc := gqlparser.MustLoadQuery(testSchema, `
+		query Q {
+			node(id: 1) { id ... on User { name friends(first: 1) { ...Friend } } }
+			user(id: 1) { __typename id }
+			__schema { types { name } }
+		}
+		fragment Friend on User { name }
+	`)
+	before := format(doc)
+
+	require.Equal(t,
+		`query Q{node(id:1){id...on User{name friends(first:1){...Friend __typename}__typename}__typename}user(id:1){__typename id}__schema{types{name}}}`+
+			`fragment Friend on User{name __typename}`,
+		Print(AddTypename(testSchema, doc, TypenameOptions{})),
+	)
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+
+	t.Run("abstract only", func(t *testing.T) {
+		require.Equal(t,
+			`query Q{node(id:1){id...on User{name friends(first:1){...Friend}}__typename}user(id:1){__typename id}__schema{types{name}}}`+
+				`fragment Friend on User{name}`,
+			Print(AddTypename(testSchema, doc, TypenameOptions{AbstractOnly: true})),
+		)
+	})
+
+	t.Run("result is still valid", func(t *testing.T) {
+		_, err := gqlparser.LoadQuery(testSchema, Print(AddTypename(testSchema, doc, TypenameOptions{})))
+		require.Nil(t, err)
+	})
+}
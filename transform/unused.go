@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// RemoveUnused returns a copy of doc without the fragments that no operation uses and without
+// the variable definitions that are never referenced by their operation or the fragments it
+// uses. It is typically run after other transforms that drop selections.
+func RemoveUnused(doc *ast.QueryDocument) *ast.QueryDocument {
+	result := &ast.QueryDocument{
+		Position: doc.Position,
+		Comment:  doc.Comment,
+	}
+
+	kept := map[string]bool{}
+	for _, op := range doc.Operations {
+		used := usedFragments(doc, op.SelectionSet)
+		referenced := map[string]bool{}
+		variablesInDirectives(op.Directives, referenced)
+		variablesInSelectionSet(op.SelectionSet, referenced)
+		for _, fragment := range doc.Fragments {
+			if used[fragment.Name] {
+				kept[fragment.Name] = true
+				variablesInDirectives(fragment.Directives, referenced)
+				variablesInSelectionSet(fragment.SelectionSet, referenced)
+			}
+		}
+
+		trimmed := *op
+		trimmed.VariableDefinitions = nil
+		for _, def := range op.VariableDefinitions {
+			if referenced[def.Variable] {
+				trimmed.VariableDefinitions = append(trimmed.VariableDefinitions, def)
+			}
+		}
+		result.Operations = append(result.Operations, &trimmed)
+	}
+
+	for _, fragment := range doc.Fragments {
+		if kept[fragment.Name] {
+			result.Fragments = append(result.Fragments, fragment)
+		}
+	}
+	return result
+}
+
+// variablesInSelectionSet adds the names of the variables used anywhere in set to vars, without
+// following fragment spreads.
+func variablesInSelectionSet(set ast.SelectionSet, vars map[string]bool) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			for _, arg := range sel.Arguments {
+				variablesInValue(arg.Value, vars)
+			}
+			variablesInDirectives(sel.Directives, vars)
+			variablesInSelectionSet(sel.SelectionSet, vars)
+		case *ast.InlineFragment:
+			variablesInDirectives(sel.Directives, vars)
+			variablesInSelectionSet(sel.SelectionSet, vars)
+		case *ast.FragmentSpread:
+			variablesInDirectives(sel.Directives, vars)
+		}
+	}
+}
+
+func variablesInDirectives(directives ast.DirectiveList, vars map[string]bool) {
+	for _, dir := range directives {
+		for _, arg := range dir.Arguments {
+			variablesInValue(arg.Value, vars)
+		}
+	}
+}
+
+func variablesInValue(v *ast.Value, vars map[string]bool) {
+	if v == nil {
+		return
+	}
+	if v.Kind == ast.Variable {
+		vars[v.Raw] = true
+		return
+	}
+	for _, child := range v.Children {
+		variablesInValue(child.Value, vars)
+	}
+}
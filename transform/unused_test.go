@@ -0,0 +1,33 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestRemoveUnused(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query A($id: ID!, $unused: Int, $skip: Boolean!, $first: Int, $filter: String) @live(if: $skip) {
+			user(id: $id) { ...UserFields }
+			users(where: {name: [$filter]}) { id }
+		}
+		query B($first: Int, $unused: Int) { me { ...Friends } }
+		fragment UserFields on User { name ...Friends }
+		fragment Friends on User { friends(first: $first) { id } }
+		fragment Orphan on User { id ...Orphan2 }
+		fragment Orphan2 on User { id }
+	`})
+	require.NoError(t, err)
+	before := format(doc)
+
+	require.Equal(t,
+		`query A($id:ID!$skip:Boolean!$first:Int$filter:String)@live(if:$skip){user(id:$id){...UserFields}users(where:{name:[$filter]}){id}}`+
+			`query B($first:Int){me{...Friends}}`+
+			`fragment UserFields on User{name...Friends}fragment Friends on User{friends(first:$first){id}}`,
+		Print(RemoveUnused(doc)),
+	)
+	require.Equal(t, before, format(doc), "the input document must not be modified")
+}
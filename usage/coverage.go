@@ -0,0 +1,334 @@
+package usage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Kind is the kind of schema member an Item is.
+type Kind string
+
+const (
+	KindType       Kind = "type"
+	KindField      Kind = "field"
+	KindArgument   Kind = "argument"
+	KindInputField Kind = "input field"
+	KindEnumValue  Kind = "enum value"
+	KindDirective  Kind = "directive"
+)
+
+// Item is a type, field, argument, input field, enum value or directive of a schema, with how much
+// operations use it.
+type Item struct {
+	// Coordinate is the schema coordinate of the item, eg "User", "User.name", "Query.user(id:)",
+	// "Role.ADMIN" or "@cached".
+	Coordinate string
+	Kind       Kind
+	// Deprecated is set on fields, arguments, input fields and enum values marked @deprecated.
+	Deprecated bool
+	// Operations is the number of operations using the item.
+	Operations int
+	// Locations holds where the documents use the item, sorted by source, line and column.
+	Locations []*ast.Position
+}
+
+// Used reports whether any operation uses the item.
+func (i *Item) Used() bool {
+	return i.Operations > 0
+}
+
+// Coverage tells which parts of a schema a corpus of operations uses, for planning deprecations
+// and removing dead parts of a schema.
+type Coverage struct {
+	// Items holds every type, field, argument, input field, enum value and directive defined by
+	// the schema, leaving out built in ones, sorted by coordinate.
+	Items []*Item
+
+	index map[string]*Item
+	// locations holds the locations already recorded for each item
+	locations map[*Item]map[location]bool
+}
+
+type location struct {
+	src   *ast.Source
+	start int
+}
+
+// Cover walks the operations of docs, following their fragments, and returns what they use of
+// schema. Like Coordinates, fields are counted on the type they are selected on, and doesn't
+// need to have been validated.
+//
+// A type is used when a field returning it is selected, when it is named by a type condition or a
+// variable, or when an argument or input field of that type is given. Enum values are only seen
+// when written in the documents, values passed in variables are unknown.
+func Cover(schema *ast.Schema, docs ...*ast.QueryDocument) *Coverage {
+	c := &Coverage{index: map[string]*Item{}, locations: map[*Item]map[location]bool{}}
+	c.define(schema)
+
+	for _, doc := range docs {
+		for _, op := range doc.Operations {
+			w := coverageWalker{
+				coverage: c,
+				schema:   schema,
+				doc:      doc,
+				used:     map[*Item]bool{},
+				visited:  map[string]bool{},
+			}
+			w.variableDefinitions(op.VariableDefinitions)
+			w.directives(op.Directives)
+			root := rootType(schema, op.Operation)
+			if root != nil {
+				w.use(root.Name, op.Position)
+			}
+			w.selectionSet(root, op.SelectionSet)
+		}
+	}
+
+	for _, item := range c.Items {
+		sort.Slice(item.Locations, func(i, j int) bool {
+			a, b := item.Locations[i], item.Locations[j]
+			if sourceName(a) != sourceName(b) {
+				return sourceName(a) < sourceName(b)
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Column < b.Column
+		})
+	}
+	return c
+}
+
+// Item returns the item with the given coordinate, or nil.
+func (c *Coverage) Item(coordinate string) *Item {
+	return c.index[coordinate]
+}
+
+// Used returns the items used by at least one operation.
+func (c *Coverage) Used() []*Item {
+	var items []*Item
+	for _, item := range c.Items {
+		if item.Used() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Unused returns the items no operation uses.
+func (c *Coverage) Unused() []*Item {
+	var items []*Item
+	for _, item := range c.Items {
+		if !item.Used() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// define adds an item for every member of schema.
+func (c *Coverage) define(schema *ast.Schema) {
+	add := func(coordinate string, kind Kind, directives ast.DirectiveList) {
+		item := &Item{Coordinate: coordinate, Kind: kind, Deprecated: directives.ForName("deprecated") != nil}
+		c.Items = append(c.Items, item)
+		c.index[coordinate] = item
+	}
+
+	for _, def := range schema.Types {
+		if def.BuiltIn {
+			continue
+		}
+		add(def.Name, KindType, nil)
+		for _, field := range def.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			coordinate := def.Name + "." + field.Name
+			if def.Kind == ast.InputObject {
+				add(coordinate, KindInputField, field.Directives)
+				continue
+			}
+			add(coordinate, KindField, field.Directives)
+			for _, arg := range field.Arguments {
+				add(coordinate+"("+arg.Name+":)", KindArgument, arg.Directives)
+			}
+		}
+		for _, value := range def.EnumValues {
+			add(def.Name+"."+value.Name, KindEnumValue, value.Directives)
+		}
+	}
+	for _, dir := range schema.Directives {
+		if dir.Position != nil && dir.Position.Src != nil && dir.Position.Src.BuiltIn {
+			continue
+		}
+		add("@"+dir.Name, KindDirective, nil)
+		for _, arg := range dir.Arguments {
+			add("@"+dir.Name+"("+arg.Name+":)", KindArgument, arg.Directives)
+		}
+	}
+
+	sort.Slice(c.Items, func(i, j int) bool { return c.Items[i].Coordinate < c.Items[j].Coordinate })
+}
+
+func sourceName(pos *ast.Position) string {
+	if pos.Src == nil {
+		return ""
+	}
+	return pos.Src.Name
+}
+
+// coverageWalker records what one operation uses.
+type coverageWalker struct {
+	coverage *Coverage
+	schema   *ast.Schema
+	doc      *ast.QueryDocument
+	// used holds the items already counted for the operation
+	used map[*Item]bool
+	// visited holds the fragments walked on each type, as "Fragment@Type"
+	visited map[string]bool
+}
+
+// use records the use of the item at coordinate by the operation, at pos.
+func (w *coverageWalker) use(coordinate string, pos *ast.Position) {
+	item := w.coverage.index[coordinate]
+	if item == nil {
+		return
+	}
+	if !w.used[item] {
+		w.used[item] = true
+		item.Operations++
+	}
+	if pos == nil {
+		return
+	}
+	seen := w.coverage.locations[item]
+	if seen == nil {
+		seen = map[location]bool{}
+		w.coverage.locations[item] = seen
+	}
+	if key := (location{pos.Src, pos.Start}); !seen[key] {
+		seen[key] = true
+		item.Locations = append(item.Locations, pos)
+	}
+}
+
+func (w *coverageWalker) variableDefinitions(defs ast.VariableDefinitionList) {
+	for _, def := range defs {
+		if def.Type == nil {
+			continue
+		}
+		w.use(def.Type.Name(), def.Type.Position)
+		w.value(def.DefaultValue, def.Type)
+		w.directives(def.Directives)
+	}
+}
+
+func (w *coverageWalker) selectionSet(parent *ast.Definition, set ast.SelectionSet) {
+	if parent == nil {
+		return
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			def := parent.Fields.ForName(sel.Name)
+			if def == nil {
+				continue
+			}
+			coordinate := parent.Name + "." + sel.Name
+			w.use(coordinate, sel.Position)
+			w.use(def.Type.Name(), nil)
+			w.arguments(coordinate, def.Arguments, sel.Arguments)
+			w.directives(sel.Directives)
+			w.selectionSet(w.schema.Types[def.Type.Name()], sel.SelectionSet)
+
+		case *ast.InlineFragment:
+			typ := parent
+			if sel.TypeCondition != "" {
+				typ = w.schema.Types[sel.TypeCondition]
+				w.use(sel.TypeCondition, sel.Position)
+			}
+			w.directives(sel.Directives)
+			w.selectionSet(typ, sel.SelectionSet)
+
+		case *ast.FragmentSpread:
+			w.directives(sel.Directives)
+			fragment := w.doc.Fragments.ForName(sel.Name)
+			if fragment == nil {
+				continue
+			}
+			typ := w.schema.Types[fragment.TypeCondition]
+			if typ == nil || w.visited[sel.Name+"@"+typ.Name] {
+				continue
+			}
+			w.visited[sel.Name+"@"+typ.Name] = true
+			w.use(typ.Name, fragment.Position)
+			w.directives(fragment.Directives)
+			w.selectionSet(typ, fragment.SelectionSet)
+		}
+	}
+}
+
+// arguments records the arguments given to the field or directive at coordinate.
+func (w *coverageWalker) arguments(coordinate string, defs ast.ArgumentDefinitionList, args ast.ArgumentList) {
+	for _, arg := range args {
+		def := defs.ForName(arg.Name)
+		if def == nil {
+			continue
+		}
+		w.use(coordinate+"("+arg.Name+":)", arg.Position)
+		w.use(def.Type.Name(), nil)
+		w.value(arg.Value, def.Type)
+	}
+}
+
+func (w *coverageWalker) directives(directives ast.DirectiveList) {
+	for _, dir := range directives {
+		def := w.schema.Directives[dir.Name]
+		if def == nil {
+			continue
+		}
+		w.use("@"+dir.Name, dir.Position)
+		w.arguments("@"+dir.Name, def.Arguments, dir.Arguments)
+	}
+}
+
+// value records the enum values and input fields written in v, which is of type typ.
+func (w *coverageWalker) value(v *ast.Value, typ *ast.Type) {
+	if v == nil || typ == nil {
+		return
+	}
+	switch v.Kind {
+	case ast.ListValue:
+		// a single value can be given for a list
+		elem := typ
+		if typ.Elem != nil {
+			elem = typ.Elem
+		}
+		for _, child := range v.Children {
+			w.value(child.Value, elem)
+		}
+	case ast.EnumValue:
+		if def := w.schema.Types[typ.Name()]; def != nil && def.Kind == ast.Enum {
+			w.use(def.Name+"."+v.Raw, v.Position)
+		}
+	case ast.ObjectValue:
+		def := w.schema.Types[typ.Name()]
+		if def == nil || def.Kind != ast.InputObject {
+			return
+		}
+		for _, child := range v.Children {
+			field := def.Fields.ForName(child.Name)
+			if field == nil {
+				continue
+			}
+			w.use(def.Name+"."+child.Name, child.Position)
+			w.use(field.Type.Name(), nil)
+			w.value(child.Value, field.Type)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package usage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/usage"
+)
+
+func TestCover(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+		directive @cached(ttl: Int) on FIELD
+		type Query {
+			users(filter: Filter, order: [Order!]): [User!]!
+			legacy: String @deprecated
+		}
+		input Filter {
+			role: Role
+			name: String
+		}
+		enum Role { ADMIN GUEST @deprecated }
+		enum Order { NAME AGE }
+		type User {
+			id: ID!
+			name: String
+			role: Role
+		}
+	`})
+
+	docs := []*ast.QueryDocument{
+		parseQuery(t, "a.graphql", `
+			query A($role: Role = GUEST) {
+				users(filter: {role: $role}) { ...UserFields }
+			}
+			fragment UserFields on User { id name @cached(ttl: 60) }
+		`),
+		parseQuery(t, "b.graphql", `
+			query B { users(order: NAME) { id __typename } }
+		`),
+	}
+	coverage := usage.Cover(schema, docs...)
+
+	var used []string
+	for _, item := range coverage.Used() {
+		used = append(used, item.Coordinate)
+	}
+	require.Equal(t, []string{
+		"@cached",
+		"@cached(ttl:)",
+		"Filter",
+		"Filter.role",
+		"Order",
+		"Order.NAME",
+		"Query",
+		"Query.users",
+		"Query.users(filter:)",
+		"Query.users(order:)",
+		"Role",
+		"Role.GUEST",
+		"User",
+		"User.id",
+		"User.name",
+	}, used)
+
+	var unused []string
+	for _, item := range coverage.Unused() {
+		unused = append(unused, item.Coordinate)
+	}
+	require.Equal(t, []string{
+		"Filter.name",
+		"Order.AGE",
+		"Query.legacy",
+		"Role.ADMIN",
+		"User.role",
+	}, unused)
+
+	id := coverage.Item("User.id")
+	require.Equal(t, usage.KindField, id.Kind)
+	require.Equal(t, 2, id.Operations)
+	require.Len(t, id.Locations, 2)
+	require.Equal(t, "a.graphql", id.Locations[0].Src.Name)
+	require.Equal(t, 5, id.Locations[0].Line)
+	require.Equal(t, "b.graphql", id.Locations[1].Src.Name)
+
+	guest := coverage.Item("Role.GUEST")
+	require.Equal(t, usage.KindEnumValue, guest.Kind)
+	require.True(t, guest.Deprecated)
+	require.Equal(t, 1, guest.Operations)
+	require.Equal(t, 2, guest.Locations[0].Line)
+
+	require.Equal(t, usage.KindInputField, coverage.Item("Filter.role").Kind)
+	require.Equal(t, usage.KindArgument, coverage.Item("@cached(ttl:)").Kind)
+	require.Equal(t, usage.KindDirective, coverage.Item("@cached").Kind)
+	require.True(t, coverage.Item("Query.legacy").Deprecated)
+	require.Equal(t, 2, coverage.Item("Query").Operations)
+	require.Nil(t, coverage.Item("String"))
+	require.Nil(t, coverage.Item("@deprecated"))
+}
+
+func parseQuery(t *testing.T, name string, input string) *ast.QueryDocument {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Name: name, Input: input})
+	require.NoError(t, err)
+	return doc
+}
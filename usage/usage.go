@@ -0,0 +1,106 @@
+// Package usage reports which parts of a schema operations use, for usage reporting and tracking
+// the use of deprecated fields.
+package usage
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Coordinates returns the sorted schema coordinates of every field and argument used by the named
+// operation in doc, eg "Query.user", "Query.user(id:)" and "User.friends".
+//
+// Fragments are followed and type conditions resolved against schema, so fields are reported on
+// the type they are selected on: "Node.id" for a field selected on the Node interface and
+// "User.id" for one selected within `... on User`. Introspection fields and everything below them
+// are left out. Fields that don't exist in schema are skipped, doc doesn't need to have been
+// validated.
+func Coordinates(schema *ast.Schema, doc *ast.QueryDocument, operationName string) ([]string, error) {
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return nil, gqlerror.Errorf("operation %s not found", strconv.Quote(operationName))
+	}
+
+	c := collector{
+		schema:      schema,
+		doc:         doc,
+		coordinates: map[string]bool{},
+		visited:     map[string]bool{},
+	}
+	c.selectionSet(rootType(schema, op.Operation), op.SelectionSet)
+
+	result := make([]string, 0, len(c.coordinates))
+	for coordinate := range c.coordinates {
+		result = append(result, coordinate)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func rootType(schema *ast.Schema, operation ast.Operation) *ast.Definition {
+	switch operation {
+	case ast.Mutation:
+		return schema.Mutation
+	case ast.Subscription:
+		return schema.Subscription
+	default:
+		return schema.Query
+	}
+}
+
+type collector struct {
+	schema      *ast.Schema
+	doc         *ast.QueryDocument
+	coordinates map[string]bool
+	// visited holds the fragments walked on each type, as "Fragment@Type"
+	visited map[string]bool
+}
+
+func (c *collector) selectionSet(parent *ast.Definition, set ast.SelectionSet) {
+	if parent == nil {
+		return
+	}
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			def := parent.Fields.ForName(sel.Name)
+			if def == nil {
+				continue
+			}
+			coordinate := parent.Name + "." + sel.Name
+			c.coordinates[coordinate] = true
+			for _, arg := range sel.Arguments {
+				if def.Arguments.ForName(arg.Name) != nil {
+					c.coordinates[coordinate+"("+arg.Name+":)"] = true
+				}
+			}
+			c.selectionSet(c.schema.Types[def.Type.Name()], sel.SelectionSet)
+
+		case *ast.InlineFragment:
+			typ := parent
+			if sel.TypeCondition != "" {
+				typ = c.schema.Types[sel.TypeCondition]
+			}
+			c.selectionSet(typ, sel.SelectionSet)
+
+		case *ast.FragmentSpread:
+			fragment := c.doc.Fragments.ForName(sel.Name)
+			if fragment == nil {
+				continue
+			}
+			typ := c.schema.Types[fragment.TypeCondition]
+			if typ == nil || c.visited[sel.Name+"@"+typ.Name] {
+				continue
+			}
+			c.visited[sel.Name+"@"+typ.Name] = true
+			c.selectionSet(typ, fragment.SelectionSet)
+		}
+	}
+}
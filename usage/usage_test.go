@@ -0,0 +1,77 @@
+package usage_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/usage"
+)
+
+var schema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		node(id: ID!): Node
+		user(id: ID!): User
+	}
+	type Mutation {
+		rename(id: ID!, name: String!): User
+	}
+	interface Node {
+		id: ID!
+	}
+	type User implements Node {
+		id: ID!
+		name: String
+		friends(first: Int, after: String): [User!]!
+	}
+	type Post implements Node {
+		id: ID!
+		author: User
+	}
+`})
+
+func TestCoordinates(t *testing.T) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: `
+		query Q($id: ID!) {
+			node(id: $id) {
+				__typename
+				id
+				... on Post { author { ...UserFields } }
+				...UserFields
+			}
+			__schema { types { name } }
+		}
+		mutation M { rename(id: 1, name: "x") { id unknown } }
+		fragment UserFields on User {
+			n: name
+			friends(first: 10) { ...UserFields }
+		}
+	`})
+	require.NoError(t, err)
+
+	coordinates, err := usage.Coordinates(schema, doc, "Q")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Node.id",
+		"Post.author",
+		"Query.node",
+		"Query.node(id:)",
+		"User.friends",
+		"User.friends(first:)",
+		"User.name",
+	}, coordinates)
+
+	coordinates, err = usage.Coordinates(schema, doc, "M")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Mutation.rename",
+		"Mutation.rename(id:)",
+		"Mutation.rename(name:)",
+		"User.id",
+	}, coordinates)
+
+	_, err = usage.Coordinates(schema, doc, "")
+	require.EqualError(t, err, `input: operation "" not found`)
+}
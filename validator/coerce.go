@@ -0,0 +1,313 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ScalarCoercer coerces the value given for a custom scalar, returning an error describing why
+// the value is invalid otherwise.
+type ScalarCoercer func(value interface{}) (interface{}, error)
+
+// CoerceOption configures CoerceVariables.
+type CoerceOption func(c *coercer)
+
+// WithScalarCoercer uses fn to coerce values of the named scalar. Values of custom scalars without
+// a coercer are used as is.
+func WithScalarCoercer(name string, fn ScalarCoercer) CoerceOption {
+	return func(c *coercer) {
+		c.scalars[name] = fn
+	}
+}
+
+// CoerceVariables coerces the raw values of the variables of op, typically decoded from the JSON
+// body of a request, following the input coercion rules of the spec.
+//
+// Missing variables and input object fields take their default values, single values given for
+// lists become lists of one and non null types are enforced. Coerced values use int64 for Int,
+// float64 for Float, string for String, ID and enum values, bool for Boolean,
+// map[string]interface{} for input objects and []interface{} for lists. raw is not modified.
+//
+// Every invalid variable is reported, with the same messages as graphql-js, eg
+// `Variable "$filter" got invalid value "GUEST" at "filter.roles[0]"; Value "GUEST" does not
+// exist in "Role" enum.` The errors have the BAD_USER_INPUT code.
+func CoerceVariables(schema *ast.Schema, op *ast.OperationDefinition, raw map[string]interface{}, options ...CoerceOption) (map[string]interface{}, gqlerror.List) {
+	c := coercer{
+		schema:  schema,
+		scalars: map[string]ScalarCoercer{},
+	}
+	for _, o := range options {
+		o(&c)
+	}
+
+	coerced := map[string]interface{}{}
+	var errs gqlerror.List
+	for _, v := range op.VariableDefinitions {
+		v := v
+		report := func(message string, args ...interface{}) {
+			err := gqlerror.ErrorPosf(v.Position, message, args...)
+			err.SetCode(gqlerror.CodeBadUserInput)
+			err.AddNodes(v)
+			errs = append(errs, err)
+		}
+
+		def := schema.Types[v.Type.Name()]
+		if def == nil || !def.IsInputType() {
+			report(`Variable "$%s" expected value of type "%s" which cannot be used as an input type.`, v.Variable, v.Type.String())
+			continue
+		}
+
+		got := "got invalid value"
+		value, ok := raw[v.Variable]
+		switch {
+		case !ok && v.DefaultValue == nil:
+			if v.Type.NonNull {
+				report(`Variable "$%s" of required type "%s" was not provided.`, v.Variable, v.Type.String())
+			}
+			continue
+		case !ok:
+			// the default value is coerced like a given value, so a bad default is reported
+			// rather than silently leaving the variable out
+			var err error
+			value, err = v.DefaultValue.Value(nil)
+			if err != nil {
+				report(`Variable "$%s" has an invalid default value: %s`, v.Variable, err.Error())
+				continue
+			}
+			got = "has invalid default value"
+		case value == nil && v.Type.NonNull:
+			report(`Variable "$%s" of non-null type "%s" must not be null.`, v.Variable, v.Type.String())
+			continue
+		}
+
+		var invalid []invalidValue
+		value = c.coerce(v.Type, value, ast.Path{ast.PathName(v.Variable)}, &invalid)
+		for _, it := range invalid {
+			at := ""
+			if len(it.path) > 1 {
+				at = fmt.Sprintf(" at %s", strconv.Quote(it.path.String()))
+			}
+			report(`Variable "$%s" %s %s%s; %s`, v.Variable, got, inspect(it.value), at, it.message)
+		}
+		if len(invalid) == 0 {
+			coerced[v.Variable] = value
+		}
+	}
+	return coerced, errs
+}
+
+type coercer struct {
+	schema  *ast.Schema
+	scalars map[string]ScalarCoercer
+}
+
+// invalidValue is a value found at path within a variable that can't be coerced.
+type invalidValue struct {
+	path    ast.Path
+	value   interface{}
+	message string
+}
+
+func (c *coercer) coerce(typ *ast.Type, value interface{}, path ast.Path, invalid *[]invalidValue) interface{} {
+	report := func(message string, args ...interface{}) {
+		*invalid = append(*invalid, invalidValue{path: path, value: value, message: fmt.Sprintf(message, args...)})
+	}
+
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			rv = reflect.Value{}
+			break
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		if typ.NonNull {
+			report(`Expected non-nullable type "%s" not to be null.`, typ.String())
+		}
+		return nil
+	}
+	value = rv.Interface()
+
+	if typ.Elem != nil {
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return []interface{}{c.coerce(typ.Elem, value, path, invalid)}
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = c.coerce(typ.Elem, rv.Index(i).Interface(), append(path[:len(path):len(path)], ast.PathIndex(i)), invalid)
+		}
+		return list
+	}
+
+	def := c.schema.Types[typ.NamedType]
+	if def == nil {
+		report(`Unknown type "%s".`, typ.NamedType)
+		return nil
+	}
+
+	switch def.Kind {
+	case ast.Scalar:
+		coerced, err := c.scalar(def.Name, value)
+		if err != "" {
+			report("%s", err)
+		}
+		return coerced
+
+	case ast.Enum:
+		name, ok := value.(string)
+		if !ok {
+			report(`Enum "%s" cannot represent non-string value: %s.`, def.Name, inspect(value))
+			return nil
+		}
+		if def.EnumValues.ForName(name) == nil {
+			var names []string
+			for _, enumValue := range def.EnumValues {
+				names = append(names, enumValue.Name)
+			}
+			report(`Value %s does not exist in "%s" enum.%s`, strconv.Quote(name), def.Name, DidYouMean(SuggestionList(name, names)...))
+			return nil
+		}
+		return name
+
+	case ast.InputObject:
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			report(`Expected type "%s" to be an object.`, def.Name)
+			return nil
+		}
+
+		var unknown []string
+		for _, key := range rv.MapKeys() {
+			if def.Fields.ForName(key.String()) == nil {
+				unknown = append(unknown, key.String())
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			var names []string
+			for _, field := range def.Fields {
+				names = append(names, field.Name)
+			}
+			report(`Field "%s" is not defined by type "%s".%s`, name, def.Name, DidYouMean(SuggestionList(name, names)...))
+		}
+
+		object := map[string]interface{}{}
+		for _, field := range def.Fields {
+			fieldPath := append(path[:len(path):len(path)], ast.PathName(field.Name))
+			fieldValue := rv.MapIndex(reflect.ValueOf(field.Name).Convert(rv.Type().Key()))
+			if !fieldValue.IsValid() {
+				if field.DefaultValue != nil {
+					value, err := field.DefaultValue.Value(nil)
+					if err != nil {
+						report(`Field "%s" has an invalid default value: %s`, field.Name, err.Error())
+						continue
+					}
+					object[field.Name] = c.coerce(field.Type, value, fieldPath, invalid)
+				} else if field.Type.NonNull {
+					report(`Field "%s" of required type "%s" was not provided.`, field.Name, field.Type.String())
+				}
+				continue
+			}
+			object[field.Name] = c.coerce(field.Type, fieldValue.Interface(), fieldPath, invalid)
+		}
+		return object
+
+	default:
+		report(`Type "%s" is not an input type.`, def.Name)
+		return nil
+	}
+}
+
+// scalar coerces value to the named scalar, returning a message describing why it can't be
+// otherwise.
+func (c *coercer) scalar(name string, value interface{}) (interface{}, string) {
+	if fn := c.scalars[name]; fn != nil {
+		coerced, err := fn(value)
+		if err != nil {
+			return nil, err.Error()
+		}
+		return coerced, ""
+	}
+
+	switch name {
+	case "Int":
+		n, ok := number(value)
+		if !ok || n != math.Trunc(n) {
+			return nil, "Int cannot represent non-integer value: " + inspect(value)
+		}
+		if n > math.MaxInt32 || n < math.MinInt32 {
+			return nil, "Int cannot represent non 32-bit signed integer value: " + inspect(value)
+		}
+		return int64(n), ""
+
+	case "Float":
+		n, ok := number(value)
+		if !ok || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, "Float cannot represent non numeric value: " + inspect(value)
+		}
+		return n, ""
+
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, ""
+		}
+		return nil, "String cannot represent a non string value: " + inspect(value)
+
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, ""
+		}
+		return nil, "Boolean cannot represent a non boolean value: " + inspect(value)
+
+	case "ID":
+		if s, ok := value.(string); ok {
+			return s, ""
+		}
+		if n, ok := number(value); ok && n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+			return strconv.FormatInt(int64(n), 10), ""
+		}
+		return nil, "ID cannot represent value: " + inspect(value)
+
+	default:
+		return value, ""
+	}
+}
+
+// number returns value as a float64 if it is a number.
+func number(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case json.Number:
+		n, err := value.Float64()
+		return n, err == nil
+	case bool, string:
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// inspect formats value for error messages.
+func inspect(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(b)
+}
@@ -0,0 +1,141 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+func TestCoerceVariables(t *testing.T) {
+	schema := gqlparser.MustLoadSchema(&ast.Source{
+		Name:  "vars.graphql",
+		Input: mustReadFile("./testdata/vars.graphql"),
+	})
+
+	coerce := func(t *testing.T, query string, raw string, options ...validator.CoerceOption) (map[string]interface{}, []string) {
+		t.Helper()
+		q := gqlparser.MustLoadQuery(schema, query)
+		var variables map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(raw), &variables))
+
+		vars, errs := validator.CoerceVariables(schema, q.Operations.ForName(""), variables, options...)
+		var messages []string
+		for _, err := range errs {
+			require.Equal(t, gqlerror.CodeBadUserInput, err.Code())
+			messages = append(messages, err.Message)
+		}
+		return vars, messages
+	}
+
+	t.Run("valid values", func(t *testing.T) {
+		vars, errs := coerce(t, `
+			query($int: Int!, $float: Float!, $id: ID!, $ids: ID!, $bool: Boolean!, $list: [Int], $single: [String], $obj: InputType!, $default: Int = 5, $missing: String) {
+				intArg(i: $int) floatArg(i: $float) idArg(i: $id) a: idArg(i: $ids) boolArg(i: $bool) intArrayArg(i: $list)
+				stringArrayArg(i: $single) structArg(i: $obj) optionalIntArg(i: $default) stringArg(i: $missing)
+			}`,
+			`{"int": 1.0, "float": 2, "id": 3, "ids": "x", "bool": true, "list": [1, null], "single": "s", "obj": {"name": "n", "enum": "A", "nullEmbedded": null}}`,
+		)
+		require.Empty(t, errs)
+		require.Equal(t, map[string]interface{}{
+			"int":    int64(1),
+			"float":  float64(2),
+			"id":     "3",
+			"ids":    "x",
+			"bool":   true,
+			"list":   []interface{}{int64(1), nil},
+			"single": []interface{}{"s"},
+			"obj": map[string]interface{}{
+				"name":         "n",
+				"enum":         "A",
+				"nullEmbedded": nil,
+				"defaultName":  "defaultFoo",
+			},
+			"default": int64(5),
+		}, vars)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, errs := coerce(t, `
+			query($required: Int!, $null: Int!, $int: Int, $big: Int, $str: String, $bool: Boolean!, $id: ID!, $obj: InputType!, $list: [InputType!]) {
+				a: intArg(i: $required) b: intArg(i: $null) c: optionalIntArg(i: $int) d: optionalIntArg(i: $big)
+				stringArg(i: $str) e: boolArg(i: $bool) idArg(i: $id) f: structArg(i: $obj) arrayArg(i: $list)
+			}`,
+			`{"null": null, "int": 1.5, "big": 3000000000, "str": 1, "bool": "yes", "id": true, "obj": {"nam": "x", "enum": "B"}, "list": [{"name": "x"}, null]}`,
+		)
+		require.Equal(t, []string{
+			`Variable "$required" of required type "Int!" was not provided.`,
+			`Variable "$null" of non-null type "Int!" must not be null.`,
+			`Variable "$int" got invalid value 1.5; Int cannot represent non-integer value: 1.5`,
+			`Variable "$big" got invalid value 3000000000; Int cannot represent non 32-bit signed integer value: 3000000000`,
+			`Variable "$str" got invalid value 1; String cannot represent a non string value: 1`,
+			`Variable "$bool" got invalid value "yes"; Boolean cannot represent a non boolean value: "yes"`,
+			`Variable "$id" got invalid value true; ID cannot represent value: true`,
+			`Variable "$obj" got invalid value {"enum":"B","nam":"x"}; Field "nam" is not defined by type "InputType". Did you mean "name" or "enum"?`,
+			`Variable "$obj" got invalid value {"enum":"B","nam":"x"}; Field "name" of required type "String!" was not provided.`,
+			`Variable "$obj" got invalid value "B" at "obj.enum"; Value "B" does not exist in "Enum" enum. Did you mean "A"?`,
+			`Variable "$list" got invalid value null at "list[1]"; Expected non-nullable type "InputType!" not to be null.`,
+		}, errs)
+	})
+
+	t.Run("custom scalars", func(t *testing.T) {
+		query := `query($c: Custom!) { scalarArg(i: $c) }`
+
+		vars, errs := coerce(t, query, `{"c": {"any": "thing"}}`)
+		require.Empty(t, errs)
+		require.Equal(t, map[string]interface{}{"any": "thing"}, vars["c"])
+
+		upper := validator.WithScalarCoercer("Custom", func(value interface{}) (interface{}, error) {
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("Custom must be a string")
+			}
+			return s + "!", nil
+		})
+		vars, errs = coerce(t, query, `{"c": "x"}`, upper)
+		require.Empty(t, errs)
+		require.Equal(t, "x!", vars["c"])
+
+		_, errs = coerce(t, query, `{"c": 1}`, upper)
+		require.Equal(t, []string{`Variable "$c" got invalid value 1; Custom must be a string`}, errs)
+	})
+
+	t.Run("invalid default values", func(t *testing.T) {
+		upper := validator.WithScalarCoercer("Custom", func(value interface{}) (interface{}, error) {
+			if _, ok := value.(string); !ok {
+				return nil, fmt.Errorf("Custom must be a string")
+			}
+			return value, nil
+		})
+		vars, errs := coerce(t, `query($c: Custom = 1) { scalarArg(i: $c) }`, `{}`, upper)
+		require.Equal(t, []string{`Variable "$c" has invalid default value 1; Custom must be a string`}, errs)
+		require.NotContains(t, vars, "c")
+
+		schema := gqlparser.MustLoadSchema(&ast.Source{Input: `
+			type Query { field(i: Limits): Int }
+			input Limits { max: Int = 99999999999999999999 }
+		`})
+		// the query isn't validated, validation rejects the default of $max
+		q, err := parser.ParseQuery(&ast.Source{Input: `query($limits: Limits, $max: Int = 99999999999999999999) { a: field(i: $limits) b: field(i: {max: $max}) }`})
+		require.NoError(t, err)
+		_, list := validator.CoerceVariables(schema, q.Operations[0], map[string]interface{}{"limits": map[string]interface{}{}})
+		require.Len(t, list, 2)
+		require.Equal(t, `Variable "$limits" got invalid value {}; Field "max" has an invalid default value: strconv.ParseInt: parsing "99999999999999999999": value out of range`, list[0].Message)
+		require.Equal(t, `Variable "$max" has an invalid default value: strconv.ParseInt: parsing "99999999999999999999": value out of range`, list[1].Message)
+	})
+
+	t.Run("does not modify raw values", func(t *testing.T) {
+		q := gqlparser.MustLoadQuery(schema, `query($obj: InputType!) { structArg(i: $obj) }`)
+		obj := map[string]interface{}{"name": "n"}
+		_, errs := validator.CoerceVariables(schema, q.Operations.ForName(""), map[string]interface{}{"obj": obj})
+		require.Empty(t, errs)
+		require.Equal(t, map[string]interface{}{"name": "n"}, obj)
+	})
+}
@@ -24,12 +24,35 @@ func At(position *ast.Position) ErrorOption {
 			Line:   position.Line,
 			Column: position.Column,
 		})
-		if position.Src.Name != "" {
+		if position.Src != nil {
 			err.SetFile(position.Src.Name)
 		}
 	}
 }
 
+// Nodes attaches the AST nodes the error is about, see gqlerror.Error.Nodes.
+func Nodes(nodes ...interface{}) ErrorOption {
+	return func(err *gqlerror.Error) {
+		err.AddNodes(nodes...)
+	}
+}
+
+// Code sets the error code, by default errors get a code derived from the name of their rule, see
+// gqlerror.RuleCode.
+func Code(code string) ErrorOption {
+	return func(err *gqlerror.Error) {
+		err.SetCode(code)
+	}
+}
+
+// Severity reports the error as a warning or notice rather than a fatal error, see
+// gqlerror.Severity.
+func Severity(severity gqlerror.Severity) ErrorOption {
+	return func(err *gqlerror.Error) {
+		err.SetSeverity(severity)
+	}
+}
+
 func SuggestListQuoted(prefix string, typed string, suggestions []string) ErrorOption {
 	suggested := SuggestionList(typed, suggestions)
 	return func(err *gqlerror.Error) {
@@ -53,3 +76,11 @@ func Suggestf(suggestion string, args ...interface{}) ErrorOption {
 		err.Message += " Did you mean " + fmt.Sprintf(suggestion, args...) + "?"
 	}
 }
+
+// Fix proposes a fix for the error, made of edits to the source the error is in, see
+// gqlerror.Error.AddFix.
+func Fix(message string, edits ...gqlerror.TextEdit) ErrorOption {
+	return func(err *gqlerror.Error) {
+		err.AddFix(gqlerror.Fix{Message: message, Edits: edits})
+	}
+}
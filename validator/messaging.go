@@ -37,3 +37,12 @@ func OrList(items ...string) string {
 	}
 	return buf.String()
 }
+
+// Given [ A, B ] return ' Did you mean "A" or "B"?', or an empty string when
+// there are no suggestions, ready to be appended to an error message.
+func DidYouMean(suggestions ...string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return " Did you mean " + QuotedOrList(suggestions...) + "?"
+}
@@ -23,4 +23,11 @@ func TestMessaging(t *testing.T) {
 		assert.Equal(t, `"A", "B", or "C"`, QuotedOrList("A", "B", "C"))
 		assert.Equal(t, `"A", "B", "C", or "D"`, QuotedOrList("A", "B", "C", "D"))
 	})
+
+	t.Run("DidYouMean", func(t *testing.T) {
+		assert.Equal(t, ``, DidYouMean())
+		assert.Equal(t, ` Did you mean "A"?`, DidYouMean("A"))
+		assert.Equal(t, ` Did you mean "A" or "B"?`, DidYouMean("A", "B"))
+		assert.Equal(t, ` Did you mean "A", "B", or "C"?`, DidYouMean("A", "B", "C"))
+	})
 }
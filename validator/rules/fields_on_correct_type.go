@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/lexer"
 
 	//nolint:revive // Validator rules each use dot imports for convenience.
 	. "github.com/vektah/gqlparser/v2/validator"
@@ -13,23 +14,33 @@ import (
 
 func init() {
 	AddRule("FieldsOnCorrectType", func(observers *Events, addError AddErrFunc) {
+		var sources lexer.SourceTokens
 		observers.OnField(func(walker *Walker, field *ast.Field) {
 			if field.ObjectDefinition == nil || field.Definition != nil {
 				return
 			}
 
 			message := fmt.Sprintf(`Cannot query field "%s" on type "%s".`, field.Name, field.ObjectDefinition.Name)
+			options := []ErrorOption{At(field.Position), Nodes(field)}
 
 			if suggestedTypeNames := getSuggestedTypeNames(walker, field.ObjectDefinition, field.Name); suggestedTypeNames != nil {
 				message += " Did you mean to use an inline fragment on " + QuotedOrList(suggestedTypeNames...) + "?"
 			} else if suggestedFieldNames := getSuggestedFieldNames(field.ObjectDefinition, field.Name); suggestedFieldNames != nil {
 				message += " Did you mean " + QuotedOrList(suggestedFieldNames...) + "?"
+
+				// the position of a field is its alias, the name follows the colon
+				tokens, i := sources.At(field.Position)
+				if colon := nextToken(tokens, i); colon >= 0 && tokens[colon].Kind == lexer.Colon {
+					i = nextToken(tokens, colon)
+				}
+				if i >= 0 && tokens[i].Value == field.Name {
+					for _, name := range suggestedFieldNames {
+						options = append(options, Fix(fmt.Sprintf(`Replace with "%s"`, name), replaceToken(tokens, i, name)))
+					}
+				}
 			}
 
-			addError(
-				Message(message),
-				At(field.Position),
-			)
+			addError(append(options, Message(message))...)
 		})
 	})
 }
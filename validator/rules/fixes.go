@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
+)
+
+// nextToken returns the index of the first token after i that isn't a comment, or -1.
+func nextToken(tokens []lexer.Token, i int) int {
+	if i < 0 {
+		return -1
+	}
+	for i++; i < len(tokens); i++ {
+		if tokens[i].Kind != lexer.Comment {
+			return i
+		}
+	}
+	return -1
+}
+
+// prevToken returns the index of the last token before i that isn't a comment, or -1.
+func prevToken(tokens []lexer.Token, i int) int {
+	for i--; i >= 0; i-- {
+		if tokens[i].Kind != lexer.Comment {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceToken returns the edit replacing tokens[i] with text.
+func replaceToken(tokens []lexer.Token, i int, text string) gqlerror.TextEdit {
+	return gqlerror.TextEdit{Start: tokens[i].Pos.Start, End: tokens[i].Pos.End, NewText: text}
+}
+
+// removeVariableDefinitions returns the edits removing the unused definitions of defs, indexed
+// like defs, or nil if the definitions can't be found in their source.
+//
+// Each edit removes its definition along with one separator, so it leaves a clean document on its
+// own, and no two edits overlap, so they can all be applied together: the definitions before the
+// first one that is kept go with the separator after them, the others with the separator before
+// them. When every definition is unused, each edit removes them all, parentheses included.
+func removeVariableDefinitions(sources *lexer.SourceTokens, defs ast.VariableDefinitionList) []gqlerror.TextEdit {
+	if len(defs) == 0 {
+		return nil
+	}
+
+	// the start of each definition, at its $, and the end of its last token
+	starts, ends := make([]int, len(defs)), make([]int, len(defs))
+	var tokens []lexer.Token
+	opening, closing := -1, -1
+	for n, def := range defs {
+		var i int
+		tokens, i = sources.At(def.Position)
+		if i < 0 || tokens[i].Kind != lexer.Dollar {
+			return nil
+		}
+		if n == 0 {
+			opening = prevToken(tokens, i)
+		}
+		closing = definitionEnd(tokens, i)
+		if closing < 0 {
+			return nil
+		}
+		starts[n], ends[n] = tokens[i].Pos.Start, tokens[prevToken(tokens, closing)].Pos.End
+	}
+	if opening < 0 || tokens[opening].Kind != lexer.ParenL || tokens[closing].Kind != lexer.ParenR {
+		return nil
+	}
+
+	edits := make([]gqlerror.TextEdit, len(defs))
+	kept := -1
+	for i, def := range defs {
+		if def.Used {
+			kept = i
+			break
+		}
+	}
+	for i, def := range defs {
+		switch {
+		case def.Used:
+		case kept < 0:
+			edits[i] = gqlerror.TextEdit{Start: tokens[opening].Pos.Start, End: tokens[closing].Pos.End}
+		case i < kept:
+			edits[i] = gqlerror.TextEdit{Start: starts[i], End: starts[i+1]}
+		default:
+			edits[i] = gqlerror.TextEdit{Start: ends[i-1], End: ends[i]}
+		}
+	}
+	return edits
+}
+
+// definitionEnd returns the index of the $ of the variable definition following the one starting
+// at the $ tokens[i], or of the closing parenthesis, or -1.
+func definitionEnd(tokens []lexer.Token, i int) int {
+	depth := 0
+	for next := nextToken(tokens, i); next >= 0; next = nextToken(tokens, next) {
+		kind := tokens[next].Kind
+		if depth == 0 && (kind == lexer.Dollar || kind == lexer.ParenR) {
+			return next
+		}
+		switch kind {
+		case lexer.ParenL, lexer.BracketL, lexer.BraceL:
+			depth++
+		case lexer.ParenR, lexer.BracketR, lexer.BraceR:
+			depth--
+		}
+	}
+	return -1
+}
@@ -22,6 +22,7 @@ func init() {
 			addError(
 				Message(message),
 				At(inlineFragment.Position),
+				Nodes(inlineFragment),
 			)
 		})
 
@@ -35,6 +36,7 @@ func init() {
 			addError(
 				Message(message),
 				At(fragment.Position),
+				Nodes(fragment),
 			)
 		})
 	})
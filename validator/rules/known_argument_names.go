@@ -29,6 +29,7 @@ func init() {
 					Message(`Unknown argument "%s" on field "%s.%s".`, arg.Name, field.ObjectDefinition.Name, field.Name),
 					SuggestListQuoted("Did you mean", arg.Name, suggestions),
 					At(field.Position),
+					Nodes(arg),
 				)
 			}
 		})
@@ -52,6 +53,7 @@ func init() {
 					Message(`Unknown argument "%s" on directive "@%s".`, arg.Name, directive.Name),
 					SuggestListQuoted("Did you mean", arg.Name, suggestions),
 					At(directive.Position),
+					Nodes(arg),
 				)
 			}
 		})
@@ -20,6 +20,7 @@ func init() {
 				addError(
 					Message(`Unknown directive "@%s".`, directive.Name),
 					At(directive.Position),
+					Nodes(directive),
 				)
 				return
 			}
@@ -41,6 +42,7 @@ func init() {
 				addError(
 					Message(`Directive "@%s" may not be used on %s.`, directive.Name, directive.Location),
 					At(directive.Position),
+					Nodes(directive),
 				)
 				seen[tmp] = true
 			}
@@ -14,6 +14,7 @@ func init() {
 				addError(
 					Message(`Unknown fragment "%s".`, fragmentSpread.Name),
 					At(fragmentSpread.Position),
+					Nodes(fragmentSpread),
 				)
 			}
 		})
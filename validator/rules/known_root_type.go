@@ -30,7 +30,8 @@ func init() {
 			if def == nil {
 				addError(
 					Message(`Schema does not support operation type "%s"`, operation.Operation),
-					At(operation.Position))
+					At(operation.Position),
+					Nodes(operation))
 			}
 		})
 	})
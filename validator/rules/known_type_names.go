@@ -19,6 +19,7 @@ func init() {
 			addError(
 				Message(`Unknown type "%s".`, typeName),
 				At(variable.Position),
+				Nodes(variable),
 			)
 		})
 
@@ -36,6 +37,7 @@ func init() {
 			addError(
 				Message(`Unknown type "%s".`, typedName),
 				At(inlineFragment.Position),
+				Nodes(inlineFragment),
 			)
 		})
 
@@ -55,6 +57,7 @@ func init() {
 				Message(`Unknown type "%s".`, typeName),
 				SuggestListQuoted("Did you mean", typeName, possibleTypes),
 				At(fragment.Position),
+				Nodes(fragment),
 			)
 		})
 	})
@@ -0,0 +1,301 @@
+package validator_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/complexity"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+	rules "github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+var limitsSchema = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: `
+	type Query {
+		user(id: ID): User
+		users(first: Int): [User!]! @listSize(assumedSize: 50, slicingArguments: ["first"])
+		version: String
+	}
+	type User {
+		id: ID!
+		name: String
+		friends(first: Int): [User!]! @cost(weight: "5")
+	}
+	directive @cost(weight: String!) on FIELD_DEFINITION | OBJECT
+	directive @listSize(assumedSize: Int, slicingArguments: [String!]) on FIELD_DEFINITION
+	directive @a on QUERY | FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT | FRAGMENT_DEFINITION | VARIABLE_DEFINITION
+	directive @b on QUERY | FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT | FRAGMENT_DEFINITION | VARIABLE_DEFINITION
+	directive @c on QUERY | FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT | FRAGMENT_DEFINITION | VARIABLE_DEFINITION
+	directive @d on QUERY | FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT | FRAGMENT_DEFINITION | VARIABLE_DEFINITION
+`})
+
+func validate(t *testing.T, query string, extra ...validator.Rule) gqlerror.List {
+	t.Helper()
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+	require.NoError(t, err)
+	return validator.ValidateWithRules(limitsSchema, doc, extra...).Errors
+}
+
+func TestMaxDepth(t *testing.T) {
+	require.Empty(t, validate(t, `{ user { friends { name } } }`, rules.MaxDepth(3)))
+
+	errs := validate(t, `{ user { friends { friends { name } } } }`, rules.MaxDepth(3))
+	require.Len(t, errs, 1)
+	require.Equal(t, `query.graphql:1:30: Field "name" exceeds the maximum depth of 3.`, errs[0].Error())
+	require.Equal(t, "MaxDepth", errs[0].Rule)
+	require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+
+	t.Run("counts through fragments", func(t *testing.T) {
+		errs := validate(t, `
+			{ users { ...A } version }
+			fragment A on User { ... on User { friends { ...B } } }
+			fragment B on User { id friends { name } }
+		`, rules.MaxDepth(3))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Field "name" exceeds the maximum depth of 3.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 38}}, errs[0].Locations)
+	})
+
+	t.Run("one error per operation", func(t *testing.T) {
+		errs := validate(t, `
+			query A { user { friends { id } } users { friends { id } } }
+			query B { version }
+			query C { users { friends { id } } }
+		`, rules.MaxDepth(2))
+		require.Len(t, errs, 2)
+	})
+
+	t.Run("not run by default", func(t *testing.T) {
+		require.Empty(t, validate(t, `{ user { friends { friends { friends { name } } } } }`))
+	})
+}
+
+func TestMaxAliases(t *testing.T) {
+	t.Run("per selection set", func(t *testing.T) {
+		limits := rules.MaxAliases(rules.AliasLimits{PerSelectionSet: 2})
+		require.Empty(t, validate(t, `{ a: version b: version version user { x: id y: id } }`, limits))
+
+		errs := validate(t, `{ a: version b: version user { x: id y: id z: id } }`, limits)
+		require.Len(t, errs, 1)
+		require.Equal(t, `query.graphql:1:25: Selection set has 3 aliases, more than the maximum of 2.`, errs[0].Error())
+		require.Equal(t, "MaxAliases", errs[0].Rule)
+		require.Equal(t, []gqlerror.Location{{Line: 1, Column: 25}}, errs[0].Locations)
+		require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+	})
+
+	t.Run("counts fragments in the selection set they are spread into", func(t *testing.T) {
+		errs := validate(t, `
+			{ user { ...A ... on User { c: id } } }
+			fragment A on User { a: id b: name }
+		`, rules.MaxAliases(rules.AliasLimits{PerSelectionSet: 2}))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Selection set has 3 aliases, more than the maximum of 2.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 6}}, errs[0].Locations)
+	})
+
+	t.Run("per field", func(t *testing.T) {
+		limits := rules.MaxAliases(rules.AliasLimits{PerField: 3})
+		require.Empty(t, validate(t, `{ a: user { name } b: user { name } c: user { name } }`, limits))
+
+		errs := validate(t, `
+			query Q { a: user { ...F } b: user { ...F } }
+			fragment F on User { x: friends { id } y: friends { id } }
+		`, limits)
+		require.Len(t, errs, 1)
+		require.Equal(t, `query.graphql:3:25: Field "User.friends" is aliased 4 times, more than the maximum of 3.`, errs[0].Error())
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 25}}, errs[0].Locations)
+
+		errs = validate(t, `{ a: user { name } b: user { name } c: user { name } d: user { name } }`, limits)
+		require.Len(t, errs, 1)
+		require.Equal(t, []gqlerror.Location{{Line: 1, Column: 3}}, errs[0].Locations)
+	})
+
+	t.Run("fields selected under their own name are not aliased", func(t *testing.T) {
+		require.Empty(t, validate(t, `{ version: version }`, rules.MaxAliases(rules.AliasLimits{PerSelectionSet: 0, PerField: 0})))
+		require.Empty(t, validate(t, `{ version: version }`, rules.MaxAliases(rules.AliasLimits{PerSelectionSet: 1, PerField: 1})))
+	})
+}
+
+func TestMaxDirectives(t *testing.T) {
+	t.Run("per node", func(t *testing.T) {
+		limits := rules.MaxDirectives(rules.DirectiveLimits{PerNode: 2})
+		require.Empty(t, validate(t, `query Q($id: ID @a @b) @a @b { user(id: $id) @a @b { id } }`, limits))
+
+		errs := validate(t, `
+			query Q($id: ID @a @b @c) { user(id: $id) @a { ...F @a @b @c } }
+			fragment F on User @a @b @c { ... on User @a @b @c { id } }
+		`, limits)
+		require.Len(t, errs, 4)
+		require.Equal(t, `Variable "$id" has 3 directives, more than the maximum of 2.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 27}}, errs[0].Locations)
+		require.Equal(t, `Fragment spread "F" has 3 directives, more than the maximum of 2.`, errs[1].Message)
+		require.Equal(t, `Fragment "F" has 3 directives, more than the maximum of 2.`, errs[2].Message)
+		require.Equal(t, `Inline fragment on "User" has 3 directives, more than the maximum of 2.`, errs[3].Message)
+		require.Equal(t, "MaxDirectives", errs[3].Rule)
+		require.ErrorIs(t, errs[3], gqlerror.ErrLimitExceeded)
+	})
+
+	t.Run("total", func(t *testing.T) {
+		limits := rules.MaxDirectives(rules.DirectiveLimits{Total: 3})
+		require.Empty(t, validate(t, `{ a: version @a b: version @a c: version @a }`, limits))
+
+		errs := validate(t, `{ a: version @a b: version @a c: version @a @b @c }`, limits)
+		require.Len(t, errs, 1)
+		require.Equal(t, `query.graphql:1:46: Document has more than 3 directives.`, errs[0].Error())
+		require.Equal(t, []gqlerror.Location{{Line: 1, Column: 46}}, errs[0].Locations)
+	})
+}
+
+func TestMaxFragments(t *testing.T) {
+	nested := `
+		{ user { ...A } }
+		fragment A on User { ...B }
+		fragment B on User { friends { ...C } }
+		fragment C on User { id }
+	`
+
+	t.Run("distinct", func(t *testing.T) {
+		require.Empty(t, validate(t, nested, rules.MaxFragments(rules.FragmentLimits{Distinct: 3})))
+
+		errs := validate(t, nested, rules.MaxFragments(rules.FragmentLimits{Distinct: 2}))
+		require.Len(t, errs, 1)
+		require.Equal(t, `query.graphql:4:37: Operation spreads more than 2 different fragments.`, errs[0].Error())
+		require.Equal(t, "MaxFragments", errs[0].Rule)
+		require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		require.Empty(t, validate(t, nested, rules.MaxFragments(rules.FragmentLimits{Depth: 3})))
+
+		errs := validate(t, nested, rules.MaxFragments(rules.FragmentLimits{Depth: 2}))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Fragment spread "C" is nested more than 2 fragments deep.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 37}}, errs[0].Locations)
+	})
+
+	t.Run("expansions", func(t *testing.T) {
+		query := `
+			{ user { ...A ...A } }
+			fragment A on User { ...B ...B }
+			fragment B on User { id }
+		`
+		require.Empty(t, validate(t, query, rules.MaxFragments(rules.FragmentLimits{Expansions: 6})))
+
+		errs := validate(t, query, rules.MaxFragments(rules.FragmentLimits{Expansions: 5}))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Operation expands to more than 5 fragment spreads.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 33}}, errs[0].Locations)
+	})
+
+	t.Run("exponential documents are checked quickly", func(t *testing.T) {
+		query := "{ user { ...F0 } }\n"
+		for i := 0; i < 40; i++ {
+			query += fmt.Sprintf("fragment F%d on User { ...F%d ...F%d }\n", i, i+1, i+1)
+		}
+		query += "fragment F40 on User { id }\n"
+
+		errs := validate(t, query, rules.MaxFragments(rules.FragmentLimits{Expansions: 1000}))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Operation expands to more than 1000 fragment spreads.`, errs[0].Message)
+	})
+}
+
+func TestMaxCost(t *testing.T) {
+	require.Empty(t, validate(t, `{ users { id } }`, rules.MaxCost(51, nil)))
+
+	errs := validate(t, `{ users { id } }`, rules.MaxCost(50, nil))
+	require.Len(t, errs, 1)
+	require.Equal(t, `query.graphql:1:1: Anonymous operation has a cost of 51, more than the maximum of 50.`, errs[0].Error())
+	require.Equal(t, "MaxCost", errs[0].Rule)
+	require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+
+	t.Run("weights and slicing arguments", func(t *testing.T) {
+		query := `query Q { users(first: 2) { friends(first: 3) { name } } }`
+		require.Empty(t, validate(t, query, rules.MaxCost(1+2*(5+3*1), nil)))
+
+		errs := validate(t, query, rules.MaxCost(16, nil))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Operation "Q" has a cost of 17, more than the maximum of 16.`, errs[0].Message)
+	})
+
+	t.Run("variables", func(t *testing.T) {
+		query := `query Q($n: Int) { users(first: $n) { id } }`
+		require.Empty(t, validate(t, query, rules.MaxCost(10, map[string]interface{}{"n": 9})))
+		require.Len(t, validate(t, query, rules.MaxCost(10, map[string]interface{}{"n": 10})), 1)
+	})
+
+	t.Run("options replace the defaults", func(t *testing.T) {
+		require.Empty(t, validate(t, `{ users { id } }`, rules.MaxCost(2, nil, complexity.WithListSizeDirective(""))))
+	})
+}
+
+func TestNoIntrospection(t *testing.T) {
+	require.Empty(t, validate(t, `{ user { __typename id } }`, rules.NoIntrospection(gqlerror.SeverityError)))
+
+	query := `
+		{ __schema { queryType { name } } ...F }
+		fragment F on Query { __type(name: "User") { name } }
+	`
+	errs := validate(t, query, rules.NoIntrospection(gqlerror.SeverityError))
+	require.Len(t, errs, 2)
+	require.Equal(t, `query.graphql:2:5: GraphQL introspection has been disabled, but the requested query contained the field "__schema".`, errs[0].Error())
+	require.Equal(t, `GraphQL introspection has been disabled, but the requested query contained the field "__type".`, errs[1].Message)
+	require.Equal(t, "NoIntrospection", errs[1].Rule)
+
+	t.Run("flagged as warnings", func(t *testing.T) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		diagnostics := validator.ValidateWithRules(limitsSchema, doc, rules.NoIntrospection(gqlerror.SeverityWarning))
+		require.Empty(t, diagnostics.Errors)
+		require.Len(t, diagnostics.Warnings, 2)
+	})
+
+	t.Run("pre-parse heuristic", func(t *testing.T) {
+		require.True(t, rules.MayUseIntrospection(query))
+		require.True(t, rules.MayUseIntrospection(`{__type(name:"User"){name}}`))
+		require.True(t, rules.MayUseIntrospection(`{ version } # __schema`))
+		require.False(t, rules.MayUseIntrospection(`{ user { __typename } }`))
+		require.False(t, rules.MayUseIntrospection(`{ my__schema __types }`))
+	})
+}
+
+func TestMaxDuplicateFields(t *testing.T) {
+	require.Empty(t, validate(t, `{ version version a: version b: version user { name name } }`, rules.MaxDuplicateFields(2)))
+
+	errs := validate(t, `{ version user { name name name } }`, rules.MaxDuplicateFields(2))
+	require.Len(t, errs, 1)
+	require.Equal(t, `query.graphql:1:18: Field "name" is selected 3 times in the same selection set, more than the maximum of 2.`, errs[0].Error())
+	require.Equal(t, []gqlerror.Location{{Line: 1, Column: 18}}, errs[0].Locations)
+	require.Equal(t, "MaxDuplicateFields", errs[0].Rule)
+	require.ErrorIs(t, errs[0], gqlerror.ErrLimitExceeded)
+
+	t.Run("counts through fragments", func(t *testing.T) {
+		errs := validate(t, `
+			{ user { id ...F ... on User { id } } }
+			fragment F on User { id }
+		`, rules.MaxDuplicateFields(2))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Field "id" is selected 3 times in the same selection set, more than the maximum of 2.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 2, Column: 13}}, errs[0].Locations)
+	})
+
+	t.Run("arguments are part of the field", func(t *testing.T) {
+		require.Empty(t, validate(t, `{ a: users(first: 1) { id } a: users(first: 1) { id } b: users(first: 2) { id } }`, rules.MaxDuplicateFields(2)))
+	})
+
+	t.Run("fragments shared by operations are reported once", func(t *testing.T) {
+		errs := validate(t, `
+			query A { ...F }
+			query B { ...F }
+			fragment F on Query { version version version }
+		`, rules.MaxDuplicateFields(2))
+		require.Len(t, errs, 1)
+		require.Equal(t, `Field "version" is selected 3 times in the same selection set, more than the maximum of 2.`, errs[0].Message)
+		require.Equal(t, []gqlerror.Location{{Line: 4, Column: 26}}, errs[0].Locations)
+	})
+}
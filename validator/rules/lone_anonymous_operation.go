@@ -14,6 +14,7 @@ func init() {
 				addError(
 					Message(`This anonymous operation must be the only defined operation.`),
 					At(operation.Position),
+					Nodes(operation),
 				)
 			}
 		})
@@ -0,0 +1,210 @@
+package validator
+
+import (
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/internal/saturating"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// AliasLimits configures the MaxAliases rule. A zero value means unlimited.
+type AliasLimits struct {
+	// PerSelectionSet caps the aliased fields of a single selection set. Fields selected through
+	// inline fragments and fragment spreads count towards the selection set they are part of.
+	PerSelectionSet int
+	// PerField caps how many times the same field, eg "Query.user", may be selected under an alias
+	// anywhere in an operation. Fragments are counted once for every time they are spread.
+	PerField int
+}
+
+// MaxAliases returns a rule rejecting documents that use more aliases than allowed by limits,
+// the usual way of making a small query produce a huge response. Errors have the
+// LIMIT_EXCEEDED code.
+//
+// The rule is a value, so limits can be fixed for a schema by building it once and passing it to
+// every ValidateWithRules call, or chosen per request.
+//
+// PerSelectionSet errors point at the selection set, PerField errors at the first aliased
+// selection of the field.
+func MaxAliases(limits AliasLimits) Rule {
+	return Rule{Name: "MaxAliases", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		// the counter is shared by every definition so that fragments are only counted once.
+		var c *aliasCounter
+		counter := func(walker *Walker) *aliasCounter {
+			if c == nil {
+				c = &aliasCounter{
+					doc:       walker.Document,
+					aliases:   map[string]int{},
+					fields:    map[string]*fieldCounts{},
+					visiting:  map[string]bool{},
+					countings: map[string]bool{},
+				}
+			}
+			return c
+		}
+
+		check := func(c *aliasCounter, set ast.SelectionSet, pos *ast.Position, node interface{}) {
+			if limits.PerSelectionSet <= 0 {
+				return
+			}
+			c.eachSelectionSet(set, pos, node, func(n int, pos *ast.Position, node interface{}) {
+				if n > limits.PerSelectionSet {
+					addError(
+						Message(`Selection set has %d aliases, more than the maximum of %d.`, n, limits.PerSelectionSet),
+						At(pos),
+						Nodes(node),
+						Code(gqlerror.CodeLimitExceeded),
+					)
+				}
+			})
+		}
+
+		// fields of fragments are counted in every operation spreading them, but each is reported
+		// once.
+		reported := map[*ast.Field]bool{}
+
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			c := counter(walker)
+			check(c, operation.SelectionSet, operation.Position, operation)
+
+			if limits.PerField <= 0 {
+				return
+			}
+			counts := &fieldCounts{counts: map[string]int{}, first: map[string]*ast.Field{}}
+			c.countFields(operation.SelectionSet, counts)
+
+			var coordinates []string
+			for _, coordinate := range counts.keys {
+				if counts.counts[coordinate] > limits.PerField {
+					coordinates = append(coordinates, coordinate)
+				}
+			}
+			sort.Strings(coordinates)
+			for _, coordinate := range coordinates {
+				field := counts.first[coordinate]
+				if reported[field] {
+					continue
+				}
+				reported[field] = true
+				addError(
+					Message(`Field "%s" is aliased %d times, more than the maximum of %d.`, coordinate, counts.counts[coordinate], limits.PerField),
+					At(field.Position),
+					Nodes(field),
+					Code(gqlerror.CodeLimitExceeded),
+				)
+			}
+		})
+
+		observers.OnFragment(func(walker *Walker, fragment *ast.FragmentDefinition) {
+			check(counter(walker), fragment.SelectionSet, fragment.Position, fragment)
+		})
+	}}
+}
+
+type aliasCounter struct {
+	doc *ast.QueryDocument
+	// aliases holds the number of aliased fields at the top level of the fragments counted so far
+	aliases map[string]int
+	// fields holds the aliased fields selected by the fragments counted so far, by coordinate
+	fields map[string]*fieldCounts
+	// visiting and countings hold the fragments being walked, to stop at cycles
+	visiting  map[string]bool
+	countings map[string]bool
+}
+
+// eachSelectionSet calls fn with the number of aliases in set and in every selection set of the
+// fields below it. Fragment spreads add to the count of the selection set they are in, but their
+// own fields are checked along with the fragment definition.
+func (c *aliasCounter) eachSelectionSet(set ast.SelectionSet, pos *ast.Position, node interface{}, fn func(n int, pos *ast.Position, node interface{})) {
+	fn(c.selectionSetAliases(set), pos, node)
+	c.eachChildSelectionSet(set, fn)
+}
+
+func (c *aliasCounter) eachChildSelectionSet(set ast.SelectionSet, fn func(n int, pos *ast.Position, node interface{})) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if len(sel.SelectionSet) > 0 {
+				c.eachSelectionSet(sel.SelectionSet, sel.Position, sel, fn)
+			}
+		case *ast.InlineFragment:
+			c.eachChildSelectionSet(sel.SelectionSet, fn)
+		}
+	}
+}
+
+// selectionSetAliases returns the number of aliased fields in set, including those selected
+// through fragments.
+func (c *aliasCounter) selectionSetAliases(set ast.SelectionSet) int {
+	n := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if isAliased(sel) {
+				n++
+			}
+		case *ast.InlineFragment:
+			n = saturating.Add(n, c.selectionSetAliases(sel.SelectionSet))
+		case *ast.FragmentSpread:
+			def := c.doc.Fragments.ForName(sel.Name)
+			if def == nil {
+				continue
+			}
+			count, ok := c.aliases[def.Name]
+			if !ok {
+				if c.visiting[def.Name] {
+					continue
+				}
+				c.visiting[def.Name] = true
+				count = c.selectionSetAliases(def.SelectionSet)
+				delete(c.visiting, def.Name)
+				c.aliases[def.Name] = count
+			}
+			n = saturating.Add(n, count)
+		}
+	}
+	return n
+}
+
+// countFields adds the number of times every field in set is selected under an alias to counts,
+// by coordinate.
+func (c *aliasCounter) countFields(set ast.SelectionSet, counts *fieldCounts) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if isAliased(sel) && sel.ObjectDefinition != nil {
+				counts.add(sel.ObjectDefinition.Name+"."+sel.Name, 1, sel)
+			}
+			c.countFields(sel.SelectionSet, counts)
+		case *ast.InlineFragment:
+			c.countFields(sel.SelectionSet, counts)
+		case *ast.FragmentSpread:
+			def := c.doc.Fragments.ForName(sel.Name)
+			if def == nil {
+				continue
+			}
+			fragment, ok := c.fields[def.Name]
+			if !ok {
+				if c.countings[def.Name] {
+					continue
+				}
+				c.countings[def.Name] = true
+				fragment = &fieldCounts{counts: map[string]int{}, first: map[string]*ast.Field{}}
+				c.countFields(def.SelectionSet, fragment)
+				delete(c.countings, def.Name)
+				c.fields[def.Name] = fragment
+			}
+			for _, coordinate := range fragment.keys {
+				counts.add(coordinate, fragment.counts[coordinate], fragment.first[coordinate])
+			}
+		}
+	}
+}
+
+func isAliased(field *ast.Field) bool {
+	return field.Alias != "" && field.Alias != field.Name
+}
@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/complexity"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// MaxCost returns a rule rejecting operations whose estimated cost is more than limit.
+//
+// Costs are estimated by complexity.EstimateOperation, reading field weights from
+// `@cost(weight:)` and list sizes from `@listSize(assumedSize:, slicingArguments:)` in the
+// schema, as described by the GraphQL cost directive specification. options are applied after
+// these and can replace them. variables holds the request variables used as list sizes, with nil
+// falling back to the assumed sizes. The error has the LIMIT_EXCEEDED code.
+func MaxCost(limit int, variables map[string]interface{}, options ...complexity.Option) Rule {
+	options = append([]complexity.Option{
+		complexity.WithCostDirective("cost"),
+		complexity.WithListSizeDirective("listSize"),
+	}, options...)
+
+	return Rule{Name: "MaxCost", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			cost := complexity.EstimateOperation(walker.Schema, walker.Document, operation, variables, options...)
+			if cost > limit {
+				addError(
+					Message(`%s has a cost of %d, more than the maximum of %d.`, describeNode(operation), cost, limit),
+					At(operation.Position),
+					Nodes(operation),
+					Code(gqlerror.CodeLimitExceeded),
+				)
+			}
+		})
+	}}
+}
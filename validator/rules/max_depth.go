@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// MaxDepth returns a rule rejecting operations that select fields nested more than limit deep,
+// counting through fragment spreads. Root fields are at depth 1. The error points at the first
+// field that is too deep and has the LIMIT_EXCEEDED code.
+func MaxDepth(limit int) Rule {
+	return Rule{Name: "MaxDepth", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			d := depthChecker{
+				doc:       walker.Document,
+				limit:     limit,
+				fragments: map[string]int{},
+				visiting:  map[string]bool{},
+			}
+			if field := d.tooDeep(operation.SelectionSet, 0); field != nil {
+				addError(
+					Message(`Field "%s" exceeds the maximum depth of %d.`, field.Name, limit),
+					At(field.Position),
+					Nodes(field),
+					Code(gqlerror.CodeLimitExceeded),
+				)
+			}
+		})
+	}}
+}
+
+type depthChecker struct {
+	doc   *ast.QueryDocument
+	limit int
+	// fragments holds the depth of the fragments measured so far
+	fragments map[string]int
+	// visiting holds the fragments being walked, to stop at cycles
+	visiting map[string]bool
+}
+
+// tooDeep returns the first field in set, which is at depth, that is nested deeper than the limit.
+func (d *depthChecker) tooDeep(set ast.SelectionSet, depth int) *ast.Field {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if depth+1 > d.limit {
+				return sel
+			}
+			if field := d.tooDeep(sel.SelectionSet, depth+1); field != nil {
+				return field
+			}
+		case *ast.InlineFragment:
+			if field := d.tooDeep(sel.SelectionSet, depth); field != nil {
+				return field
+			}
+		case *ast.FragmentSpread:
+			// fragments are only searched when they are known to be too deep, so that documents
+			// spreading the same fragments over and over are still checked quickly.
+			def := d.doc.Fragments.ForName(sel.Name)
+			if def == nil || depth+d.depth(def) <= d.limit || d.visiting[def.Name] {
+				continue
+			}
+			d.visiting[def.Name] = true
+			field := d.tooDeep(def.SelectionSet, depth)
+			delete(d.visiting, def.Name)
+			if field != nil {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// depth returns how deep the fields of fragment are nested.
+func (d *depthChecker) depth(fragment *ast.FragmentDefinition) int {
+	if depth, ok := d.fragments[fragment.Name]; ok {
+		return depth
+	}
+	// a fragment spread within itself counts as empty, such cycles are reported by another rule.
+	d.fragments[fragment.Name] = 0
+	depth := d.selectionSetDepth(fragment.SelectionSet)
+	d.fragments[fragment.Name] = depth
+	return depth
+}
+
+func (d *depthChecker) selectionSetDepth(set ast.SelectionSet) int {
+	max := 0
+	for _, sel := range set {
+		depth := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			depth = 1 + d.selectionSetDepth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			depth = d.selectionSetDepth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if def := d.doc.Fragments.ForName(sel.Name); def != nil {
+				depth = d.depth(def)
+			}
+		}
+		if depth > max {
+			max = depth
+		}
+	}
+	return max
+}
@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// DirectiveLimits configures the MaxDirectives rule. A zero value means unlimited.
+type DirectiveLimits struct {
+	// Total caps the directives applied anywhere in the document.
+	Total int
+	// PerNode caps the directives applied to a single operation, variable definition, field,
+	// fragment or fragment spread.
+	PerNode int
+}
+
+// MaxDirectives returns a rule rejecting documents that apply more directives than allowed by
+// limits. Every directive is checked against its definition by several rules, so repeating
+// cheap directives is an easy way to make validation itself expensive. Errors name the node that
+// went over the limit and have the LIMIT_EXCEEDED code.
+func MaxDirectives(limits DirectiveLimits) Rule {
+	return Rule{Name: "MaxDirectives", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		total := 0
+		check := func(node interface{}, directives ast.DirectiveList) {
+			if limits.PerNode > 0 && len(directives) > limits.PerNode {
+				addError(
+					Message(`%s has %d directives, more than the maximum of %d.`, describeNode(node), len(directives), limits.PerNode),
+					At(directives[limits.PerNode].Position),
+					Nodes(node),
+					Code(gqlerror.CodeLimitExceeded),
+				)
+			}
+
+			if limits.Total > 0 && total <= limits.Total && total+len(directives) > limits.Total {
+				directive := directives[limits.Total-total]
+				addError(
+					Message(`Document has more than %d directives.`, limits.Total),
+					At(directive.Position),
+					Nodes(directive),
+					Code(gqlerror.CodeLimitExceeded),
+				)
+			}
+			total += len(directives)
+		}
+
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			check(operation, operation.Directives)
+			for _, v := range operation.VariableDefinitions {
+				check(v, v.Directives)
+			}
+			eachDirectiveList(operation.SelectionSet, check)
+		})
+
+		observers.OnFragment(func(walker *Walker, fragment *ast.FragmentDefinition) {
+			check(fragment, fragment.Directives)
+			eachDirectiveList(fragment.SelectionSet, check)
+		})
+	}}
+}
+
+// eachDirectiveList calls fn with the directives of every selection in set and below it. Fragment
+// spreads are not followed.
+func eachDirectiveList(set ast.SelectionSet, fn func(node interface{}, directives ast.DirectiveList)) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			fn(sel, sel.Directives)
+			eachDirectiveList(sel.SelectionSet, fn)
+		case *ast.InlineFragment:
+			fn(sel, sel.Directives)
+			eachDirectiveList(sel.SelectionSet, fn)
+		case *ast.FragmentSpread:
+			fn(sel, sel.Directives)
+		}
+	}
+}
+
+// describeNode names node for use at the start of an error message.
+func describeNode(node interface{}) string {
+	switch node := node.(type) {
+	case *ast.OperationDefinition:
+		if node.Name == "" {
+			return "Anonymous operation"
+		}
+		return fmt.Sprintf(`Operation "%s"`, node.Name)
+	case *ast.VariableDefinition:
+		return fmt.Sprintf(`Variable "$%s"`, node.Variable)
+	case *ast.Field:
+		return fmt.Sprintf(`Field "%s"`, node.Alias)
+	case *ast.InlineFragment:
+		if node.TypeCondition == "" {
+			return "Inline fragment"
+		}
+		return fmt.Sprintf(`Inline fragment on "%s"`, node.TypeCondition)
+	case *ast.FragmentSpread:
+		return fmt.Sprintf(`Fragment spread "%s"`, node.Name)
+	case *ast.FragmentDefinition:
+		return fmt.Sprintf(`Fragment "%s"`, node.Name)
+	}
+	return fmt.Sprintf("%T", node)
+}
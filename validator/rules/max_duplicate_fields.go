@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/internal/saturating"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// MaxDuplicateFields returns a rule rejecting selection sets that repeat the same field, with the
+// same alias and arguments, more than limit times. Such repeats are merged into a single
+// response entry, so they add nothing but the cost of checking and executing them over and
+// over, while passing depth checks. Fields selected through inline fragments and fragment
+// spreads count towards the selection set they are part of.
+//
+// The error points at the first selection of the repeated field and has the LIMIT_EXCEEDED code.
+func MaxDuplicateFields(limit int) Rule {
+	return Rule{Name: "MaxDuplicateFields", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		var d *duplicateCounter
+		// the fields of fragments are counted in every operation spreading them and once more
+		// for the fragment definition, but each is reported once.
+		reported := map[*ast.Field]bool{}
+
+		check := func(walker *Walker, set ast.SelectionSet) {
+			if d == nil {
+				d = &duplicateCounter{
+					doc:       walker.Document,
+					fragments: map[string]*fieldCounts{},
+					visiting:  map[string]bool{},
+				}
+			}
+			d.eachSelectionSet(set, func(counts *fieldCounts) {
+				for _, key := range counts.keys {
+					if n := counts.counts[key]; n > limit {
+						field := counts.first[key]
+						if reported[field] {
+							continue
+						}
+						reported[field] = true
+						addError(
+							Message(`Field "%s" is selected %d times in the same selection set, more than the maximum of %d.`, field.Alias, n, limit),
+							At(field.Position),
+							Nodes(field),
+							Code(gqlerror.CodeLimitExceeded),
+						)
+					}
+				}
+			})
+		}
+
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			check(walker, operation.SelectionSet)
+		})
+		observers.OnFragment(func(walker *Walker, fragment *ast.FragmentDefinition) {
+			check(walker, fragment.SelectionSet)
+		})
+	}}
+}
+
+// fieldCounts holds how many times every field of a selection set is selected, keyed by alias,
+// name and arguments.
+type fieldCounts struct {
+	keys   []string
+	counts map[string]int
+	first  map[string]*ast.Field
+}
+
+func (c *fieldCounts) add(key string, n int, first *ast.Field) {
+	if _, ok := c.counts[key]; !ok {
+		c.keys = append(c.keys, key)
+		c.first[key] = first
+	}
+	c.counts[key] = saturating.Add(c.counts[key], n)
+}
+
+type duplicateCounter struct {
+	doc *ast.QueryDocument
+	// fragments holds the fields at the top level of the fragments counted so far
+	fragments map[string]*fieldCounts
+	// visiting holds the fragments being walked, to stop at cycles
+	visiting map[string]bool
+}
+
+// eachSelectionSet calls fn with the counts of set and of every selection set of the fields below
+// it. The fields of spread fragments are checked along with the fragment definition.
+func (d *duplicateCounter) eachSelectionSet(set ast.SelectionSet, fn func(counts *fieldCounts)) {
+	counts := &fieldCounts{counts: map[string]int{}, first: map[string]*ast.Field{}}
+	d.count(set, counts)
+	fn(counts)
+	d.eachChildSelectionSet(set, fn)
+}
+
+func (d *duplicateCounter) eachChildSelectionSet(set ast.SelectionSet, fn func(counts *fieldCounts)) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if len(sel.SelectionSet) > 0 {
+				d.eachSelectionSet(sel.SelectionSet, fn)
+			}
+		case *ast.InlineFragment:
+			d.eachChildSelectionSet(sel.SelectionSet, fn)
+		}
+	}
+}
+
+// count adds the fields of set to counts, including those selected through fragments.
+func (d *duplicateCounter) count(set ast.SelectionSet, counts *fieldCounts) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			counts.add(fieldKey(sel), 1, sel)
+		case *ast.InlineFragment:
+			d.count(sel.SelectionSet, counts)
+		case *ast.FragmentSpread:
+			def := d.doc.Fragments.ForName(sel.Name)
+			if def == nil {
+				continue
+			}
+			fragment, ok := d.fragments[def.Name]
+			if !ok {
+				if d.visiting[def.Name] {
+					continue
+				}
+				d.visiting[def.Name] = true
+				fragment = &fieldCounts{counts: map[string]int{}, first: map[string]*ast.Field{}}
+				d.count(def.SelectionSet, fragment)
+				delete(d.visiting, def.Name)
+				d.fragments[def.Name] = fragment
+			}
+			for _, key := range fragment.keys {
+				counts.add(key, fragment.counts[key], fragment.first[key])
+			}
+		}
+	}
+}
+
+// fieldKey identifies field by its alias, name and arguments, in any order.
+func fieldKey(field *ast.Field) string {
+	args := make([]string, 0, len(field.Arguments))
+	for _, arg := range field.Arguments {
+		args = append(args, arg.Name+":"+arg.Value.String())
+	}
+	sort.Strings(args)
+	return field.Alias + ":" + field.Name + "(" + strings.Join(args, ",") + ")"
+}
@@ -0,0 +1,205 @@
+package validator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/internal/saturating"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// FragmentLimits configures the MaxFragments rule. A zero value means unlimited.
+type FragmentLimits struct {
+	// Distinct caps the number of different fragments an operation spreads, directly or through
+	// other fragments.
+	Distinct int
+	// Expansions caps the number of spreads in an operation once every fragment is expanded. A
+	// fragment spread twice in a fragment that is itself spread twice counts four times.
+	Expansions int
+	// Depth caps how deeply spreads are nested, spreads in the operation being at depth 1.
+	Depth int
+}
+
+// MaxFragments returns a rule rejecting operations whose fragment spreads go over limits. Nesting
+// fragments that spread each other several times grows the expanded operation exponentially with
+// the size of the document. Errors point at the first spread over the limit and have the
+// LIMIT_EXCEEDED code.
+func MaxFragments(limits FragmentLimits) Rule {
+	return Rule{Name: "MaxFragments", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		var f *fragmentCounter
+
+		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			if f == nil {
+				f = &fragmentCounter{
+					doc:        walker.Document,
+					expansions: map[string]int{},
+					depths:     map[string]int{},
+					visiting:   map[string]bool{},
+				}
+			}
+
+			if limits.Distinct > 0 {
+				if spread := f.distinctOver(operation.SelectionSet, map[string]bool{}, limits.Distinct); spread != nil {
+					addError(
+						Message(`Operation spreads more than %d different fragments.`, limits.Distinct),
+						At(spread.Position),
+						Nodes(spread),
+						Code(gqlerror.CodeLimitExceeded),
+					)
+				}
+			}
+
+			if limits.Expansions > 0 {
+				count := 0
+				if spread := f.expansionsOver(operation.SelectionSet, &count, limits.Expansions); spread != nil {
+					addError(
+						Message(`Operation expands to more than %d fragment spreads.`, limits.Expansions),
+						At(spread.Position),
+						Nodes(spread),
+						Code(gqlerror.CodeLimitExceeded),
+					)
+				}
+			}
+
+			if limits.Depth > 0 {
+				if spread := f.depthOver(operation.SelectionSet, 0, limits.Depth); spread != nil {
+					addError(
+						Message(`Fragment spread "%s" is nested more than %d fragments deep.`, spread.Name, limits.Depth),
+						At(spread.Position),
+						Nodes(spread),
+						Code(gqlerror.CodeLimitExceeded),
+					)
+				}
+			}
+		})
+	}}
+}
+
+type fragmentCounter struct {
+	doc *ast.QueryDocument
+	// expansions and depths hold the measurements of the fragments seen so far
+	expansions map[string]int
+	depths     map[string]int
+	// visiting holds the fragments being walked, to stop at cycles
+	visiting map[string]bool
+}
+
+// distinctOver returns the spread of the first fragment past the limit of different fragments,
+// adding the fragments found to seen.
+func (f *fragmentCounter) distinctOver(set ast.SelectionSet, seen map[string]bool, limit int) *ast.FragmentSpread {
+	return f.eachSpread(set, func(spread *ast.FragmentSpread) *ast.FragmentSpread {
+		if seen[spread.Name] {
+			return nil
+		}
+		seen[spread.Name] = true
+		if len(seen) > limit {
+			return spread
+		}
+		if def := f.doc.Fragments.ForName(spread.Name); def != nil {
+			return f.distinctOver(def.SelectionSet, seen, limit)
+		}
+		return nil
+	})
+}
+
+// expansionsOver returns the spread at which the number of expanded spreads, kept in count, goes
+// past limit.
+func (f *fragmentCounter) expansionsOver(set ast.SelectionSet, count *int, limit int) *ast.FragmentSpread {
+	return f.eachSpread(set, func(spread *ast.FragmentSpread) *ast.FragmentSpread {
+		*count++
+		if *count > limit {
+			return spread
+		}
+		def := f.doc.Fragments.ForName(spread.Name)
+		if def == nil {
+			return nil
+		}
+		// fragments are only searched when they are known to go over the limit, so that documents
+		// spreading the same fragments over and over are still checked quickly.
+		if n := f.expansionCount(def); *count <= limit-n || f.visiting[def.Name] {
+			*count += n
+			return nil
+		}
+		f.visiting[def.Name] = true
+		defer delete(f.visiting, def.Name)
+		return f.expansionsOver(def.SelectionSet, count, limit)
+	})
+}
+
+// depthOver returns the first spread nested more than limit fragments deep in set, which is
+// depth fragments deep.
+func (f *fragmentCounter) depthOver(set ast.SelectionSet, depth int, limit int) *ast.FragmentSpread {
+	return f.eachSpread(set, func(spread *ast.FragmentSpread) *ast.FragmentSpread {
+		if depth+1 > limit {
+			return spread
+		}
+		def := f.doc.Fragments.ForName(spread.Name)
+		if def == nil || depth+1+f.depth(def) <= limit || f.visiting[def.Name] {
+			return nil
+		}
+		f.visiting[def.Name] = true
+		defer delete(f.visiting, def.Name)
+		return f.depthOver(def.SelectionSet, depth+1, limit)
+	})
+}
+
+// expansionCount returns the number of spreads in fragment once every fragment in it is expanded.
+func (f *fragmentCounter) expansionCount(fragment *ast.FragmentDefinition) int {
+	if n, ok := f.expansions[fragment.Name]; ok {
+		return n
+	}
+	// a fragment spread within itself counts as empty, such cycles are reported by another rule.
+	f.expansions[fragment.Name] = 0
+	n := 0
+	f.eachSpread(fragment.SelectionSet, func(spread *ast.FragmentSpread) *ast.FragmentSpread {
+		n = saturating.Add(n, 1)
+		if def := f.doc.Fragments.ForName(spread.Name); def != nil {
+			n = saturating.Add(n, f.expansionCount(def))
+		}
+		return nil
+	})
+	f.expansions[fragment.Name] = n
+	return n
+}
+
+// depth returns how deeply spreads are nested within fragment.
+func (f *fragmentCounter) depth(fragment *ast.FragmentDefinition) int {
+	if depth, ok := f.depths[fragment.Name]; ok {
+		return depth
+	}
+	f.depths[fragment.Name] = 0
+	max := 0
+	f.eachSpread(fragment.SelectionSet, func(spread *ast.FragmentSpread) *ast.FragmentSpread {
+		depth := 1
+		if def := f.doc.Fragments.ForName(spread.Name); def != nil {
+			depth += f.depth(def)
+		}
+		if depth > max {
+			max = depth
+		}
+		return nil
+	})
+	f.depths[fragment.Name] = max
+	return max
+}
+
+// eachSpread calls fn with every fragment spread in set, without following them, until fn
+// returns a spread.
+func (f *fragmentCounter) eachSpread(set ast.SelectionSet, fn func(spread *ast.FragmentSpread) *ast.FragmentSpread) *ast.FragmentSpread {
+	for _, sel := range set {
+		var found *ast.FragmentSpread
+		switch sel := sel.(type) {
+		case *ast.Field:
+			found = f.eachSpread(sel.SelectionSet, fn)
+		case *ast.InlineFragment:
+			found = f.eachSpread(sel.SelectionSet, fn)
+		case *ast.FragmentSpread:
+			found = fn(sel)
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
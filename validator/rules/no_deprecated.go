@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+func init() {
+	AddRule("NoDeprecated", func(observers *Events, addError AddErrFunc) {
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			if field.Definition == nil || field.ObjectDefinition == nil {
+				return
+			}
+
+			if reason, ok := deprecationReason(field.Definition.Directives); ok {
+				addError(
+					Message(`The field %s.%s is deprecated. %s`, field.ObjectDefinition.Name, field.Name, reason),
+					Severity(gqlerror.SeverityWarning),
+					At(field.Position),
+					Nodes(field),
+				)
+			}
+
+			for _, arg := range field.Arguments {
+				def := field.Definition.Arguments.ForName(arg.Name)
+				if def == nil {
+					continue
+				}
+				if reason, ok := deprecationReason(def.Directives); ok {
+					addError(
+						Message(`Field "%s.%s" argument "%s" is deprecated. %s`, field.ObjectDefinition.Name, field.Name, arg.Name, reason),
+						Severity(gqlerror.SeverityWarning),
+						At(arg.Position),
+						Nodes(arg),
+					)
+				}
+			}
+		})
+
+		observers.OnValue(func(walker *Walker, value *ast.Value) {
+			if value.Definition == nil {
+				return
+			}
+
+			switch {
+			case value.Kind == ast.ObjectValue && value.Definition.Kind == ast.InputObject:
+				for _, child := range value.Children {
+					def := value.Definition.Fields.ForName(child.Name)
+					if def == nil {
+						continue
+					}
+					if reason, ok := deprecationReason(def.Directives); ok {
+						addError(
+							Message(`The input field %s.%s is deprecated. %s`, value.Definition.Name, child.Name, reason),
+							Severity(gqlerror.SeverityWarning),
+							At(child.Position),
+							Nodes(child),
+						)
+					}
+				}
+
+			case value.Kind == ast.EnumValue && value.Definition.Kind == ast.Enum:
+				def := value.Definition.EnumValues.ForName(value.Raw)
+				if def == nil {
+					return
+				}
+				if reason, ok := deprecationReason(def.Directives); ok {
+					addError(
+						Message(`The enum value "%s.%s" is deprecated. %s`, value.Definition.Name, value.Raw, reason),
+						Severity(gqlerror.SeverityWarning),
+						At(value.Position),
+						Nodes(value),
+					)
+				}
+			}
+		})
+	})
+}
+
+// deprecationReason returns the reason given by a @deprecated directive in directives, if any.
+func deprecationReason(directives ast.DirectiveList) (string, bool) {
+	deprecated := directives.ForName("deprecated")
+	if deprecated == nil {
+		return "", false
+	}
+	if reason := deprecated.Arguments.ForName("reason"); reason != nil && reason.Value != nil {
+		return reason.Value.Raw, true
+	}
+	return "No longer supported", true
+}
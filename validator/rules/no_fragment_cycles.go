@@ -56,6 +56,7 @@ func init() {
 						addError(
 							Message(`Cannot spread fragment "%s" within itself%s.`, spreadName, via),
 							At(spreadNode.Position),
+							Nodes(spreadNode),
 						)
 					}
 
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	//nolint:revive // Validator rules each use dot imports for convenience.
+	. "github.com/vektah/gqlparser/v2/validator"
+)
+
+// NoIntrospection returns a rule reporting every __schema and __type field, so that
+// introspection can be turned off by configuration. With gqlerror.SeverityError the document is
+// rejected, with a lower severity the fields are only flagged and can be found in the
+// diagnostics returned by ValidateWithRules.
+//
+// __typename is not reported, clients need it to tell apart the members of abstract types.
+func NoIntrospection(severity gqlerror.Severity) Rule {
+	return Rule{Name: "NoIntrospection", RuleFunc: func(observers *Events, addError AddErrFunc) {
+		// fields of fragments are seen once for every operation spreading them and once more
+		// for the fragment definition.
+		reported := map[*ast.Field]bool{}
+
+		observers.OnField(func(walker *Walker, field *ast.Field) {
+			if !isIntrospectionField(field.Name) || reported[field] {
+				return
+			}
+			reported[field] = true
+
+			addError(
+				Message(`GraphQL introspection has been disabled, but the requested query contained the field "%s".`, field.Name),
+				At(field.Position),
+				Nodes(field),
+				Severity(severity),
+			)
+		})
+	}}
+}
+
+// MayUseIntrospection is a cheap check of a query before it is parsed, reporting whether it could
+// select __schema or __type. It can return true for queries that only mention those names in a
+// string or a comment, but never returns false for a query using introspection, so servers can
+// skip the NoIntrospection rule for every query it returns false for.
+func MayUseIntrospection(query string) bool {
+	for _, name := range []string{"__schema", "__type"} {
+		for offset := 0; ; {
+			i := strings.Index(query[offset:], name)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(name)
+			if (start == 0 || !isNameByte(query[start-1])) && (end == len(query) || !isNameByte(query[end])) {
+				return true
+			}
+			offset = end
+		}
+	}
+	return false
+}
+
+func isIntrospectionField(name string) bool {
+	return name == "__schema" || name == "__type"
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -18,11 +18,13 @@ func init() {
 				addError(
 					Message(`Variable "%s" is not defined by operation "%s".`, value, walker.CurrentOperation.Name),
 					At(value.Position),
+					Nodes(value),
 				)
 			} else {
 				addError(
 					Message(`Variable "%s" is not defined.`, value),
 					At(value.Position),
+					Nodes(value),
 				)
 			}
 		})
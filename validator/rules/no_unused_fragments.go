@@ -24,6 +24,7 @@ func init() {
 				addError(
 					Message(`Fragment "%s" is never used.`, fragment.Name),
 					At(fragment.Position),
+					Nodes(fragment),
 				)
 			}
 		})
@@ -1,7 +1,11 @@
 package validator
 
 import (
+	"fmt"
+
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/lexer"
 
 	//nolint:revive // Validator rules each use dot imports for convenience.
 	. "github.com/vektah/gqlparser/v2/validator"
@@ -9,22 +13,37 @@ import (
 
 func init() {
 	AddRule("NoUnusedVariables", func(observers *Events, addError AddErrFunc) {
+		var sources lexer.SourceTokens
 		observers.OnOperation(func(walker *Walker, operation *ast.OperationDefinition) {
+			var edits []gqlerror.TextEdit
+			used := 0
 			for _, varDef := range operation.VariableDefinitions {
+				if varDef.Used {
+					used++
+				}
+			}
+			if used < len(operation.VariableDefinitions) {
+				edits = removeVariableDefinitions(&sources, operation.VariableDefinitions)
+			}
+
+			for i, varDef := range operation.VariableDefinitions {
 				if varDef.Used {
 					continue
 				}
 
+				options := []ErrorOption{At(varDef.Position), Nodes(varDef)}
+				switch {
+				case edits == nil:
+				case used == 0 && len(operation.VariableDefinitions) > 1:
+					options = append(options, Fix("Remove all variables", edits[i]))
+				default:
+					options = append(options, Fix(fmt.Sprintf(`Remove "$%s"`, varDef.Variable), edits[i]))
+				}
+
 				if operation.Name != "" {
-					addError(
-						Message(`Variable "$%s" is never used in operation "%s".`, varDef.Variable, operation.Name),
-						At(varDef.Position),
-					)
+					addError(append(options, Message(`Variable "$%s" is never used in operation "%s".`, varDef.Variable, operation.Name))...)
 				} else {
-					addError(
-						Message(`Variable "$%s" is never used.`, varDef.Variable),
-						At(varDef.Position),
-					)
+					addError(append(options, Message(`Variable "$%s" is never used.`, varDef.Variable))...)
 				}
 			}
 		})
@@ -200,6 +200,8 @@ type ConflictMessage struct {
 	Names        []string
 	SubMessage   []*ConflictMessage
 	Position     *ast.Position
+	// Fields holds the two conflicting fields, Position is the position of the second one
+	Fields [2]*ast.Field
 }
 
 func (m *ConflictMessage) String(buf *bytes.Buffer) {
@@ -225,6 +227,7 @@ func (m *ConflictMessage) addFieldsConflictMessage(addError AddErrFunc) {
 	addError(
 		Message(`Fields "%s" conflict because %s. Use different aliases on the fields to fetch both if this was intentional.`, m.ResponseName, buf.String()),
 		At(m.Position),
+		Nodes(m.Fields[0], m.Fields[1]),
 	)
 }
 
@@ -438,6 +441,7 @@ func (m *overlappingFieldsCanBeMergedManager) findConflict(parentFieldsAreMutual
 				ResponseName: fieldNameA,
 				Message:      fmt.Sprintf(`"%s" and "%s" are different fields`, fieldA.Name, fieldB.Name),
 				Position:     fieldB.Position,
+				Fields:       [2]*ast.Field{fieldA, fieldB},
 			}
 		}
 
@@ -447,6 +451,7 @@ func (m *overlappingFieldsCanBeMergedManager) findConflict(parentFieldsAreMutual
 				ResponseName: fieldNameA,
 				Message:      "they have differing arguments",
 				Position:     fieldB.Position,
+				Fields:       [2]*ast.Field{fieldA, fieldB},
 			}
 		}
 	}
@@ -456,6 +461,7 @@ func (m *overlappingFieldsCanBeMergedManager) findConflict(parentFieldsAreMutual
 			ResponseName: fieldNameA,
 			Message:      fmt.Sprintf(`they return conflicting types "%s" and "%s"`, fieldA.Definition.Type.String(), fieldB.Definition.Type.String()),
 			Position:     fieldB.Position,
+			Fields:       [2]*ast.Field{fieldA, fieldB},
 		}
 	}
 
@@ -470,6 +476,7 @@ func (m *overlappingFieldsCanBeMergedManager) findConflict(parentFieldsAreMutual
 		ResponseName: fieldNameA,
 		SubMessage:   conflicts.Conflicts,
 		Position:     fieldB.Position,
+		Fields:       [2]*ast.Field{fieldA, fieldB},
 	}
 }
 
@@ -50,6 +50,7 @@ func init() {
 				addError(
 					Message(`Fragment cannot be spread here as objects of type "%s" can never be of type "%s".`, inlineFragment.ObjectDefinition.Name, inlineFragment.TypeCondition),
 					At(inlineFragment.Position),
+					Nodes(inlineFragment),
 				)
 			})
 		})
@@ -62,6 +63,7 @@ func init() {
 				addError(
 					Message(`Fragment "%s" cannot be spread here as objects of type "%s" can never be of type "%s".`, fragmentSpread.Name, fragmentSpread.ObjectDefinition.Name, fragmentSpread.Definition.TypeCondition),
 					At(fragmentSpread.Position),
+					Nodes(fragmentSpread),
 				)
 			})
 		})
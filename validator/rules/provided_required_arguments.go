@@ -31,6 +31,7 @@ func init() {
 				addError(
 					Message(`Field "%s" argument "%s" of type "%s" is required, but it was not provided.`, field.Name, argDef.Name, argDef.Type.String()),
 					At(field.Position),
+					Nodes(field),
 				)
 			}
 		})
@@ -57,6 +58,7 @@ func init() {
 				addError(
 					Message(`Directive "@%s" argument "%s" of type "%s" is required, but it was not provided.`, directive.Definition.Name, argDef.Name, argDef.Type.String()),
 					At(directive.Position),
+					Nodes(directive),
 				)
 			}
 		})
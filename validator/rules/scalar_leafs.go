@@ -23,6 +23,7 @@ func init() {
 				addError(
 					Message(`Field "%s" must not have a selection since type "%s" has no subfields.`, field.Name, fieldType.Name),
 					At(field.Position),
+					Nodes(field),
 				)
 			}
 
@@ -31,6 +32,7 @@ func init() {
 					Message(`Field "%s" of type "%s" must have a selection of subfields.`, field.Name, field.Definition.Type.String()),
 					Suggestf(`"%s { ... }"`, field.Name),
 					At(field.Position),
+					Nodes(field),
 				)
 			}
 		})
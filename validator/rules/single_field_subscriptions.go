@@ -28,6 +28,7 @@ func init() {
 				addError(
 					Message(`%s must select only one top level field.`, name),
 					At(fields[1].position),
+					Nodes(fields[1].node),
 				)
 			}
 
@@ -36,6 +37,7 @@ func init() {
 					addError(
 						Message(`%s must not select an introspection top level field.`, name),
 						At(field.position),
+						Nodes(field.node),
 					)
 				}
 			}
@@ -46,6 +48,7 @@ func init() {
 type topField struct {
 	name     string
 	position *ast.Position
+	node     *ast.Field
 }
 
 func retrieveTopFieldNames(selectionSet ast.SelectionSet) []*topField {
@@ -59,6 +62,7 @@ func retrieveTopFieldNames(selectionSet ast.SelectionSet) []*topField {
 				fields = append(fields, &topField{
 					name:     selection.Name,
 					position: selection.GetPosition(),
+					node:     selection,
 				})
 			case *ast.InlineFragment:
 				walk(selection.SelectionSet)
@@ -27,6 +27,7 @@ func checkUniqueArgs(args ast.ArgumentList, addError AddErrFunc) {
 			addError(
 				Message(`There can be only one argument named "%s".`, arg.Name),
 				At(arg.Position),
+				Nodes(arg),
 			)
 		}
 
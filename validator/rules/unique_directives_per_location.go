@@ -17,6 +17,7 @@ func init() {
 					addError(
 						Message(`The directive "@%s" can only be used once at this location.`, dir.Name),
 						At(dir.Position),
+						Nodes(dir),
 					)
 				}
 				seen[dir.Name] = true
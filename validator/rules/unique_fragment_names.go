@@ -16,6 +16,7 @@ func init() {
 				addError(
 					Message(`There can be only one fragment named "%s".`, fragment.Name),
 					At(fragment.Position),
+					Nodes(fragment),
 				)
 			}
 			seenFragments[fragment.Name] = true
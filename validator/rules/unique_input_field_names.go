@@ -20,6 +20,7 @@ func init() {
 					addError(
 						Message(`There can be only one input field named "%s".`, field.Name),
 						At(field.Position),
+						Nodes(field),
 					)
 				}
 				seen[field.Name] = true
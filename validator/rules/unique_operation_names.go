@@ -16,6 +16,7 @@ func init() {
 				addError(
 					Message(`There can be only one operation named "%s".`, operation.Name),
 					At(operation.Position),
+					Nodes(operation),
 				)
 			}
 			seen[operation.Name] = true
@@ -17,6 +17,7 @@ func init() {
 					addError(
 						Message(`There can be only one variable named "$%s".`, def.Variable),
 						At(def.Position),
+						Nodes(def),
 					)
 				}
 				seen[def.Variable]++
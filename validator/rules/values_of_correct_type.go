@@ -22,6 +22,7 @@ func init() {
 				addError(
 					Message(`Expected value of type "%s", found %s.`, value.ExpectedType.String(), value.String()),
 					At(value.Position),
+					Nodes(value),
 				)
 			}
 
@@ -70,6 +71,7 @@ func init() {
 						Message(`Enum "%s" cannot represent non-enum value: %s.`, value.ExpectedType.String(), value.String()),
 						SuggestListQuoted("Did you mean the enum value", rawValStr, possibleEnums),
 						At(value.Position),
+						Nodes(value),
 					)
 				} else if !value.Definition.OneOf("String", "ID") {
 					unexpectedTypeMessage(addError, value)
@@ -82,6 +84,7 @@ func init() {
 						unexpectedTypeMessageOnly(value),
 						SuggestListUnquoted("Did you mean the enum value", rawValStr, possibleEnums),
 						At(value.Position),
+						Nodes(value),
 					)
 				} else if value.Definition.EnumValues.ForName(value.Raw) == nil {
 					rawValStr := fmt.Sprint(rawVal)
@@ -89,6 +92,7 @@ func init() {
 						Message(`Value "%s" does not exist in "%s" enum.`, value.String(), value.ExpectedType.String()),
 						SuggestListQuoted("Did you mean the enum value", rawValStr, possibleEnums),
 						At(value.Position),
+						Nodes(value),
 					)
 				}
 
@@ -106,6 +110,7 @@ func init() {
 							addError(
 								Message(`Field "%s.%s" of required type "%s" was not provided.`, value.Definition.Name, field.Name, field.Type.String()),
 								At(value.Position),
+								Nodes(value),
 							)
 							continue
 						}
@@ -123,6 +128,7 @@ func init() {
 							Message(`Field "%s" is not defined by type "%s".`, fieldValue.Name, value.Definition.Name),
 							SuggestListQuoted("Did you mean", fieldValue.Name, suggestions),
 							At(fieldValue.Position),
+							Nodes(fieldValue),
 						)
 					}
 				}
@@ -141,6 +147,7 @@ func unexpectedTypeMessage(addError AddErrFunc, v *ast.Value) {
 	addError(
 		unexpectedTypeMessageOnly(v),
 		At(v.Position),
+		Nodes(v),
 	)
 }
 
@@ -22,6 +22,7 @@ func init() {
 							def.Type.String(),
 						),
 						At(def.Position),
+						Nodes(def),
 					)
 				}
 			}
@@ -33,6 +33,7 @@ func init() {
 						value.ExpectedType.String(),
 					),
 					At(value.Position),
+					Nodes(value),
 				)
 			}
 		})
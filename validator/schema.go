@@ -20,6 +20,15 @@ func LoadSchema(inputs ...*Source) (*Schema, error) {
 }
 
 func ValidateSchemaDocument(sd *SchemaDocument) (*Schema, error) {
+	schema, err := validateSchemaDocument(sd)
+	if err != nil {
+		err.SetCode(gqlerror.CodeSchemaInvalid)
+		return nil, err
+	}
+	return schema, nil
+}
+
+func validateSchemaDocument(sd *SchemaDocument) (*Schema, *gqlerror.Error) {
 	schema := Schema{
 		Types:         map[string]*Definition{},
 		Directives:    map[string]*DirectiveDefinition{},
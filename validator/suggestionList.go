@@ -10,13 +10,17 @@ import (
 
 // Given an invalid input string and a list of valid options, returns a filtered
 // list of valid options sorted based on their similarity with the input.
+//
+// Options with the same similarity are sorted alphabetically, so the result is
+// stable regardless of the order options are given in. Use it together with
+// DidYouMean to report suggestions the same way the built in rules do.
 func SuggestionList(input string, options []string) []string {
 	var results []string
 	optionsByDistance := map[string]int{}
+	threshold := calcThreshold(input)
 
 	for _, option := range options {
-		distance := lexicalDistance(input, option)
-		threshold := calcThreshold(input)
+		distance := LexicalDistance(input, option)
 		if distance <= threshold {
 			results = append(results, option)
 			optionsByDistance[option] = distance
@@ -24,7 +28,10 @@ func SuggestionList(input string, options []string) []string {
 	}
 
 	sort.Slice(results, func(i, j int) bool {
-		return optionsByDistance[results[i]] < optionsByDistance[results[j]]
+		if diff := optionsByDistance[results[i]] - optionsByDistance[results[j]]; diff != 0 {
+			return diff < 0
+		}
+		return results[i] < results[j]
 	})
 	return results
 }
@@ -52,7 +59,7 @@ func calcThreshold(a string) (threshold int) {
 // of 1.
 //
 // This distance can be useful for detecting typos in input or sorting
-func lexicalDistance(a, b string) int {
+func LexicalDistance(a, b string) int {
 	if a == b {
 		return 0
 	}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestionList(t *testing.T) {
+	t.Run("returns results when input is empty", func(t *testing.T) {
+		assert.Equal(t, []string{"a"}, SuggestionList("", []string{"a"}))
+	})
+
+	t.Run("returns empty array when there are no options", func(t *testing.T) {
+		assert.Empty(t, SuggestionList("input", []string{}))
+	})
+
+	t.Run("returns options with small lexical distance", func(t *testing.T) {
+		assert.Equal(t, []string{"greenish"}, SuggestionList("greenish", []string{"greenish"}))
+		assert.Equal(t, []string{"greenish"}, SuggestionList("green", []string{"greenish"}))
+	})
+
+	t.Run("rejects options with distance that exceeds threshold", func(t *testing.T) {
+		assert.Equal(t, []string{"aaab", "aabb"}, SuggestionList("aaaa", []string{"aaab", "aabb", "abbb"}))
+		assert.Empty(t, SuggestionList("ab", []string{"ca"}))
+	})
+
+	t.Run("returns options with different case", func(t *testing.T) {
+		assert.Equal(t, []string{"VERYLONGSTRING"}, SuggestionList("verylongstring", []string{"VERYLONGSTRING"}))
+		assert.Equal(t, []string{"verylongstring"}, SuggestionList("VERYLONGSTRING", []string{"verylongstring"}))
+	})
+
+	t.Run("returns options sorted based on lexical distance", func(t *testing.T) {
+		assert.Equal(t, []string{"abc", "ab", "a"}, SuggestionList("abc", []string{"a", "ab", "abc"}))
+	})
+
+	t.Run("returns options with the same lexical distance sorted alphabetically", func(t *testing.T) {
+		assert.Equal(t, []string{"a", "b", "c"}, SuggestionList("a", []string{"b", "c", "a"}))
+	})
+}
+
+func TestLexicalDistance(t *testing.T) {
+	assert.Equal(t, 0, LexicalDistance("name", "name"))
+	assert.Equal(t, 1, LexicalDistance("name", "Name"))
+	assert.Equal(t, 1, LexicalDistance("name", "nme"))
+	assert.Equal(t, 2, LexicalDistance("name", "nmae"))
+}
@@ -8,22 +8,43 @@ import (
 
 type AddErrFunc func(options ...ErrorOption)
 
-type ruleFunc func(observers *Events, addError AddErrFunc)
+type RuleFunc func(observers *Events, addError AddErrFunc)
 
-type rule struct {
-	name string
-	rule ruleFunc
+// Rule is a named validation rule. Rules that need configuration, such as limits, are not
+// registered with AddRule but built per use and passed to ValidateWithRules.
+type Rule struct {
+	Name     string
+	RuleFunc RuleFunc
 }
 
-var rules []rule
+var rules []Rule
 
 // addRule to rule set.
 // f is called once each time `Validate` is executed.
-func AddRule(name string, f ruleFunc) {
-	rules = append(rules, rule{name: name, rule: f})
+func AddRule(name string, f RuleFunc) {
+	rules = append(rules, Rule{Name: name, RuleFunc: f})
 }
 
+// Validate runs every registered rule against doc and returns the errors found. Warnings and
+// notices, such as the use of deprecated fields, are left out, use ValidateDiagnostics to get
+// those too.
 func Validate(schema *Schema, doc *QueryDocument) gqlerror.List {
+	return ValidateDiagnostics(schema, doc).Errors
+}
+
+// ValidateDiagnostics runs every registered rule against doc and returns everything reported,
+// split by severity.
+func ValidateDiagnostics(schema *Schema, doc *QueryDocument) gqlerror.Diagnostics {
+	return gqlerror.NewDiagnostics(validate(schema, doc, nil))
+}
+
+// ValidateWithRules runs every registered rule, followed by extra, against doc and returns
+// everything reported, split by severity. The errors are in the Errors field of the result.
+func ValidateWithRules(schema *Schema, doc *QueryDocument, extra ...Rule) gqlerror.Diagnostics {
+	return gqlerror.NewDiagnostics(validate(schema, doc, extra))
+}
+
+func validate(schema *Schema, doc *QueryDocument, extra []Rule) gqlerror.List {
 	var errs gqlerror.List
 	if schema == nil {
 		errs = append(errs, gqlerror.Errorf("cannot validate as Schema is nil"))
@@ -32,18 +53,24 @@ func Validate(schema *Schema, doc *QueryDocument) gqlerror.List {
 		errs = append(errs, gqlerror.Errorf("cannot validate as QueryDocument is nil"))
 	}
 	if len(errs) > 0 {
+		for _, err := range errs {
+			err.SetCode(gqlerror.CodeValidationFailed)
+		}
 		return errs
 	}
 	observers := &Events{}
-	for i := range rules {
-		rule := rules[i]
-		rule.rule(observers, func(options ...ErrorOption) {
+	for _, rule := range append(rules[:len(rules):len(rules)], extra...) {
+		rule := rule
+		rule.RuleFunc(observers, func(options ...ErrorOption) {
 			err := &gqlerror.Error{
-				Rule: rule.name,
+				Rule: rule.Name,
 			}
 			for _, o := range options {
 				o(err)
 			}
+			if err.Code() == "" {
+				err.SetCode(gqlerror.RuleCode(rule.Name))
+			}
 			errs = append(errs, err)
 		})
 	}
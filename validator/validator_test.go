@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 	"github.com/vektah/gqlparser/v2/parser"
 	"github.com/vektah/gqlparser/v2/validator"
 )
@@ -73,7 +74,7 @@ query SomeOperation {
 	require.NoError(t, err)
 	r1 := validator.Validate(s, q1)
 	require.Len(t, r1, 1)
-	const errorString = `SomeOperation:4: Field "myAction" argument "myEnum" of type "Locale!" is required, but it was not provided.`
+	const errorString = `SomeOperation:4:2: Field "myAction" argument "myEnum" of type "Locale!" is required, but it was not provided.`
 	require.EqualError(t, r1[0], errorString)
 
 	// Some other call that should not affect validator behavior
@@ -138,3 +139,228 @@ func TestNoUnusedVariables(t *testing.T) {
 		require.Nil(t, validator.Validate(s, q))
 	})
 }
+
+func TestErrorCodes(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Name: "graph/schema.graphqls", Input: `
+		type Query {
+			bar: String!
+			baz(id: Int): String!
+		}
+	`})
+
+	t.Run("validation errors use the rule name", func(t *testing.T) {
+		q, err := parser.ParseQuery(&ast.Source{Input: `{ qux }`})
+		require.NoError(t, err)
+
+		errs := validator.Validate(s, q)
+		require.Len(t, errs, 1)
+		require.Equal(t, "FIELDS_ON_CORRECT_TYPE", errs[0].Code())
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		_, errs := gqlparser.LoadQuery(s, `{ bar `)
+		require.Len(t, errs, 1)
+		require.Equal(t, gqlerror.CodeParseFailed, errs[0].Code())
+	})
+
+	t.Run("schema errors", func(t *testing.T) {
+		_, err := gqlparser.LoadSchema(&ast.Source{Input: `type Query { bar: Baz }`})
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, err, &gqlErr)
+		require.Equal(t, gqlerror.CodeSchemaInvalid, gqlErr.Code())
+	})
+
+	t.Run("variable errors", func(t *testing.T) {
+		q := gqlparser.MustLoadQuery(s, `query($id: Int!) { baz(id: $id) }`)
+		_, err := validator.VariableValues(s, q.Operations[0], nil)
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, err, &gqlErr)
+		require.Equal(t, gqlerror.CodeBadUserInput, gqlErr.Code())
+	})
+}
+
+func TestErrorSourceNames(t *testing.T) {
+	t.Run("schema errors name the file they came from", func(t *testing.T) {
+		_, err := gqlparser.LoadSchema(
+			&ast.Source{Name: "query.graphql", Input: "type Query {\n  user: User\n}"},
+			&ast.Source{Name: "users.graphql", Input: "type User {\n  id: ID!\n  friend: Friend\n}"},
+		)
+
+		var gqlErr *gqlerror.Error
+		require.ErrorAs(t, err, &gqlErr)
+		require.Equal(t, "users.graphql", gqlErr.Extensions["file"])
+		require.Equal(t, []gqlerror.Location{{Line: 3, Column: 11}}, gqlErr.Locations)
+	})
+
+	t.Run("validation errors honor the location offset", func(t *testing.T) {
+		s := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { user: String }`})
+		q, err := parser.ParseQuery(&ast.Source{
+			Name:           "main.go",
+			Input:          "{\n  usr\n}",
+			LocationOffset: ast.LocationOffset{Line: 14, Column: 20},
+		})
+		require.NoError(t, err)
+
+		errs := validator.Validate(s, q)
+		require.Len(t, errs, 1)
+		require.Equal(t, "main.go", errs[0].Extensions["file"])
+		require.Equal(t, []gqlerror.Location{{Line: 15, Column: 3}}, errs[0].Locations)
+	})
+}
+
+func TestErrorNodes(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `type Query { user(id: ID): String }`})
+
+	t.Run("unknown field", func(t *testing.T) {
+		q, err := parser.ParseQuery(&ast.Source{Input: `{ usr }`})
+		require.NoError(t, err)
+
+		errs := validator.Validate(s, q)
+		require.Len(t, errs, 1)
+		require.Equal(t, []interface{}{q.Operations[0].SelectionSet[0]}, errs[0].Nodes())
+	})
+
+	t.Run("unknown argument", func(t *testing.T) {
+		q, err := parser.ParseQuery(&ast.Source{Input: `{ user(name: "a") }`})
+		require.NoError(t, err)
+
+		errs := validator.Validate(s, q)
+		require.Len(t, errs, 1)
+		field := q.Operations[0].SelectionSet[0].(*ast.Field)
+		require.Equal(t, []interface{}{field.Arguments[0]}, errs[0].Nodes())
+	})
+
+	t.Run("conflicting fields", func(t *testing.T) {
+		q, err := parser.ParseQuery(&ast.Source{Input: `{ a: user(id: 1) a: user(id: 2) }`})
+		require.NoError(t, err)
+
+		errs := validator.Validate(s, q)
+		require.Len(t, errs, 1)
+		sel := q.Operations[0].SelectionSet
+		require.Equal(t, []interface{}{sel[0], sel[1]}, errs[0].Nodes())
+	})
+}
+
+func TestValidateDiagnostics(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query {
+			user(id: ID, name: String @deprecated(reason: "Use id.")): String @deprecated
+			users(filter: Filter, role: Role): [String]
+		}
+		input Filter {
+			name: String
+			nick: String @deprecated(reason: "Use name.")
+		}
+		enum Role {
+			ADMIN
+			ROOT @deprecated(reason: "Use ADMIN.")
+		}
+	`})
+
+	q, err := parser.ParseQuery(&ast.Source{Input: `{
+		user(name: "a")
+		users(filter: {nick: "b"}, role: ROOT)
+		unknown
+	}`})
+	require.NoError(t, err)
+
+	d := validator.ValidateDiagnostics(s, q)
+	require.Len(t, d.Errors, 1)
+	require.Equal(t, `Cannot query field "unknown" on type "Query".`, d.Errors[0].Message)
+
+	var warnings []string
+	for _, w := range d.Warnings {
+		require.Equal(t, gqlerror.SeverityWarning, w.Severity())
+		warnings = append(warnings, w.Message)
+	}
+	require.Equal(t, []string{
+		`The field Query.user is deprecated. No longer supported`,
+		`Field "Query.user" argument "name" is deprecated. Use id.`,
+		`The input field Filter.nick is deprecated. Use name.`,
+		`The enum value "Role.ROOT" is deprecated. Use ADMIN.`,
+	}, warnings)
+
+	require.Equal(t, d.Errors, validator.Validate(s, q))
+}
+
+func TestFixes(t *testing.T) {
+	s := gqlparser.MustLoadSchema(&ast.Source{Input: `
+		type Query { user(id: ID): User }
+		type User { name: String email: String }
+	`})
+
+	fixed := func(t *testing.T, query string) []string {
+		t.Helper()
+		src := &ast.Source{Name: "query.graphql", Input: query}
+		doc, err := parser.ParseQuery(src)
+		require.NoError(t, err)
+
+		var result []string
+		for _, err := range validator.Validate(s, doc) {
+			for _, fix := range err.Fixes() {
+				out, applyErr := gqlerror.ApplyFixes(src.Input, fix.Edits)
+				require.NoError(t, applyErr)
+				result = append(result, fix.Message+": "+out)
+			}
+		}
+		return result
+	}
+
+	t.Run("misspelled fields", func(t *testing.T) {
+		require.Equal(t, []string{
+			`Replace with "name": { user { name } }`,
+		}, fixed(t, `{ user { nam } }`))
+		require.Equal(t, []string{
+			`Replace with "email": { user { mail: email } }`,
+		}, fixed(t, `{ user { mail: emial } }`))
+		require.Equal(t, []string{
+			`Replace with "name": { user { nam: name } }`,
+		}, fixed(t, `{ user { nam: nam } }`))
+		require.Empty(t, fixed(t, `{ user { zzz } }`))
+	})
+
+	t.Run("unused variables", func(t *testing.T) {
+		require.Equal(t, []string{
+			`Remove "$a": query Q { user { name } }`,
+		}, fixed(t, `query Q($a: ID) { user { name } }`))
+		require.Equal(t, []string{
+			`Remove "$a": query Q($b: ID) { user(id: $b) { name } }`,
+		}, fixed(t, `query Q($a: [ID] = ["1", "2"] @deprecated, $b: ID) { user(id: $b) { name } }`))
+		require.Equal(t, []string{
+			`Remove "$b": query Q($a: ID) { user(id: $a) { name } }`,
+		}, fixed(t, "query Q($a: ID, # unused\n$b: ID = \"x\") { user(id: $a) { name } }"))
+		require.Equal(t, []string{
+			`Remove all variables: query Q { user { name } }`,
+			`Remove all variables: query Q { user { name } }`,
+		}, fixed(t, `query Q($a: ID @deprecated(reason: "x"), $b: ID) { user { name } }`))
+	})
+
+	t.Run("every fix applied at once", func(t *testing.T) {
+		all := func(query string) string {
+			t.Helper()
+			src := &ast.Source{Name: "query.graphql", Input: query}
+			doc, err := parser.ParseQuery(src)
+			require.NoError(t, err)
+
+			var edits []gqlerror.TextEdit
+			for _, err := range validator.Validate(s, doc) {
+				for _, fix := range err.Fixes() {
+					edits = append(edits, fix.Edits...)
+				}
+			}
+			out, err := gqlerror.ApplyFixes(src.Input, edits)
+			require.NoError(t, err)
+			return out
+		}
+
+		require.Equal(t, `query Q($c: ID) { user(id: $c) { name } }`, all(`query Q($a: ID, $b: ID, $c: ID) { user(id: $c) { name } }`))
+		require.Equal(t, `query Q($a: ID) { user(id: $a) { name } }`, all(`query Q($a: ID, $b: ID, $c: ID) { user(id: $a) { name } }`))
+		require.Equal(t, `query Q($b: ID) { user(id: $b) { name } }`, all(`query Q($a: ID, $b: ID, $c: ID) { user(id: $b) { name } }`))
+		require.Equal(t, `query Q($b: ID, $d: ID) { user(id: $b) { name } other: user(id: $d) { name } }`,
+			all(`query Q($a: ID, $b: ID, $c: ID, $d: ID, $e: ID) { user(id: $b) { name } other: user(id: $d) { name } }`))
+		require.Equal(t, `query Q { user { name } }`, all(`query Q($a: ID, $b: ID, $c: ID) { user { name } }`))
+		require.Equal(t, `query Q($a: ID) { user(id: $a) { name } }`, all(`query Q($a: ID, $b: ID, $c: ID) { user(id: $a) { nam } }`))
+	})
+}
@@ -15,6 +15,15 @@ var ErrUnexpectedType = fmt.Errorf("Unexpected Type")
 
 // VariableValues coerces and validates variable values
 func VariableValues(schema *ast.Schema, op *ast.OperationDefinition, variables map[string]interface{}) (map[string]interface{}, error) {
+	coercedVars, err := variableValues(schema, op, variables)
+	if err != nil {
+		err.SetCode(gqlerror.CodeBadUserInput)
+		return nil, err
+	}
+	return coercedVars, nil
+}
+
+func variableValues(schema *ast.Schema, op *ast.OperationDefinition, variables map[string]interface{}) (map[string]interface{}, *gqlerror.Error) {
 	coercedVars := map[string]interface{}{}
 
 	validator := varValidator{
@@ -124,7 +133,7 @@ func (v *varValidator) validateVarType(typ *ast.Type, val reflect.Value) (reflec
 	}
 	def := v.schema.Types[typ.NamedType]
 	if def == nil {
-		panic(fmt.Errorf("missing def for %s", typ.NamedType))
+		return val, gqlerror.ErrorPathf(v.path, "unknown type %s", typ.NamedType)
 	}
 
 	if !typ.NonNull && !val.IsValid() {
@@ -234,7 +243,7 @@ func (v *varValidator) validateVarType(typ *ast.Type, val reflect.Value) (reflec
 			val.SetMapIndex(reflect.ValueOf(fieldDef.Name), cval)
 		}
 	default:
-		panic(fmt.Errorf("unsupported type %s", def.Kind))
+		return val, gqlerror.ErrorPathf(v.path, "%s is not an input type", def.Name)
 	}
 	return val, nil
 }